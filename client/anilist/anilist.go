@@ -7,6 +7,7 @@ import (
 	"github.com/luevano/libmangal/metadata"
 	"github.com/luevano/libmangal/metadata/anilist"
 	"github.com/luevano/mangal/util/cache"
+	"github.com/luevano/mangal/util/httprec"
 	"golang.org/x/oauth2"
 )
 
@@ -26,6 +27,7 @@ func Anilist() *metadata.ProviderWithCache {
 
 func newAnilist() *metadata.ProviderWithCache {
 	aniOpts := anilist.DefaultOptions()
+	aniOpts.HTTPClient = httprec.Wrap(aniOpts.HTTPClient)
 
 	ani, err := anilist.NewAnilist(aniOpts)
 	if err != nil {
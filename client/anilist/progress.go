@@ -0,0 +1,102 @@
+package anilist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/luevano/mangal/util/cache"
+	"github.com/luevano/mangal/util/httprec"
+	"golang.org/x/oauth2"
+)
+
+const apiURL = "https://graphql.anilist.co"
+
+// queryProgress gets the list entry of the authenticated user, null if not in the list.
+const queryProgress = `
+query ($id: Int) {
+	Media (id: $id, type: MANGA) {
+		mediaListEntry {
+			progress
+		}
+	}
+}`
+
+var httpClient = httprec.Wrap(&http.Client{Timeout: time.Minute})
+
+// lastToken returns the access token of the last authenticated user.
+func lastToken() (string, bool, error) {
+	var userHistory cache.UserHistory
+	if _, err := cache.GetAuthHistory(cache.AnilistAuthHistory, &userHistory); err != nil {
+		return "", false, err
+	}
+	username := userHistory.Last()
+	if username == "" {
+		return "", false, nil
+	}
+	var token oauth2.Token
+	found, err := cache.GetAnilistAuthData(username, &token)
+	if err != nil || !found {
+		return "", false, err
+	}
+	return token.AccessToken, true, nil
+}
+
+// Progress returns the chapter progress of the manga in the list of the
+// authenticated user. Queried directly, as libmangal can only set it.
+//
+// False if there is no authenticated user or the manga isn't in their list.
+func Progress(ctx context.Context, id int) (int, bool, error) {
+	token, ok, err := lastToken()
+	if err != nil || !ok {
+		return 0, false, err
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"query":     queryProgress,
+		"variables": map[string]any{"id": id},
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return 0, false, err
+	}
+	defer res.Body.Close()
+
+	var response struct {
+		Data struct {
+			Media struct {
+				MediaListEntry *struct {
+					Progress int `json:"progress"`
+				} `json:"mediaListEntry"`
+			} `json:"Media"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return 0, false, fmt.Errorf("anilist progress: %s (status %s)", err.Error(), res.Status)
+	}
+	if len(response.Errors) > 0 {
+		return 0, false, errors.New("anilist progress: " + response.Errors[0].Message)
+	}
+	if entry := response.Data.Media.MediaListEntry; entry != nil {
+		return entry.Progress, true, nil
+	}
+	return 0, false, nil
+}
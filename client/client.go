@@ -10,9 +10,12 @@ import (
 	"github.com/luevano/libmangal"
 	"github.com/luevano/mangal/client/anilist"
 	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/log"
+	"github.com/luevano/mangal/metrics"
 	"github.com/luevano/mangal/provider/manager"
 	"github.com/luevano/mangal/template"
 	"github.com/luevano/mangal/util/afs"
+	"github.com/luevano/mangal/util/httprec"
 	"github.com/samber/lo"
 	"github.com/zyedidia/generic/queue"
 )
@@ -75,7 +78,8 @@ func NewClient(ctx context.Context, loader libmangal.ProviderLoader) (*libmangal
 	defer m.Unlock()
 
 	HTTPClient := &http.Client{
-		Timeout: time.Minute,
+		Timeout:   time.Minute,
+		Transport: log.Transport(loader.Info().ID, metrics.Transport(loader.Info().ID, httprec.Transport(nil))),
 	}
 
 	options := libmangal.DefaultClientOptions()
@@ -89,7 +93,7 @@ func NewClient(ctx context.Context, loader libmangal.ProviderLoader) (*libmangal
 	options.VolumeName = template.Volume
 	options.ChapterName = template.Chapter
 
-	client, err := libmangal.NewClient(ctx, loader, options)
+	client, err := libmangal.NewClient(ctx, imageLoader{searchLoader{loader}}, options)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,229 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/luevano/libmangal"
+	"github.com/luevano/libmangal/mangadata"
+	"github.com/luevano/libmangal/metadata"
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/log"
+	"github.com/luevano/mangal/provider/manager"
+	"github.com/luevano/mangal/util/chapter"
+	stringutil "github.com/luevano/mangal/util/string"
+	"github.com/samber/lo"
+)
+
+// Fallback downloads chapters that failed on their own provider
+// from the next provider in its chain, the one of the series if
+// there is a per series chain for it, else the default chain.
+//
+// The same manga is matched on the fallback providers through the shared
+// metadata ID and the chapter through its number. Found chapters are kept
+// so that the search is only done once per provider.
+type Fallback struct {
+	providers []string
+	chains    []config.FallbackChain
+	chapters  map[string][]mangadata.Chapter
+}
+
+// NewFallback creates a new Fallback with the given default chain of
+// provider IDs and the per series chains.
+func NewFallback(providers []string, chains []config.FallbackChain) *Fallback {
+	return &Fallback{
+		providers: providers,
+		chains:    chains,
+		chapters:  make(map[string][]mangadata.Chapter),
+	}
+}
+
+// Enabled returns true if there is at least one provider to fallback to.
+func (f *Fallback) Enabled() bool {
+	return len(f.providers) != 0 || len(f.chains) != 0
+}
+
+// chain returns the provider IDs to fallback to for the manga.
+func (f *Fallback) chain(manga mangadata.Manga) []string {
+	titles := []string{manga.Info().Title}
+	if meta := manga.Metadata(); meta != nil {
+		titles = append(titles, meta.Title())
+	}
+	for _, chain := range f.chains {
+		if chain.Matches(titles...) {
+			return chain.Providers
+		}
+	}
+	return f.providers
+}
+
+// Download tries to download the failed chapter with each of the fallback providers,
+// in order, until one succeeds. The chapter's Down, Err, Source and Fallback fields are updated.
+//
+// The original error is kept if none of the providers could download the chapter.
+func (f *Fallback) Download(ctx context.Context, ch *chapter.Chapter, options libmangal.DownloadOptions) {
+	manga := ch.Chapter.Volume().Manga()
+	providers := f.chain(manga)
+	if len(providers) == 0 {
+		return
+	}
+	id, ok := seriesID(manga.Metadata())
+	if !ok {
+		log.Log("can't fallback for chapter %q: manga %q has no metadata ID to match with", ch.Chapter, manga)
+		return
+	}
+
+	// The fallback manga must end up in the same place as the original,
+	// use the same (already searched) metadata
+	options.SearchMetadata = false
+	number := ch.Chapter.Info().Number
+	for _, provider := range providers {
+		if provider == ch.Source {
+			continue
+		}
+
+		fallbackChapter, err := f.findChapter(ctx, provider, id, manga, number)
+		if err != nil {
+			log.Log("fallback provider %q: %s", provider, err.Error())
+			continue
+		}
+
+		c, err := getOrNewClientByID(ctx, provider)
+		if err != nil {
+			log.Log("fallback provider %q: %s", provider, err.Error())
+			continue
+		}
+
+		down, err := c.DownloadChapter(ctx, fallbackChapter, options)
+		if err != nil {
+			log.Log("fallback provider %q: error downloading chapter %q: %s", provider, fallbackChapter, err.Error())
+			continue
+		}
+
+		log.Log("downloaded chapter %q with fallback provider %q", ch.Chapter, provider)
+		ch.Down = down
+		ch.Err = nil
+		ch.Source = provider
+		ch.Fallback = true
+		return
+	}
+}
+
+// findChapter returns the chapter with the same number of the manga matching
+// the metadata ID on the provider.
+func (f *Fallback) findChapter(ctx context.Context, provider string, id metadata.ID, manga mangadata.Manga, number float32) (mangadata.Chapter, error) {
+	c, err := getOrNewClientByID(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprint(provider, "-", id.Code, id.Raw)
+	chapters, ok := f.chapters[key]
+	if !ok {
+		fallbackManga, err := f.findManga(ctx, c, id, manga)
+		if err != nil {
+			return nil, err
+		}
+
+		volumes, err := c.MangaVolumes(ctx, fallbackManga)
+		if err != nil {
+			return nil, err
+		}
+		for _, volume := range volumes {
+			volumeChapters, err := c.VolumeChapters(ctx, volume)
+			if err != nil {
+				return nil, err
+			}
+			chapters = append(chapters, volumeChapters...)
+		}
+		f.chapters[key] = chapters
+	}
+
+	found, ok := lo.Find(chapters, func(c mangadata.Chapter) bool {
+		return c.Info().Number == number
+	})
+	if !ok {
+		return nil, fmt.Errorf("chapter %s not found for manga %q", stringutil.FormatFloa32(number), manga)
+	}
+	return found, nil
+}
+
+// findManga searches the manga on the provider and returns
+// the first result that matches the metadata ID.
+func (f *Fallback) findManga(ctx context.Context, c *libmangal.Client, id metadata.ID, manga mangadata.Manga) (mangadata.Manga, error) {
+	query := manga.Info().Title
+	if meta := manga.Metadata(); meta != nil && meta.Title() != "" {
+		query = meta.Title()
+	}
+
+	mangas, err := c.SearchMangas(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range mangas {
+		if !matchesID(ctx, c, id, m) {
+			continue
+		}
+		// use the same metadata so that the paths/metadata files are the same
+		m.SetMetadata(manga.Metadata())
+		return m, nil
+	}
+	return nil, fmt.Errorf("no manga found matching %s id %q with query %q", id.Code, id.Raw, query)
+}
+
+// matchesID checks if the manga metadata contains the ID, else the
+// metadata is searched with the corresponding metadata provider.
+func matchesID(ctx context.Context, c *libmangal.Client, id metadata.ID, manga mangadata.Manga) bool {
+	if meta := manga.Metadata(); meta != nil {
+		for _, i := range append([]metadata.ID{meta.ID()}, meta.ExtraIDs()...) {
+			if i.Source == id.Source && i.Raw == id.Raw {
+				return true
+			}
+		}
+	}
+
+	provider, err := c.GetMetadataProvider(id.Source)
+	if err != nil {
+		return false
+	}
+	meta, found, err := c.SearchByManga(ctx, provider, manga)
+	if err != nil || !found {
+		return false
+	}
+	return meta.ID().Source == id.Source && meta.ID().Raw == id.Raw
+}
+
+// seriesID returns the first non-provider metadata ID, which is shared across providers.
+func seriesID(meta metadata.Metadata) (metadata.ID, bool) {
+	if meta == nil {
+		return metadata.ID{}, false
+	}
+	for _, id := range append([]metadata.ID{meta.ID()}, meta.ExtraIDs()...) {
+		if id.Source != metadata.IDSourceProvider && id.Source != 0 && id.Raw != "" {
+			return id, true
+		}
+	}
+	return metadata.ID{}, false
+}
+
+// getOrNewClientByID returns the existing client for the provider or creates a new one.
+func getOrNewClientByID(ctx context.Context, provider string) (*libmangal.Client, error) {
+	loaders, err := manager.Loaders()
+	if err != nil {
+		return nil, err
+	}
+
+	loader, ok := lo.Find(loaders, func(loader libmangal.ProviderLoader) bool {
+		return loader.Info().ID == provider
+	})
+	if !ok {
+		return nil, errors.New("provider not found")
+	}
+
+	if c := Get(loader); c != nil {
+		return c, nil
+	}
+	return NewClient(ctx, loader)
+}
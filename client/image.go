@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"slices"
+
+	"github.com/luevano/libmangal"
+	"github.com/luevano/libmangal/mangadata"
+	"github.com/luevano/mangal/config"
+	imageutil "github.com/luevano/mangal/util/image"
+	"golang.org/x/sync/errgroup"
+)
+
+// imageLoader loads the provider with the page images transformed as set
+// in the config (see config.ImageOptions), done on the chapter pages instead of
+// libmangal.DownloadOptions.ImageTransformer as the transformations can
+// change the page extension and split a page in two.
+type imageLoader struct {
+	libmangal.ProviderLoader
+}
+
+func (l imageLoader) Load(ctx context.Context) (libmangal.Provider, error) {
+	provider, err := l.ProviderLoader.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return imageProvider{provider}, nil
+}
+
+type imageProvider struct {
+	libmangal.Provider
+}
+
+// ChapterPages downloads and transforms the page images when
+// any transformation is enabled.
+func (p imageProvider) ChapterPages(ctx context.Context, chapter mangadata.Chapter) ([]mangadata.Page, error) {
+	pages, err := p.Provider.ChapterPages(ctx, chapter)
+	if err != nil {
+		return nil, err
+	}
+	options := config.ImageOptions()
+	if !options.Enabled() {
+		return pages, nil
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(int(config.Providers.Parallelism.Get()), 1))
+	transformed := make([][]mangadata.Page, len(pages))
+	for i, page := range pages {
+		g.Go(func() error {
+			var image []byte
+			if withImage, ok := page.(mangadata.PageWithImage); ok {
+				image = withImage.Image()
+			} else {
+				var err error
+				if image, err = p.Provider.GetPageImage(ctx, page); err != nil {
+					return err
+				}
+			}
+
+			images, err := imageutil.Transform(image, options)
+			if err != nil {
+				return err
+			}
+			for _, image := range images {
+				transformed[i] = append(transformed[i], &transformedPage{
+					Page:      page,
+					image:     image.Data,
+					extension: image.Format.Extension(),
+				})
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return slices.Concat(transformed...), nil
+}
+
+var _ mangadata.PageWithImage = (*transformedPage)(nil)
+
+// transformedPage is a page with the transformed image, which libmangal uses
+// instead of downloading it.
+type transformedPage struct {
+	mangadata.Page
+	image     []byte
+	extension string
+}
+
+func (p *transformedPage) Extension() string {
+	return p.extension
+}
+
+func (p *transformedPage) Image() []byte {
+	return p.image
+}
+
+func (p *transformedPage) SetImage(image []byte) {
+	p.image = image
+}
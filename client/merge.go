@@ -0,0 +1,354 @@
+package client
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/luevano/libmangal"
+	"github.com/luevano/libmangal/mangadata"
+	"github.com/luevano/libmangal/metadata"
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/template"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/spf13/afero"
+)
+
+// MergeFormats are the formats that support merging chapters,
+// EPUB is not supported by libmangal.
+var MergeFormats = []libmangal.Format{
+	libmangal.FormatCBZ,
+	libmangal.FormatZIP,
+	libmangal.FormatPDF,
+}
+
+// Merged is the information of the merged chapters file.
+type Merged struct {
+	Filename  string                  `json:"filename"`
+	Directory string                  `json:"directory"`
+	Chapters  []float32               `json:"chapters"`
+	Status    metadata.DownloadStatus `json:"status"`
+}
+
+// Path is the full path of the merged file.
+func (m *Merged) Path() string {
+	return filepath.Join(m.Directory, m.Filename)
+}
+
+// DownloadedChapter returns the downloaded information of one
+// of the merged chapters, pointing to the merged file.
+func (m *Merged) DownloadedChapter(chapter mangadata.Chapter) *metadata.DownloadedChapter {
+	return &metadata.DownloadedChapter{
+		Number:             chapter.Info().Number,
+		Title:              chapter.Info().Title,
+		Filename:           m.Filename,
+		Directory:          m.Directory,
+		ChapterStatus:      m.Status,
+		SeriesJSONStatus:   metadata.DownloadStatusSkip,
+		ComicInfoXMLStatus: metadata.DownloadStatusSkip,
+		CoverStatus:        metadata.DownloadStatusSkip,
+		BannerStatus:       metadata.DownloadStatusSkip,
+	}
+}
+
+// MergeVolume downloads the chapters of the volume into a single file
+// named by the volume name template.
+func MergeVolume(
+	ctx context.Context,
+	client *libmangal.Client,
+	volume mangadata.Volume,
+	chapters []mangadata.Chapter,
+	options libmangal.DownloadOptions,
+) (*Merged, error) {
+	return merge(ctx, client, chapters, client.VolumeName(volume), options)
+}
+
+// MergeRange downloads the chapters into a single file
+// named by the merge name template.
+func MergeRange(
+	ctx context.Context,
+	client *libmangal.Client,
+	chapters []mangadata.Chapter,
+	options libmangal.DownloadOptions,
+) (*Merged, error) {
+	if len(chapters) == 0 {
+		return nil, fmt.Errorf("no chapters to merge")
+	}
+	from := chapters[0].Info().Number
+	to := chapters[len(chapters)-1].Info().Number
+	name := template.Merge(client.Info(), chapters[0].Volume().Manga(), from, to)
+	return merge(ctx, client, chapters, name, options)
+}
+
+// bookmark is the title of the chapter that starts at the page (0 based).
+type bookmark struct {
+	page  int
+	title string
+}
+
+// merge downloads the pages of the chapters and writes them into a single file,
+// with a bookmark at the start of each chapter, in the manga directory.
+//
+// Archives are written as each chapter is downloaded, PDFs need all the images
+// in memory. The file is written next to the final one and renamed when done.
+func merge(
+	ctx context.Context,
+	client *libmangal.Client,
+	chapters []mangadata.Chapter,
+	name string,
+	options libmangal.DownloadOptions,
+) (*Merged, error) {
+	if len(chapters) == 0 {
+		return nil, fmt.Errorf("no chapters to merge")
+	}
+	if !slices.Contains(MergeFormats, options.Format) {
+		return nil, fmt.Errorf("format %s doesn't support merging chapters, available: %v", options.Format, MergeFormats)
+	}
+
+	manga := chapters[0].Volume().Manga()
+	if options.SearchMetadata {
+		m, err := client.SearchMetadata(ctx, manga)
+		if err != nil {
+			return nil, err
+		}
+		manga.SetMetadata(m)
+	}
+	if err := metadata.Validate(manga.Metadata()); err != nil && options.Strict {
+		return nil, fmt.Errorf("no valid metadata for manga %q: %s", manga, err.Error())
+	}
+
+	// merged files are placed at the manga level,
+	// a range of chapters can span multiple volumes
+	directory := options.Directory
+	if options.CreateProviderDir {
+		directory = filepath.Join(directory, client.ProviderName(client.Info()))
+	}
+	if options.CreateMangaDir {
+		directory = filepath.Join(directory, client.MangaName(manga))
+	}
+
+	merged := &Merged{
+		Filename:  name + options.Format.Extension(),
+		Directory: directory,
+		Status:    metadata.DownloadStatusNew,
+	}
+	for _, chapter := range chapters {
+		merged.Chapters = append(merged.Chapters, chapter.Info().Number)
+	}
+
+	exists, err := afero.Exists(client.FS(), merged.Path())
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		if options.SkipIfExists {
+			merged.Status = metadata.DownloadStatusExists
+			return merged, nil
+		}
+		merged.Status = metadata.DownloadStatusOverwritten
+	}
+
+	if err := client.FS().MkdirAll(directory, config.Download.ModeDir.Get()); err != nil {
+		return nil, err
+	}
+	file, err := afero.TempFile(client.FS(), directory, "."+merged.Filename+".*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		file.Close()
+		// no-op once renamed
+		_ = client.FS().Remove(file.Name())
+	}()
+
+	var (
+		zipWriter *zip.Writer
+		images    [][]byte
+		bookmarks []bookmark
+		pages     int
+	)
+	if options.Format != libmangal.FormatPDF {
+		zipWriter = zip.NewWriter(file)
+	}
+	for _, chapter := range chapters {
+		chapterPages, err := client.ChapterPages(ctx, chapter)
+		if err != nil {
+			return nil, err
+		}
+		downloaded, err := client.DownloadPagesInBatch(ctx, chapterPages)
+		if err != nil {
+			return nil, err
+		}
+		bookmarks = append(bookmarks, bookmark{page: pages, title: chapterBookmark(chapter)})
+		for _, page := range downloaded {
+			image, err := options.ImageTransformer(page.Image())
+			if err != nil {
+				return nil, err
+			}
+			page.SetImage(image)
+
+			if zipWriter == nil {
+				images = append(images, image)
+			} else if err := writeMergedZIPPage(zipWriter, pages, page); err != nil {
+				return nil, err
+			}
+			pages++
+		}
+	}
+
+	switch options.Format {
+	case libmangal.FormatPDF:
+		err = writeMergedPDF(images, bookmarks, file)
+	case libmangal.FormatCBZ:
+		var comicInfo []byte
+		if options.WriteComicInfoXML && metadata.Validate(manga.Metadata()) == nil {
+			comicInfo, err = mergedComicInfoXML(manga, chapters, name, pages, bookmarks, options.ComicInfoXMLOptions)
+			if err != nil && options.Strict {
+				return nil, err
+			}
+		}
+		err = closeMergedZIP(zipWriter, comicInfo)
+	case libmangal.FormatZIP:
+		err = closeMergedZIP(zipWriter, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+	if err := client.FS().Chmod(file.Name(), config.Download.ModeFile.Get()); err != nil {
+		return nil, err
+	}
+	if err := client.FS().Rename(file.Name(), merged.Path()); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+func chapterBookmark(chapter mangadata.Chapter) string {
+	info := chapter.Info()
+	if info.Title == "" {
+		return fmt.Sprintf("Chapter %v", info.Number)
+	}
+	return fmt.Sprintf("Chapter %v: %s", info.Number, info.Title)
+}
+
+func writeMergedPDF(images [][]byte, bookmarks []bookmark, out io.Writer) error {
+	readers := make([]io.Reader, len(images))
+	for i, image := range images {
+		readers[i] = bytes.NewReader(image)
+	}
+
+	var pdf bytes.Buffer
+	if err := api.ImportImages(nil, &pdf, readers, nil, nil); err != nil {
+		return err
+	}
+	if len(bookmarks) == 0 {
+		_, err := io.Copy(out, &pdf)
+		return err
+	}
+
+	pdfBookmarks := make([]pdfcpu.Bookmark, len(bookmarks))
+	for i, b := range bookmarks {
+		pdfBookmarks[i] = pdfcpu.Bookmark{Title: b.title, PageFrom: b.page + 1}
+	}
+	return api.AddBookmarks(bytes.NewReader(pdf.Bytes()), out, pdfBookmarks, true, nil)
+}
+
+// writeMergedZIPPage writes the page image as the i-th (0 based) file of the archive.
+func writeMergedZIPPage(zipWriter *zip.Writer, i int, page mangadata.PageWithImage) error {
+	writer, err := zipWriter.CreateHeader(&zip.FileHeader{
+		Name:   fmt.Sprintf("%04d%s", i+1, page.Extension()),
+		Method: zip.Store,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(page.Image())
+	return err
+}
+
+// closeMergedZIP writes the ComicInfo.xml, if any, and closes the archive.
+func closeMergedZIP(zipWriter *zip.Writer, comicInfo []byte) error {
+	if comicInfo != nil {
+		writer, err := zipWriter.CreateHeader(&zip.FileHeader{
+			Name:   metadata.FilenameComicInfoXML,
+			Method: zip.Store,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := writer.Write(comicInfo); err != nil {
+			return err
+		}
+	}
+	return zipWriter.Close()
+}
+
+// comicInfoPages are the ComicInfo.xml page entries, only used for the bookmarks.
+type comicInfoPages struct {
+	XMLName xml.Name `xml:"Pages"`
+	Pages   []struct {
+		Image    int    `xml:"Image,attr"`
+		Bookmark string `xml:"Bookmark,attr"`
+	} `xml:"Page"`
+}
+
+// mergedComicInfoXML builds the ComicInfo.xml of the whole merged range,
+// with the start of each chapter as a bookmarked page.
+func mergedComicInfoXML(
+	manga mangadata.Manga,
+	chapters []mangadata.Chapter,
+	name string,
+	pages int,
+	bookmarks []bookmark,
+	options metadata.ComicInfoXMLOptions,
+) ([]byte, error) {
+	first := chapters[0].Info()
+	comicInfo := metadata.ToComicInfoXML(manga.Metadata(), metadata.Chapter{
+		Title:           name,
+		URL:             first.URL,
+		Number:          first.Number,
+		Date:            first.Date,
+		ScanlationGroup: first.ScanlationGroup,
+		Pages:           pages,
+	})
+	data, err := comicInfo.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var pagesInfo comicInfoPages
+	for _, b := range bookmarks {
+		pagesInfo.Pages = append(pagesInfo.Pages, struct {
+			Image    int    `xml:"Image,attr"`
+			Bookmark string `xml:"Bookmark,attr"`
+		}{b.page, b.title})
+	}
+	// libmangal's ComicInfo.xml has no pages, append them
+	pagesXML, err := xml.MarshalIndent(pagesInfo, "  ", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	const closing = "</ComicInfo>"
+	i := bytes.LastIndex(data, []byte(closing))
+	if i == -1 {
+		return nil, fmt.Errorf("unexpected ComicInfo.xml without closing tag")
+	}
+	var sb strings.Builder
+	sb.Write(data[:i])
+	sb.WriteString("  ")
+	sb.Write(pagesXML)
+	sb.WriteString("\n" + closing)
+	return []byte(sb.String()), nil
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"archive/zip"
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/luevano/libmangal/mangadata"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+// testPage only implements the methods used to write merged files.
+type testPage struct {
+	mangadata.PageWithImage
+	image []byte
+}
+
+func (p testPage) Image() []byte     { return p.image }
+func (p testPage) Extension() string { return ".png" }
+
+func testImage(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 10, 20))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestWriteMergedPDF(t *testing.T) {
+	img := testImage(t)
+	images := [][]byte{img, img, img, img}
+	chapters := []bookmark{{0, "Chapter 1"}, {2, "Chapter 2"}}
+
+	var buf bytes.Buffer
+	if err := writeMergedPDF(images, chapters, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	bookmarks, err := api.Bookmarks(bytes.NewReader(buf.Bytes()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bookmarks) != 2 {
+		t.Fatalf("got %d bookmarks, want 2", len(bookmarks))
+	}
+	for i, want := range []struct {
+		title string
+		page  int
+	}{{"Chapter 1", 1}, {"Chapter 2", 3}} {
+		if bookmarks[i].Title != want.title || bookmarks[i].PageFrom != want.page {
+			t.Errorf("bookmark %d = %q (page %d), want %q (page %d)",
+				i, bookmarks[i].Title, bookmarks[i].PageFrom, want.title, want.page)
+		}
+	}
+}
+
+func TestWriteMergedZIP(t *testing.T) {
+	page := testPage{image: testImage(t)}
+
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	for i := range 3 {
+		if err := writeMergedZIPPage(zipWriter, i, page); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := closeMergedZIP(zipWriter, []byte("<ComicInfo></ComicInfo>")); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	want := []string{"0001.png", "0002.png", "0003.png", "ComicInfo.xml"}
+	if len(names) != len(want) {
+		t.Fatalf("files = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("files = %v, want %v", names, want)
+			break
+		}
+	}
+}
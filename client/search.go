@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+
+	"github.com/luevano/libmangal"
+	"github.com/luevano/libmangal/mangadata"
+	"github.com/luevano/mangal/metrics"
+)
+
+// searchLoader loads the provider with its manga searches counted
+// in the metrics, for every caller of the client.
+type searchLoader struct {
+	libmangal.ProviderLoader
+}
+
+func (l searchLoader) Load(ctx context.Context) (libmangal.Provider, error) {
+	provider, err := l.ProviderLoader.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return searchProvider{provider, l.Info().ID}, nil
+}
+
+type searchProvider struct {
+	libmangal.Provider
+	id string
+}
+
+// SearchMangas counts the search of the provider.
+func (p searchProvider) SearchMangas(ctx context.Context, query string) ([]mangadata.Manga, error) {
+	metrics.Search(p.id)
+	return p.Provider.SearchMangas(ctx, query)
+}
@@ -4,6 +4,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/luevano/mangal/client/anilist"
 	anilistViewer "github.com/luevano/mangal/tui/model/anilist"
+	"github.com/luevano/mangal/tui/util"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +17,9 @@ var anilistCmd = &cobra.Command{
 	Short: "Anilist auth commands",
 	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, _ []string) {
+		if err := util.ValidateKeys(); err != nil {
+			errorf(cmd, err.Error())
+		}
 		if _, err := tea.NewProgram(anilistViewer.New(anilist.Anilist(), true)).Run(); err != nil {
 			errorf(cmd, err.Error())
 		}
@@ -6,9 +6,12 @@ import (
 
 	cc "github.com/ivanpirog/coloredcobra"
 	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/log"
 	"github.com/luevano/mangal/meta"
+	"github.com/luevano/mangal/theme"
 	"github.com/luevano/mangal/theme/icon"
 	"github.com/luevano/mangal/tui"
+	"github.com/luevano/mangal/util/httprec"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -42,7 +45,24 @@ func Execute() {
 	// Looks weird, it sets the same variable it uses for default and then path.ConfigDir
 	// will also read this set flag but it also creates the directory if doesn't exist.
 	rootCmd.PersistentFlags().StringVar(&config.Path, "config", config.Path, "Config file path")
-	cobra.OnInitialize(initConfig(rootCmd.PersistentFlags().Lookup("config")))
+	rootCmd.PersistentFlags().StringVar(&config.Profile, "profile", config.Profile, fmt.Sprintf("Config profile overlaying the config file (or %s env)", config.EnvProfile))
+	rootCmd.RegisterFlagCompletionFunc("profile", completionProfiles)
+	cobra.OnInitialize(initConfig(
+		rootCmd.PersistentFlags().Lookup("config"),
+		rootCmd.PersistentFlags().Lookup("profile"),
+	))
+
+	// -v is already used by the script vars
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Log at debug level, including the provider requests (see 'mangal logs')")
+	cobra.OnInitialize(initLog)
+
+	// HTTP fixtures, useful to run fully offline (CI)
+	rootCmd.PersistentFlags().StringVar(&httpArgs.Record, "http-record", "", "Record HTTP requests/responses into the cassette directory")
+	rootCmd.PersistentFlags().StringVar(&httpArgs.Replay, "http-replay", "", "Replay HTTP responses from the cassette directory, failing on unknown requests")
+	rootCmd.MarkFlagsMutuallyExclusive("http-record", "http-replay")
+	rootCmd.MarkPersistentFlagDirname("http-record")
+	rootCmd.MarkPersistentFlagDirname("http-replay")
+	cobra.OnInitialize(initHTTP)
 
 	if config.CLI.ColoredHelp.Get() {
 		cc.Init(&cc.Config{
@@ -63,15 +83,47 @@ func Execute() {
 	}
 }
 
-// re-reads the config from the changed flag
-func initConfig(flag *pflag.Flag) func() {
+var verbose bool
+
+// sets up the logger with the (re-read) config and the verbose flag
+func initLog() {
+	if err := log.Setup(verbose); err != nil {
+		errorf(rootCmd, err.Error())
+	}
+}
+
+var httpArgs = struct {
+	Record string
+	Replay string
+}{}
+
+// sets the HTTP record/replay mode from the flags
+func initHTTP() {
+	switch {
+	case httpArgs.Record != "":
+		httprec.Set(httprec.ModeRecord, httpArgs.Record)
+	case httpArgs.Replay != "":
+		httprec.Set(httprec.ModeReplay, httpArgs.Replay)
+	}
+}
+
+// re-reads the config from the changed flags
+func initConfig(pathFlag, profileFlag *pflag.Flag) func() {
 	return func() {
-		if flag.Changed {
-			if err := config.Load(config.Path); err != nil {
+		if pathFlag.Changed || profileFlag.Changed {
+			// keep the default lookup if only the profile changed
+			var path string
+			if pathFlag.Changed {
+				path = config.Path
+			}
+			if err := config.Load(path); err != nil {
 				panic(fmt.Errorf("error loading config from path %s: %s", config.Path, err.Error()))
 			}
 		}
-		// once the config finishes initializing (using custom config path or not), set the actual icon type
+		// once the config finishes initializing (using custom config path or not), set the actual icon type and theme
 		icon.SetType(config.Icons.Get())
+		if err := theme.Set(config.ThemesDir(), config.Theme.Get()); err != nil {
+			errorf(rootCmd, err.Error())
+		}
 	}
 }
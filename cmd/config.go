@@ -1,12 +1,17 @@
 package cmd
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/theme"
 	"github.com/luevano/mangal/theme/style"
+	"github.com/luevano/mangal/tui/util"
 	"github.com/spf13/cobra"
 )
 
@@ -41,9 +46,10 @@ var configInfoCmd = &cobra.Command{
 	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, _ []string) {
 		type configEntry struct {
-			Value       any    `json:"value"`
-			Default     any    `json:"default"`
-			Description string `json:"description"`
+			Value       any           `json:"value"`
+			Default     any           `json:"default"`
+			Description string        `json:"description"`
+			Source      config.Source `json:"source"`
 		}
 
 		configEntries := map[string]configEntry{}
@@ -52,6 +58,7 @@ var configInfoCmd = &cobra.Command{
 				Value:       config.Get(key),
 				Default:     config.Default(key),
 				Description: config.Description(key),
+				Source:      config.GetSource(key),
 			}
 		}
 
@@ -86,6 +93,10 @@ var configInfoCmd = &cobra.Command{
 				style.Normal.Viewport.Render("Default:"),
 				style.Normal.Secondary.Render(fmt.Sprintf("%v", entry.Default)),
 			)
+			cmd.Printf("  %s %s\n",
+				style.Normal.Viewport.Render("Source:"),
+				style.Normal.Secondary.Render(entry.Source.String()),
+			)
 		}
 	},
 }
@@ -177,3 +188,170 @@ var configSetCmd = &cobra.Command{
 		successf(cmd, "Successfully set %q to %v", key, converted)
 	},
 }
+
+func init() {
+	configCmd.AddCommand(configDevicesCmd)
+}
+
+var configDevicesCmd = &cobra.Command{
+	Use:   "devices",
+	Short: "List the device profiles",
+	Long:  "List the built-in and user defined (under [devices.<name>]) device profiles",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		devices, err := config.Devices()
+		if err != nil {
+			errorf(cmd, err.Error())
+		}
+
+		for _, name := range config.DeviceNames() {
+			device := devices[name]
+			cmd.Println(style.Bold.Accent.Render(name))
+			if device.Description != "" {
+				cmd.Printf("  %s\n", style.Normal.Secondary.Render(device.Description))
+			}
+			cmd.Printf("  %s %s\n",
+				style.Normal.Viewport.Render("Format:"),
+				style.Normal.Secondary.Render(fmt.Sprintf("%s (images: %q)", device.Format, device.ImageFormat)),
+			)
+			cmd.Printf("  %s %s\n",
+				style.Normal.Viewport.Render("Max size:"),
+				style.Normal.Secondary.Render(fmt.Sprintf("%dx%d", device.MaxWidth, device.MaxHeight)),
+			)
+			cmd.Printf("  %s %s\n",
+				style.Normal.Viewport.Render("Grayscale:"),
+				style.Normal.Secondary.Render(strconv.FormatBool(device.Grayscale)),
+			)
+			cmd.Printf("  %s %s\n",
+				style.Normal.Viewport.Render("Split spreads:"),
+				style.Normal.Secondary.Render(fmt.Sprintf("%t (left to right: %t)", device.SplitSpreads, device.LeftToRight)),
+			)
+			if device.ChapterNameTemplate != "" {
+				cmd.Printf("  %s %s\n",
+					style.Normal.Viewport.Render("Chapter name:"),
+					style.Normal.Secondary.Render(device.ChapterNameTemplate),
+				)
+			}
+		}
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configProfilesCmd)
+}
+
+var configProfilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "List the config profiles",
+	Long: fmt.Sprintf(`List the config profiles, the <name>.toml files in %s.
+
+The selected profile (--profile or %s env) and the %s
+of the working directory overlay the config file, in that order.`,
+		config.ProfilesDir(), config.EnvProfile, config.ProjectFilename),
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		profiles, err := config.Profiles()
+		if err != nil {
+			errorf(cmd, err.Error())
+		}
+
+		for _, profile := range profiles {
+			if profile == config.Profile {
+				cmd.Println(style.Bold.Accent.Render(profile + " (active)"))
+				continue
+			}
+			cmd.Println(profile)
+		}
+		for _, overlay := range config.Overlays() {
+			cmd.Printf("%s %s\n",
+				style.Normal.Viewport.Render("Loaded:"),
+				style.Normal.Secondary.Render(overlay),
+			)
+		}
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configThemesCmd)
+}
+
+var configThemesCmd = &cobra.Command{
+	Use:   "themes",
+	Short: "List the color themes",
+	Long: fmt.Sprintf(`List the built-in and user defined themes, the <name>.toml files in %s.
+
+The active theme is set with the "theme" key, the %s env always uses no-color.`,
+		config.ThemesDir(), theme.EnvNoColor),
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		for _, name := range theme.Names(config.ThemesDir()) {
+			t, err := theme.Load(config.ThemesDir(), name)
+			if err != nil {
+				cmd.Printf("%s %s\n", name, style.Normal.Error.Render(err.Error()))
+				continue
+			}
+			if name == config.Theme.Get() {
+				name += " (active)"
+			}
+			cmd.Printf("%s %s\n",
+				style.Bold.Base.Foreground(lipgloss.Color(t.Accent)).Render(name),
+				style.Normal.Base.Foreground(lipgloss.Color(t.Secondary)).Render(fmt.Sprintf("(extends: %s)", cmp.Or(t.Extends, "-"))),
+			)
+		}
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configKeysCmd)
+}
+
+var configKeysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "List the TUI keybindings",
+	Long: `List the TUI keybindings by keymap and action, and validate the overrides.
+
+The keys of any action can be overridden under [tui.keys.<keymap>], as in:
+
+	[tui.keys.chapters]
+	read = "R"
+	download = ["d", "ctrl+d"]`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		actions, err := util.KeyActions()
+		if err != nil {
+			errorf(cmd, err.Error())
+		}
+
+		var keyMap string
+		for _, action := range actions {
+			if action.KeyMap != keyMap {
+				keyMap = action.KeyMap
+				cmd.Println(style.Bold.Accent.Render(keyMap))
+			}
+			keys := quoteKeys(action.Keys)
+			if action.Overridden() {
+				keys += fmt.Sprintf(" (default: %s)", quoteKeys(action.Default))
+			}
+			cmd.Printf("  %s %s\n",
+				style.Normal.Viewport.Render(action.Name+":"),
+				style.Normal.Secondary.Render(keys),
+			)
+		}
+
+		if err := util.ValidateKeys(); err != nil {
+			errorf(cmd, err.Error())
+		}
+	},
+}
+
+// quoteKeys as written in the config, "space" being the space bar
+func quoteKeys(keys []string) string {
+	quoted := make([]string, len(keys))
+	for i, k := range keys {
+		if k == " " {
+			k = "space"
+		}
+		quoted[i] = strconv.Quote(k)
+	}
+	return strings.Join(quoted, ", ")
+}
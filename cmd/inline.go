@@ -56,11 +56,12 @@ func init() {
 }
 
 var inlineCmd = &cobra.Command{
-	Use:     config.ModeInline.String(),
-	Short:   "Useful for automation",
-	Long:    fmt.Sprintf("%s, useful for automation", config.ModeInline),
-	GroupID: groupMode,
-	Args:    cobra.NoArgs,
+	Use:              config.ModeInline.String(),
+	Short:            "Useful for automation",
+	Long:             fmt.Sprintf("%s, useful for automation", config.ModeInline),
+	GroupID:          groupMode,
+	Args:             cobra.NoArgs,
+	PersistentPreRun: serveMetrics,
 }
 
 func init() {
@@ -78,7 +79,9 @@ var inlineJSONCmd = &cobra.Command{
 	Short: "Output search results",
 	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, _ []string) {
-		if err := inline.RunJSON(context.Background(), inlineArgs); err != nil {
+		err := inline.RunJSON(context.Background(), inlineArgs)
+		writeMetrics(cmd)
+		if err != nil {
 			errorf(cmd, err.Error())
 		}
 	},
@@ -92,11 +95,17 @@ func init() {
 	f.BoolVar(&inlineArgs.JSONOutput, "json-output", false, "JSON format for individual chapter download output")
 	f.StringP("format", "f", config.Download.Format.Get().String(), fmtDesc)
 	f.StringP("directory", "d", config.Download.Path.Get(), "Download directory")
+	f.String("fallback", config.Download.Fallback.Providers.Get(), "Comma separated provider IDs to try when a chapter fails to download")
+	f.StringVar(&inlineArgs.Merge, "merge", "", fmt.Sprintf("Merge the chapters into a single file per volume or for the whole range (%s|%s)", inline.MergeVolume, inline.MergeRange))
+	f.String("device", "", "Device profile to use, sets the format and page image options (see 'config devices')")
 
 	inlineDownloadCmd.MarkFlagDirname("directory")
+	inlineDownloadCmd.RegisterFlagCompletionFunc("device", completionDevices)
+	inlineDownloadCmd.RegisterFlagCompletionFunc("merge", cobra.FixedCompletions([]string{inline.MergeVolume, inline.MergeRange}, cobra.ShellCompDirectiveNoFileComp))
 
 	config.BindPFlag(config.Download.Format.Key, f.Lookup("format"))
 	config.BindPFlag(config.Download.Path.Key, f.Lookup("directory"))
+	config.BindPFlag(config.Download.Fallback.Providers.Key, f.Lookup("fallback"))
 }
 
 var inlineDownloadCmd = &cobra.Command{
@@ -104,7 +113,20 @@ var inlineDownloadCmd = &cobra.Command{
 	Short: "Download manga",
 	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, _ []string) {
-		if err := inline.RunDownload(context.Background(), inlineArgs); err != nil {
+		if device := cmd.Flag("device").Value.String(); device != "" {
+			if err := config.ApplyDevice(device); err != nil {
+				errorf(cmd, err.Error())
+			}
+			// an explicit format takes precedence over the device's
+			if format := cmd.Flag("format"); format.Changed {
+				if err := config.Set(config.Download.Format.Key, format.Value.String()); err != nil {
+					errorf(cmd, err.Error())
+				}
+			}
+		}
+		err := inline.RunDownload(context.Background(), inlineArgs)
+		writeMetrics(cmd)
+		if err != nil {
 			errorf(cmd, err.Error())
 		}
 	},
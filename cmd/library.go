@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+
+	"github.com/luevano/mangal/library"
+	"github.com/luevano/mangal/theme/style"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(libraryCmd)
+}
+
+var libraryCmd = &cobra.Command{
+	Use:   "library",
+	Short: "Downloaded manga library commands",
+	Args:  cobra.NoArgs,
+}
+
+var libraryRenameArgs = struct {
+	Path   string
+	DryRun bool
+	JSON   bool
+}{}
+
+func init() {
+	libraryCmd.AddCommand(libraryRenameCmd)
+
+	f := libraryRenameCmd.Flags()
+	f.StringVar(&libraryRenameArgs.Path, "path", "", "Library path, defaults to library.path (or download.path)")
+	f.BoolVarP(&libraryRenameArgs.DryRun, "dry-run", "n", false, "Only show the changes, without moving anything")
+	f.BoolVarP(&libraryRenameArgs.JSON, "json", "j", false, "Output the moves as JSON")
+}
+
+var libraryRenameCmd = &cobra.Command{
+	Use:   "rename",
+	Short: "Rename the downloads with the current name templates",
+	Long: `Re-render the manga, volume and chapter names of the downloaded mangas with the
+current name templates, from the stored series.json and ComicInfo.xml, and move
+the files accordingly. Existing files are never overwritten.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		root := libraryRenameArgs.Path
+		if root == "" {
+			root = library.Path()
+		}
+
+		index, err := library.Scan(root)
+		if err != nil {
+			errorf(cmd, "error scanning the library: %s", err)
+		}
+		moves, err := library.PlanRename(index)
+		if err != nil {
+			errorf(cmd, "error planning the renames: %s", err)
+		}
+
+		if libraryRenameArgs.JSON {
+			if moves == nil {
+				moves = []library.Move{}
+			}
+			if err := json.NewEncoder(cmd.OutOrStdout()).Encode(moves); err != nil {
+				errorf(cmd, "error encoding moves: %s", err)
+			}
+		} else {
+			printMoves(cmd, root, moves)
+		}
+
+		if len(moves) == 0 {
+			if !libraryRenameArgs.JSON {
+				successf(cmd, "Library is up to date")
+			}
+			return
+		}
+		if libraryRenameArgs.DryRun {
+			return
+		}
+
+		if err := library.Rename(index, moves); err != nil {
+			errorf(cmd, "error renaming: %s", err)
+		}
+		if !libraryRenameArgs.JSON {
+			successf(cmd, "Library renamed")
+		}
+	},
+}
+
+// printMoves prints the moves as a diff, relative to the library root.
+func printMoves(cmd *cobra.Command, root string, moves []library.Move) {
+	rel := func(path string) string {
+		if r, err := filepath.Rel(root, path); err == nil {
+			return r
+		}
+		return path
+	}
+
+	for _, move := range moves {
+		cmd.Println(style.Normal.Error.Render("- " + rel(move.From)))
+		cmd.Println(style.Normal.Success.Render("+ " + rel(move.To)))
+		if move.Conflict != "" {
+			cmd.Println(style.Italic.Warning.Render("  skipped: " + move.Conflict))
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/luevano/mangal/log"
+	"github.com/luevano/mangal/path"
+	"github.com/luevano/mangal/theme/style"
+	"github.com/rs/zerolog"
+
+	"github.com/spf13/cobra"
+)
+
+var logsArgs = struct {
+	Follow   bool
+	Level    string
+	Provider string
+	JSON     bool
+}{}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+
+	f := logsCmd.Flags()
+	f.BoolVarP(&logsArgs.Follow, "follow", "f", false, "Keep reading the new logs")
+	f.StringVarP(&logsArgs.Level, "level", "l", zerolog.TraceLevel.String(), "Minimum level of the logs shown (trace|debug|info|warn|error)")
+	f.StringVarP(&logsArgs.Provider, "provider", "p", "", "Only show the logs of the provider id")
+	f.BoolVarP(&logsArgs.JSON, "json", "j", false, "Output the JSON log lines as is")
+
+	logsCmd.RegisterFlagCompletionFunc("level", cobra.FixedCompletions(
+		[]string{"trace", "debug", "info", "warn", "error"},
+		cobra.ShellCompDirectiveNoFileComp,
+	))
+	logsCmd.RegisterFlagCompletionFunc("provider", completionProviderIDs)
+}
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Show the logs",
+	Long: `Read back the logs written in the logs directory (see 'mangal path --logs'),
+oldest first. Use --verbose (or log.level) on other commands to log more.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		level, err := zerolog.ParseLevel(logsArgs.Level)
+		if err != nil {
+			errorf(cmd, "invalid level: %s", err)
+		}
+		filter := log.Filter{
+			Level:    level,
+			Provider: logsArgs.Provider,
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		reader := log.NewReader(path.LogDir())
+		for {
+			entries, err := reader.Read()
+			if err != nil {
+				errorf(cmd, "error reading logs: %s", err)
+			}
+			for _, entry := range entries {
+				if !filter.Match(entry) {
+					continue
+				}
+				if logsArgs.JSON {
+					cmd.Println(string(entry.Raw))
+				} else {
+					cmd.Println(renderEntry(entry))
+				}
+			}
+
+			if !logsArgs.Follow {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(500 * time.Millisecond):
+			}
+		}
+	},
+}
+
+// renderEntry renders the log entry in a single line.
+func renderEntry(entry log.Entry) string {
+	var sb strings.Builder
+	if !entry.Time.IsZero() {
+		sb.WriteString(style.Normal.Secondary.Render(entry.Time.Format(time.DateTime)))
+		sb.WriteString(" ")
+	}
+
+	level := strings.ToUpper(entry.Level.String())
+	switch entry.Level {
+	case zerolog.ErrorLevel, zerolog.FatalLevel, zerolog.PanicLevel:
+		level = style.Bold.Error.Render(level)
+	case zerolog.WarnLevel:
+		level = style.Bold.Warning.Render(level)
+	case zerolog.InfoLevel:
+		level = style.Bold.Success.Render(level)
+	case zerolog.NoLevel:
+		level = style.Bold.Secondary.Render("-")
+	default:
+		level = style.Bold.Secondary.Render(level)
+	}
+	sb.WriteString(level)
+
+	if entry.Provider != "" {
+		sb.WriteString(" " + style.Normal.Accent.Render("["+entry.Provider+"]"))
+	}
+	if entry.Message != "" {
+		sb.WriteString(" " + entry.Message)
+	}
+	if entry.Error != "" {
+		sb.WriteString(" " + style.Normal.Error.Render(entry.Error))
+	}
+	for _, key := range slices.Sorted(maps.Keys(entry.Fields)) {
+		sb.WriteString(" " + style.Normal.Secondary.Render(key+"=") + fmt.Sprint(entry.Fields[key]))
+	}
+	return sb.String()
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/log"
+	"github.com/luevano/mangal/metrics"
+	"github.com/spf13/cobra"
+)
+
+// serveMetrics serves /metrics and /healthz on metrics.address (if set)
+// until the command finishes, for the long running headless commands.
+func serveMetrics(_ *cobra.Command, _ []string) {
+	address := config.Metrics.Address.Get()
+	if address == "" {
+		return
+	}
+	go func() {
+		if err := metrics.Serve(context.Background(), address); err != nil {
+			log.L.Err(err).Str("address", address).Msg("error serving metrics")
+		}
+	}()
+}
+
+// writeMetrics writes the metrics to metrics.file (if set), called
+// after the headless commands finish, even on error.
+func writeMetrics(cmd *cobra.Command) {
+	path := config.Metrics.File.Get()
+	if path == "" {
+		return
+	}
+	if err := metrics.WriteFile(path); err != nil {
+		cmd.PrintErrf("error writing metrics file: %s\n", err)
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/inline"
+	"github.com/luevano/mangal/notify"
+	"github.com/luevano/mangal/notify/message"
+	"github.com/luevano/mangal/theme/icon"
+	"github.com/luevano/mangal/theme/style"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(notifyCmd)
+}
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Notification commands",
+	Args:  cobra.NoArgs,
+}
+
+func init() {
+	notifyCmd.AddCommand(notifyTestCmd)
+}
+
+var notifyTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Send a sample success and error through every configured backend",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		notifiers, err := notify.Notifiers()
+		if err != nil {
+			errorf(cmd, "error loading the notification backends: %s", err)
+		}
+		if len(notifiers) == 0 {
+			errorf(cmd, "no notification backends configured")
+		}
+
+		samples := []message.Message{
+			message.Text("Test", "Sample success notification sent with 'mangal notify test'."),
+			message.Error(errors.New("sample error notification sent with 'mangal notify test'")),
+		}
+		failed := false
+		for _, n := range notifiers {
+			for _, msg := range samples {
+				name := n.Name() + " (" + string(msg.Level) + ")"
+				if err := n.Notify(context.Background(), msg); err != nil {
+					failed = true
+					cmd.PrintErrf("%s %s: %s\n", icon.Cross.Colored(), name, err)
+					continue
+				}
+				successf(cmd, "%s", name)
+			}
+		}
+		if failed {
+			errorf(cmd, "some notifications couldn't be sent")
+		}
+	},
+}
+
+func init() {
+	notifyCmd.AddCommand(notifyWatchCmd)
+}
+
+var notifyWatchCmd = &cobra.Command{
+	Use:   "watch [name...]",
+	Short: "Notify about new chapters of the watched series, without downloading",
+	Long: `Check the watched series (the [watch.<name>] config tables) for chapters
+newly available on their providers and notify about them, without downloading.
+
+The first check of a series only records its current chapters, following checks
+notify about the chapters not seen before that aren't already downloaded. The
+chapters are recorded as seen only once notified, and it fails without any
+notification backend configured.`,
+	Example: `  # config.toml
+  [watch.berserk]
+  provider = "mangadex"
+  query = "berserk"
+  manga_selector = "exact"`,
+	ValidArgsFunction: completionWatched,
+	PreRun:            serveMetrics,
+	Run: func(cmd *cobra.Command, args []string) {
+		var series []config.Watch
+		if len(args) == 0 {
+			var err error
+			series, err = config.Watched()
+			if err != nil {
+				errorf(cmd, err.Error())
+			}
+		}
+		for _, name := range args {
+			w, err := config.GetWatched(name)
+			if err != nil {
+				errorf(cmd, err.Error())
+			}
+			series = append(series, w)
+		}
+		if len(series) == 0 {
+			errorf(cmd, "no watched series configured")
+		}
+
+		results, err := inline.RunWatch(context.Background(), series)
+		writeMetrics(cmd)
+		if err != nil {
+			errorf(cmd, err.Error())
+		}
+
+		failed := false
+		for _, result := range results {
+			name := style.Bold.Accent.Render(result.Watch.Name)
+			switch {
+			case result.Err != nil:
+				failed = true
+				cmd.PrintErrf("%s %s: %s\n", icon.Cross.Colored(), name, result.Err)
+			case result.First:
+				successf(cmd, "%s: %s", name, style.Normal.Secondary.Render("first check, chapters recorded"))
+			default:
+				successf(cmd, "%s: %d new chapters", name, len(result.New))
+			}
+		}
+		if failed {
+			errorf(cmd, "some watched series couldn't be checked")
+		}
+	},
+}
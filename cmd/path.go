@@ -6,6 +6,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/luevano/mangal/path"
 	pathViewer "github.com/luevano/mangal/tui/model/path"
+	"github.com/luevano/mangal/tui/util"
 	"github.com/spf13/cobra"
 )
 
@@ -54,13 +55,13 @@ var pathCmd = &cobra.Command{
 		case pathArgs.Config:
 			p = path.PathConfig
 		case pathArgs.Downloads:
-			p = path.PathConfig
+			p = path.PathDownloads
 		case pathArgs.Temp:
-			p = path.PathConfig
+			p = path.PathTemp
 		case pathArgs.Providers:
-			p = path.PathConfig
+			p = path.PathProviders
 		case pathArgs.Logs:
-			p = path.PathConfig
+			p = path.PathLog
 		}
 		paths := path.AllPaths()
 		if p != "" {
@@ -80,6 +81,9 @@ var pathCmd = &cobra.Command{
 			return
 		}
 
+		if err := util.ValidateKeys(); err != nil {
+			errorf(cmd, err.Error())
+		}
 		if _, err := tea.NewProgram(pathViewer.New(true)).Run(); err != nil {
 			errorf(cmd, err.Error())
 		}
@@ -2,13 +2,22 @@ package cmd
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
 	"net/url"
+	"os"
+	"os/signal"
+	"strings"
 
 	"github.com/luevano/libmangal"
 	"github.com/luevano/mangal/config"
 	"github.com/luevano/mangal/path"
+	"github.com/luevano/mangal/provider/dev"
 	"github.com/luevano/mangal/provider/info"
 	"github.com/luevano/mangal/provider/manager"
+	"github.com/luevano/mangal/theme/style"
+	"github.com/luevano/mangal/util/afs"
 	"github.com/spf13/cobra"
 )
 
@@ -22,28 +31,46 @@ var providersCmd = &cobra.Command{
 	Args:  cobra.NoArgs,
 }
 
+var providersAddArgs = struct {
+	Yes    bool
+	Commit string
+}{}
+
 func init() {
 	providersCmd.AddCommand(providersAddCmd)
+
+	f := providersAddCmd.Flags()
+	f.BoolVarP(&providersAddArgs.Yes, "yes", "y", false, "Accept the requested permissions without asking")
+	f.StringVarP(&providersAddArgs.Commit, "commit", "c", "", "Pin the provider to a commit hash, it won't be updated")
 }
 
 var providersAddCmd = &cobra.Command{
 	Use:   "add <url>",
 	Short: "Install provider",
 	Args:  cobra.ExactArgs(1),
-	RunE: func(_ *cobra.Command, args []string) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		URL, err := url.Parse(args[0])
 		if err != nil {
 			return err
 		}
 
 		return manager.Add(context.Background(), manager.AddOptions{
-			URL: URL,
+			URL:     URL,
+			Commit:  providersAddArgs.Commit,
+			Confirm: confirmPermissions(cmd, providersAddArgs.Yes),
 		})
 	},
 }
 
+var providersUpArgs = struct {
+	Yes bool
+}{}
+
 func init() {
 	providersCmd.AddCommand(providersUpCmd)
+
+	f := providersUpCmd.Flags()
+	f.BoolVarP(&providersUpArgs.Yes, "yes", "y", false, "Accept changes in the requested permissions without asking")
 }
 
 var providersUpCmd = &cobra.Command{
@@ -51,7 +78,9 @@ var providersUpCmd = &cobra.Command{
 	Short: "Update providers",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return manager.Update(context.Background(), manager.UpdateOptions{})
+		return manager.Update(context.Background(), manager.UpdateOptions{
+			Confirm: confirmPermissions(cmd, providersUpArgs.Yes),
+		})
 	},
 }
 
@@ -121,6 +150,9 @@ var providersNewCmd = &cobra.Command{
 					Website:     "example.com",
 				},
 				Type: info.TypeLua,
+				Permissions: &info.Permissions{
+					Hosts: []string{"example.com"},
+				},
 			},
 		}
 
@@ -129,3 +161,97 @@ var providersNewCmd = &cobra.Command{
 		}
 	},
 }
+
+var providersDevArgs = struct {
+	Query string
+}{}
+
+func init() {
+	providersCmd.AddCommand(providersDevCmd)
+
+	f := providersDevCmd.Flags()
+	f.StringVarP(&providersDevArgs.Query, "query", "q", "", "Query to search on each reload")
+
+	providersDevCmd.MarkFlagRequired("query")
+}
+
+var providersDevCmd = &cobra.Command{
+	Use:   "dev <dir>",
+	Short: "Develop a Lua provider, reloading it on each change",
+	Long: `Watches the Lua provider directory and on each change reloads it and runs
+the query from search through pages, printing the results and errors.
+
+The provider cache is kept across reloads and never expires, it lives
+in the "dev" directory of the cache (mangal path --cache).`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		err := dev.Run(ctx, dev.Options{
+			Dir:   args[0],
+			Query: providersDevArgs.Query,
+			Out:   cmd.OutOrStdout(),
+		})
+		if err != nil {
+			errorf(cmd, err.Error())
+		}
+	},
+}
+
+var providersSignArgs = struct {
+	Key string
+}{}
+
+func init() {
+	providersCmd.AddCommand(providersSignCmd)
+
+	f := providersSignCmd.Flags()
+	f.StringVarP(&providersSignArgs.Key, "key", "k", "", "File containing the base64 encoded ed25519 private key")
+
+	providersSignCmd.MarkFlagRequired("key")
+	providersSignCmd.MarkFlagFilename("key")
+}
+
+var providersSignCmd = &cobra.Command{
+	Use:   "sign <dir>",
+	Short: "Sign provider files",
+	Long:  fmt.Sprintf("Sign provider files, writes the signature to the %s file.", manager.SignatureFilename),
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		encoded, err := afs.Afero.ReadFile(providersSignArgs.Key)
+		if err != nil {
+			errorf(cmd, err.Error())
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+		if err != nil {
+			errorf(cmd, "invalid private key: %s", err.Error())
+		}
+		if len(key) != ed25519.PrivateKeySize {
+			errorf(cmd, "invalid private key: wrong size %d", len(key))
+		}
+
+		if err := manager.Sign(args[0], ed25519.PrivateKey(key)); err != nil {
+			errorf(cmd, err.Error())
+		}
+		successf(cmd, "Signed provider %s", args[0])
+	},
+}
+
+func init() {
+	providersCmd.AddCommand(providersKeygenCmd)
+}
+
+var providersKeygenCmd = &cobra.Command{
+	Use:   "keygen",
+	Short: "Generate a key pair to sign providers",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		public, private, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			errorf(cmd, err.Error())
+		}
+		cmd.Printf("%s %s\n", style.Bold.Accent.Render("Public key:"), base64.StdEncoding.EncodeToString(public))
+		cmd.Printf("%s %s\n", style.Bold.Accent.Render("Private key:"), base64.StdEncoding.EncodeToString(private))
+	},
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"strings"
 
 	"github.com/luevano/mangal/config"
@@ -24,7 +25,7 @@ func init() {
 	f.StringVarP(&scriptArgs.File, "file", "f", "", "Read script from file")
 	f.StringVarP(&scriptArgs.String, "string", "s", "", "Read script from script")
 	f.BoolVarP(&scriptArgs.Stdin, "stdin", "i", false, "Read script from stdin")
-	f.StringVarP(&scriptArgs.Provider, "provider", "p", "", "Load provider by tag")
+	f.StringVarP(&scriptArgs.Provider, "provider", "p", "", "Load provider by tag for the client module (others can be loaded with the providers module)")
 	f.StringToStringVarP(&scriptArgs.Variables, "vars", "v", nil, "Variables to set in the `Vars` table")
 
 	// Reused loader options from inlineCmd, this only
@@ -38,7 +39,6 @@ func init() {
 	f.AddFlag(inlineFlags.Lookup("headless-use-flaresolverr"))
 	f.AddFlag(inlineFlags.Lookup("headless-flaresolverr-url"))
 
-	scriptCmd.MarkPersistentFlagRequired("provider")
 	scriptCmd.MarkPersistentFlagRequired("vars")
 	scriptCmd.MarkFlagsOneRequired("file", "string", "stdin")
 	scriptCmd.MarkFlagsMutuallyExclusive("file", "string", "stdin")
@@ -74,7 +74,14 @@ var scriptCmd = &cobra.Command{
 			reader = os.Stdin
 		}
 
-		if err := script.Run(context.Background(), scriptArgs, reader); err != nil {
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		if err := script.Run(ctx, scriptArgs, reader); err != nil {
+			// interrupted, nothing else to report
+			if ctx.Err() != nil {
+				return
+			}
 			errorf(cmd, err.Error())
 		}
 	},
@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"text/template"
 
+	"github.com/luevano/mangal/client"
+	"github.com/luevano/mangal/config"
+	nametemplate "github.com/luevano/mangal/template"
 	"github.com/luevano/mangal/template/funcs"
 	"github.com/luevano/mangal/theme/style"
 	"github.com/samber/lo"
@@ -38,8 +43,22 @@ var templatesFuncsCmd = &cobra.Command{
 	},
 }
 
+// Data kinds that templates exec can render from a provider query.
+const (
+	templatesDataProvider = "provider"
+	templatesDataManga    = "manga"
+	templatesDataVolume   = "volume"
+	templatesDataChapter  = "chapter"
+)
+
 var templatesExecArgs = struct {
-	Value string
+	Value          string
+	Provider       string
+	Query          string
+	Manga          int
+	Chapter        float32
+	Data           string
+	SearchMetadata bool
 }{}
 
 func init() {
@@ -56,12 +75,33 @@ func init() {
 
 	f := templatesExecCmd.Flags()
 	f.StringVarP(&templatesExecArgs.Value, "value", "v", string(marshalled), "JSON object to use as value")
+	f.StringVarP(&templatesExecArgs.Provider, "provider", "p", "", "Provider ID to query the value from, instead of the JSON value")
+	f.StringVarP(&templatesExecArgs.Query, "query", "q", "", "Query to search the manga with")
+	f.IntVar(&templatesExecArgs.Manga, "manga", 0, "Index of the manga in the search results")
+	f.Float32Var(&templatesExecArgs.Chapter, "chapter", 0, "Chapter number, defaults to the first chapter")
+	f.StringVarP(&templatesExecArgs.Data, "data", "d", templatesDataChapter, "Name template data to use (provider, manga, volume or chapter)")
+	f.BoolVar(&templatesExecArgs.SearchMetadata, "search-metadata", config.Download.Metadata.Search.Get(), "Search the manga metadata, available to the manga data")
+
+	templatesExecCmd.MarkFlagsMutuallyExclusive("value", "provider")
+	templatesExecCmd.MarkFlagsRequiredTogether("provider", "query")
+	templatesExecCmd.RegisterFlagCompletionFunc("provider", completionProviderIDs)
+	templatesExecCmd.RegisterFlagCompletionFunc("data", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{
+			templatesDataProvider,
+			templatesDataManga,
+			templatesDataVolume,
+			templatesDataChapter,
+		}, cobra.ShellCompDirectiveNoFileComp
+	})
 }
 
 var templatesExecCmd = &cobra.Command{
 	Use:   "exec template...",
 	Short: "Execute template",
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Execute the template with the JSON value, or with the same data the name
+templates get from a provider query (--provider and --query).`,
+	Example: `  mangal templates exec -p mango-mangadex -q "chainsaw man" '{{ padNumber 4 .Chapter.Number }} {{ .Chapter.Title | default "Untitled" }}'`,
+	Args:    cobra.MinimumNArgs(1),
 	// TODO: fix issue when using spaces in -v
 	Run: func(cmd *cobra.Command, args []string) {
 		tmpl, err := template.
@@ -72,10 +112,18 @@ var templatesExecCmd = &cobra.Command{
 			errorf(cmd, err.Error())
 		}
 
-		var value map[string]any
-
-		if err := json.Unmarshal([]byte(templatesExecArgs.Value), &value); err != nil {
-			errorf(cmd, err.Error())
+		var value any
+		if templatesExecArgs.Provider != "" {
+			value, err = templatesProviderData(cmd.Context())
+			if err != nil {
+				errorf(cmd, err.Error())
+			}
+		} else {
+			var v map[string]any
+			if err := json.Unmarshal([]byte(templatesExecArgs.Value), &v); err != nil {
+				errorf(cmd, err.Error())
+			}
+			value = v
 		}
 
 		if err := tmpl.Execute(cmd.OutOrStdout(), value); err != nil {
@@ -85,3 +133,67 @@ var templatesExecCmd = &cobra.Command{
 		cmd.Println()
 	},
 }
+
+// templatesProviderData queries the provider and builds the name template data of the selected kind.
+func templatesProviderData(ctx context.Context) (any, error) {
+	args := templatesExecArgs
+	switch args.Data {
+	case templatesDataProvider, templatesDataManga, templatesDataVolume, templatesDataChapter:
+	default:
+		return nil, fmt.Errorf("invalid data %q, needs to be one of provider, manga, volume or chapter", args.Data)
+	}
+	c, err := client.NewClientByID(ctx, args.Provider)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseAll()
+
+	provider := c.Info()
+	if args.Data == templatesDataProvider {
+		return provider, nil
+	}
+
+	mangas, err := c.SearchMangas(ctx, args.Query)
+	if err != nil {
+		return nil, err
+	}
+	if args.Manga < 0 || args.Manga >= len(mangas) {
+		return nil, fmt.Errorf("manga index %d out of range, found %d mangas with query %q", args.Manga, len(mangas), args.Query)
+	}
+	manga := mangas[args.Manga]
+
+	if args.Data == templatesDataManga {
+		if args.SearchMetadata {
+			meta, err := c.SearchMetadata(ctx, manga)
+			if err != nil {
+				return nil, err
+			}
+			manga.SetMetadata(meta)
+		}
+		return nametemplate.MangaData(provider, manga), nil
+	}
+
+	volumes, err := c.MangaVolumes(ctx, manga)
+	if err != nil {
+		return nil, err
+	}
+	for _, volume := range volumes {
+		chapters, err := c.VolumeChapters(ctx, volume)
+		if err != nil {
+			return nil, err
+		}
+		for _, chapter := range chapters {
+			if args.Chapter != 0 && chapter.Info().Number != args.Chapter {
+				continue
+			}
+			if args.Data == templatesDataVolume {
+				return nametemplate.VolumeData(provider, volume), nil
+			}
+			return nametemplate.ChapterData(provider, chapter), nil
+		}
+	}
+	if args.Chapter != 0 {
+		return nil, fmt.Errorf("chapter %v not found for manga %q", args.Chapter, manga)
+	}
+	return nil, fmt.Errorf("no chapters found for manga %q", manga)
+}
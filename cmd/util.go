@@ -9,6 +9,7 @@ import (
 	"github.com/luevano/mangal/config"
 	"github.com/luevano/mangal/provider/manager"
 	"github.com/luevano/mangal/theme/icon"
+	"github.com/luevano/mangal/theme/style"
 	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 )
@@ -35,6 +36,18 @@ func completionProviderIDs(_ *cobra.Command, _ []string, _ string) ([]string, co
 	return IDs, cobra.ShellCompDirectiveDefault
 }
 
+func completionDevices(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return config.DeviceNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completionProfiles(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	profiles, err := config.Profiles()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return profiles, cobra.ShellCompDirectiveNoFileComp
+}
+
 func completionConfigKeys(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	keys := config.Keys()
 
@@ -44,3 +57,34 @@ func completionConfigKeys(_ *cobra.Command, _ []string, toComplete string) ([]st
 
 	return filtered, cobra.ShellCompDirectiveDefault
 }
+
+// confirmPermissions shows the permissions diff and asks the user to accept it.
+func confirmPermissions(cmd *cobra.Command, yes bool) manager.ConfirmPermissions {
+	return func(ID, diff string) bool {
+		cmd.Printf("Provider %s requests the following permissions:\n", style.Bold.Accent.Render(ID))
+		if diff == "" {
+			diff = "(none)"
+		}
+		cmd.Println(style.Normal.Secondary.Render(diff))
+		if yes {
+			return true
+		}
+
+		cmd.Print("Accept? [y/N] ")
+		var answer string
+		fmt.Fscanln(cmd.InOrStdin(), &answer)
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		return answer == "y" || answer == "yes"
+	}
+}
+
+func completionWatched(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	series, err := config.Watched()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	names := lo.Map(series, func(w config.Watch, _ int) string {
+		return w.Name
+	})
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -3,8 +3,12 @@ package config
 import (
 	"fmt"
 	"io/fs"
+	"net/http"
+	"net/url"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
 	"text/template"
 	"time"
 
@@ -13,7 +17,10 @@ import (
 	"github.com/luevano/libmangal"
 	"github.com/luevano/mangal/meta"
 	"github.com/luevano/mangal/template/funcs"
+	"github.com/luevano/mangal/theme"
 	"github.com/luevano/mangal/theme/icon"
+	imageutil "github.com/luevano/mangal/util/image"
+	"github.com/rs/zerolog"
 )
 
 // TODO: cleanup the config setup, register each config directly into
@@ -32,6 +39,7 @@ var (
 	// Path to the config file
 	Path         = filepath.Join(dir, filename)
 	Icons        = cfg.Icons
+	Theme        = cfg.Theme
 	Cache        = cfg.Cache
 	CLI          = cfg.CLI
 	Read         = cfg.Read
@@ -40,6 +48,9 @@ var (
 	Providers    = cfg.Providers
 	Library      = cfg.Library
 	Notification = cfg.Notification
+	Script       = cfg.Script
+	Log          = cfg.Log
+	Metrics      = cfg.Metrics
 )
 
 func xdgConfig() string {
@@ -52,6 +63,87 @@ func xdgConfig() string {
 	return d
 }
 
+// notificationFilter registers the filters of the notification backend.
+func notificationFilter(backend string) configNotificationFilter {
+	validate := func(s string) error {
+		if s == "" {
+			return nil
+		}
+		_, err := strconv.ParseBool(s)
+		return err
+	}
+	return configNotificationFilter{
+		IncludeExisting: reg(entry[string, string]{
+			Key:         "notification." + backend + ".include_existing",
+			Default:     "",
+			Description: "Overrides notification.include_existing for this backend (true or false). Empty uses notification.include_existing.",
+			Validate:    validate,
+		}),
+		IncludeDirectory: reg(entry[string, string]{
+			Key:         "notification." + backend + ".include_directory",
+			Default:     "",
+			Description: "Overrides notification.include_directory for this backend (true or false). Empty uses notification.include_directory.",
+			Validate:    validate,
+		}),
+	}
+}
+
+// notificationTemplatesDefaults are the default templates,
+// empty backend templates use the notification.templates ones.
+type notificationTemplatesDefaults struct {
+	Title, Body, Chapter string
+}
+
+// notificationTemplates registers the downloaded chapters message templates under the prefix.
+func notificationTemplates(prefix string, defaults notificationTemplatesDefaults) configNotificationTemplates {
+	validate := func(s string) error {
+		_, err := template.
+			New("").
+			Funcs(funcs.FuncMap).
+			Parse(s)
+
+		return err
+	}
+	fallback := ""
+	if prefix != "notification.templates" {
+		fallback = " Empty uses the notification.templates one."
+	}
+	return configNotificationTemplates{
+		Title: reg(entry[string, string]{
+			Key:         prefix + ".title",
+			Default:     defaults.Title,
+			Description: "Template of the downloaded chapters notification title, with .Manga, .Metadata, .Cover (URL), .Level, .Summary, the .Chapters (.Succeed, .Failed, .ToDownload, .Existent) and their rendered .SucceedList, .FailedList and .ToDownloadList." + fallback,
+			Validate:    validate,
+		}),
+		Body: reg(entry[string, string]{
+			Key:         prefix + ".body",
+			Default:     defaults.Body,
+			Description: "Template of the downloaded chapters notification body, same data as the title." + fallback,
+			Validate:    validate,
+		}),
+		Chapter: reg(entry[string, string]{
+			Key:         prefix + ".chapter",
+			Default:     defaults.Chapter,
+			Description: "Template of each line of the notification chapter lists, with the .Chapter, .Down, .Err, .Source, .Fallback, .Info, .Number, .Tag and .Status of the chapter." + fallback,
+			Validate:    validate,
+		}),
+	}
+}
+
+func validateURL(s string) error {
+	if s == "" {
+		return nil
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("URL %q must be http or https", s)
+	}
+	return nil
+}
+
 func initConfig() config {
 	// setups viper
 	Init()
@@ -68,6 +160,15 @@ func initConfig() config {
 				return i.String(), nil
 			},
 		}),
+		Theme: reg(entry[string, string]{
+			Key:         "theme",
+			Default:     theme.Dark,
+			Description: "Color theme, built-in (dark, light, high-contrast, no-color) or a <name>.toml in the themes directory. The NO_COLOR env always uses no-color.",
+			Validate: func(s string) error {
+				_, err := theme.Load(ThemesDir(), s)
+				return err
+			},
+		}),
 		Cache: configCache{
 			Path: reg(entry[string, string]{
 				Key:         "cache.path",
@@ -293,6 +394,21 @@ func initConfig() config {
 					},
 				}),
 			},
+			Merge: configDownloadMerge{
+				NameTemplate: reg(entry[string, string]{
+					Key:         "download.merge.name_template",
+					Default:     `{{ printf "[%06.1f-%06.1f] %s" .From .To .Manga.Title | sanitize }}`,
+					Description: "Template to use for naming merged chapter ranges. Merged volumes are named with the volume name template.",
+					Validate: func(s string) error {
+						_, err := template.
+							New("").
+							Funcs(funcs.FuncMap).
+							Parse(s)
+
+						return err
+					},
+				}),
+			},
 			Metadata: configDownloadMetadata{
 				Strict: reg(entry[bool, bool]{
 					Key:         "download.metadata.strict",
@@ -320,6 +436,87 @@ func initConfig() config {
 					Description: "Generate `ComicInfo.xml` file.",
 				}),
 			},
+			Image: configDownloadImage{
+				Format: reg(entry[string, imageutil.Format]{
+					Key:         "download.image.format",
+					Default:     imageutil.FormatOriginal,
+					Description: `Format to convert page images to, one of "jpeg", "png", "webp" (lossless, near-lossless below quality 81) or "avif" (needs avifenc from libavif in the PATH). Empty keeps the original format when possible (images are only re-encoded if any other step is enabled). The page file extension matches the output format.`,
+					Unmarshal: func(s string) (imageutil.Format, error) {
+						return imageutil.ParseFormat(s)
+					},
+					Marshal: func(f imageutil.Format) (string, error) {
+						return string(f), nil
+					},
+				}),
+				Quality: reg(entry[int64, int]{
+					Key:         "download.image.quality",
+					Default:     90,
+					Description: "Quality (1-100) used when encoding page images as JPEG, WebP or AVIF.",
+					Validate: func(i int) error {
+						if i < 1 || i > 100 {
+							return fmt.Errorf("image quality %d out of range (1-100)", i)
+						}
+						return nil
+					},
+				}),
+				Grayscale: reg(entry[bool, bool]{
+					Key:         "download.image.grayscale",
+					Default:     false,
+					Description: "Convert page images to grayscale, useful for e-ink devices.",
+				}),
+				MaxWidth: reg(entry[int64, int]{
+					Key:         "download.image.max_width",
+					Default:     0,
+					Description: "Maximum width of the page images, bigger images are resized keeping the aspect ratio. 0 disables it.",
+					Validate: func(i int) error {
+						if i < 0 {
+							return fmt.Errorf("max width must be non-negative")
+						}
+						return nil
+					},
+				}),
+				MaxHeight: reg(entry[int64, int]{
+					Key:         "download.image.max_height",
+					Default:     0,
+					Description: "Maximum height of the page images, bigger images are resized keeping the aspect ratio. 0 disables it.",
+					Validate: func(i int) error {
+						if i < 0 {
+							return fmt.Errorf("max height must be non-negative")
+						}
+						return nil
+					},
+				}),
+				AutoCrop: reg(entry[bool, bool]{
+					Key:         "download.image.auto_crop",
+					Default:     false,
+					Description: "Crop the white borders of the page images.",
+				}),
+				SplitSpreads: reg(entry[bool, bool]{
+					Key:         "download.image.split_spreads",
+					Default:     false,
+					Description: "Split double page spreads (wider than tall) in two pages, the right half first (manga reading order).",
+				}),
+				LeftToRight: reg(entry[bool, bool]{
+					Key:         "download.image.left_to_right",
+					Default:     false,
+					Description: "Put the left half of split spreads first (comics reading order) instead of the right half.",
+				}),
+			},
+			Fallback: configDownloadFallback{
+				Providers: reg(entry[string, string]{
+					Key:         "download.fallback.providers",
+					Default:     "",
+					Description: "Comma separated list of provider IDs to try (in order) when a chapter fails to download. The same manga is matched by its metadata ID and the chapter by its number. Empty disables the fallback, except for the series with a [fallback.<name>] chain (title and providers).",
+					Validate: func(s string) error {
+						for _, id := range splitList(s) {
+							if id == "" {
+								return fmt.Errorf("empty provider ID in fallback list %q", s)
+							}
+						}
+						return nil
+					},
+				}),
+			},
 		},
 		TUI: configTUI{
 			SkipHome: reg(entry[bool, bool]{
@@ -375,6 +572,11 @@ func initConfig() config {
 					Default:     true,
 					Description: "If the chapter scanlation group should be shown in the description.",
 				}),
+				PreferGroup: reg(entry[string, string]{
+					Key:         "tui.chapter.prefer_group",
+					Default:     "",
+					Description: "Scanlation group whose chapter is kept when there are chapters with the same number, the others are hidden. Only has effect with providers.filter.avoid_duplicate_chapters disabled.",
+				}),
 			},
 		},
 		Providers: configProviders{
@@ -403,6 +605,29 @@ func initConfig() config {
 					return int64(i), nil
 				},
 			}),
+			RequirePermissions: reg(entry[bool, bool]{
+				Key:         "providers.require_permissions",
+				Default:     false,
+				Description: "Refuse to load Lua providers that don't declare a `permissions` section in their `mangal.toml`. Providers that declare it are always restricted to it.",
+			}),
+			RequireSignature: reg(entry[bool, bool]{
+				Key:         "providers.require_signature",
+				Default:     false,
+				Description: "Refuse to install or update providers that aren't signed by a trusted key (`providers.trusted_keys` or `providers.trusted_keyring`).",
+			}),
+			TrustedKeys: reg(entry[string, string]{
+				Key:         "providers.trusted_keys",
+				Default:     "",
+				Description: "Comma separated list of base64 encoded ed25519 public keys trusted to sign providers (`mangal.sig` file).",
+			}),
+			TrustedKeyring: reg(entry[string, string]{
+				Key:         "providers.trusted_keyring",
+				Default:     "",
+				Description: "Path to an armored PGP keyring trusted to sign provider git tags.",
+				Unmarshal: func(s string) (string, error) {
+					return expandPath(s)
+				},
+			}),
 			Headless: configProvidersHeadless{
 				UseFlaresolverr: reg(entry[bool, bool]{
 					Key:         "providers.headless.use_flaresolverr",
@@ -488,12 +713,29 @@ func initConfig() config {
 			IncludeExisting: reg(entry[bool, bool]{
 				Key:         "notification.include_existing",
 				Default:     false,
-				Description: "If existing downloaded chapters should be included in the notification. If false and all chapters already existed, no notification is sent.",
+				Description: "If existing downloaded chapters should be included in the notification. If false and all chapters already existed, no notification is sent. Each backend can override it with its own include_existing.",
 			}),
 			IncludeDirectory: reg(entry[bool, bool]{
 				Key:         "notification.include_directory",
 				Default:     true,
-				Description: "If the full chapter download directory should be included, only unique directories are shown.",
+				Description: "If the full chapter download directory should be included, only unique directories are shown. Each backend can override it with its own include_directory.",
+			}),
+			URLs: reg(entry[string, string]{
+				Key:         "notification.urls",
+				Default:     "",
+				Description: "Comma separated list of Apprise-style backend URLs (discord://, ntfy://, ntfys://, gotify://, gotifys://, mailto:// and mailtos://), in addition to the configured backends. They use the filters and templates of their backend.",
+				Validate: func(s string) error {
+					for _, raw := range splitList(s) {
+						u, err := url.Parse(raw)
+						if err != nil {
+							return err
+						}
+						if u.Scheme == "" {
+							return fmt.Errorf("URL %q has no scheme", raw)
+						}
+					}
+					return nil
+				},
 			}),
 			Discord: configNotificationDiscord{
 				Username: reg(entry[string, string]{
@@ -513,7 +755,282 @@ func initConfig() config {
 						return err
 					},
 				}),
+				Filter: notificationFilter("discord"),
+				// the chapter lists are sent as embed fields
+				Templates: notificationTemplates("notification.discord.templates", notificationTemplatesDefaults{
+					Body: "{{ .Manga.Title }}",
+				}),
+			},
+			Webhook: configNotificationWebhook{
+				URL: reg(entry[string, string]{
+					Key:         "notification.webhook.url",
+					Default:     "",
+					Description: "URL of the generic webhook, if present the payload is sent to it.",
+					Validate:    validateURL,
+				}),
+				Method: reg(entry[string, string]{
+					Key:         "notification.webhook.method",
+					Default:     http.MethodPost,
+					Description: "HTTP method of the generic webhook requests.",
+					Validate: func(s string) error {
+						if s == "" {
+							return fmt.Errorf("method is empty")
+						}
+						return nil
+					},
+				}),
+				Headers: reg(entry[string, string]{
+					Key:         "notification.webhook.headers",
+					Default:     "",
+					Description: `Comma separated list of "Name: value" headers of the generic webhook requests.`,
+					Validate: func(s string) error {
+						for _, header := range splitList(s) {
+							if name, _, ok := strings.Cut(header, ":"); !ok || strings.TrimSpace(name) == "" {
+								return fmt.Errorf(`header %q is not in the "Name: value" form`, header)
+							}
+						}
+						return nil
+					},
+				}),
+				PayloadTemplate: reg(entry[string, string]{
+					Key:         "notification.webhook.payload_template",
+					Default:     `{"title": {{ toJSON .Title }}, "body": {{ toJSON .Body }}, "level": {{ toJSON .Level }}, "manga": {{ toJSON .Manga }}}`,
+					Description: "Template of the generic webhook JSON payload, with the message Title, Body, Level (success, warning or error), Manga and Chapters.",
+					Validate: func(s string) error {
+						_, err := template.
+							New("").
+							Funcs(funcs.FuncMap).
+							Parse(s)
+
+						return err
+					},
+				}),
+				Filter:    notificationFilter("webhook"),
+				Templates: notificationTemplates("notification.webhook.templates", notificationTemplatesDefaults{}),
+			},
+			Ntfy: configNotificationNtfy{
+				URL: reg(entry[string, string]{
+					Key:         "notification.ntfy.url",
+					Default:     "",
+					Description: "URL of the ntfy topic (for example https://ntfy.sh/my-topic), if present ntfy notifications will be sent.",
+					Validate:    validateURL,
+				}),
+				Token: reg(entry[string, string]{
+					Key:         "notification.ntfy.token",
+					Default:     "",
+					Description: "Access token for protected ntfy topics.",
+				}),
+				Filter:    notificationFilter("ntfy"),
+				Templates: notificationTemplates("notification.ntfy.templates", notificationTemplatesDefaults{}),
 			},
+			Gotify: configNotificationGotify{
+				URL: reg(entry[string, string]{
+					Key:         "notification.gotify.url",
+					Default:     "",
+					Description: "URL of the Gotify server, if present (along the token) Gotify notifications will be sent.",
+					Validate:    validateURL,
+				}),
+				Token: reg(entry[string, string]{
+					Key:         "notification.gotify.token",
+					Default:     "",
+					Description: "Gotify application token.",
+				}),
+				Filter:    notificationFilter("gotify"),
+				Templates: notificationTemplates("notification.gotify.templates", notificationTemplatesDefaults{}),
+			},
+			SMTP: configNotificationSMTP{
+				Host: reg(entry[string, string]{
+					Key:         "notification.smtp.host",
+					Default:     "",
+					Description: "SMTP server host, if present (along the recipients) email notifications will be sent.",
+				}),
+				Port: reg(entry[int, int]{
+					Key:         "notification.smtp.port",
+					Default:     587,
+					Description: "SMTP server port.",
+					Validate: func(i int) error {
+						if i <= 0 || i > 65535 {
+							return fmt.Errorf("invalid port %d", i)
+						}
+						return nil
+					},
+				}),
+				TLS: reg(entry[bool, bool]{
+					Key:         "notification.smtp.tls",
+					Default:     false,
+					Description: "Connect with implicit TLS (usually port 465). Else STARTTLS is used if the server supports it.",
+				}),
+				Username: reg(entry[string, string]{
+					Key:         "notification.smtp.username",
+					Default:     "",
+					Description: "SMTP username, if empty no authentication is done.",
+				}),
+				Password: reg(entry[string, string]{
+					Key:         "notification.smtp.password",
+					Default:     "",
+					Description: "SMTP password.",
+				}),
+				From: reg(entry[string, string]{
+					Key:         "notification.smtp.from",
+					Default:     "",
+					Description: "Sender address of the emails, defaults to the username.",
+				}),
+				To: reg(entry[string, string]{
+					Key:         "notification.smtp.to",
+					Default:     "",
+					Description: "Comma separated list of recipient addresses.",
+				}),
+				Filter:    notificationFilter("smtp"),
+				Templates: notificationTemplates("notification.smtp.templates", notificationTemplatesDefaults{}),
+			},
+			Templates: notificationTemplates("notification.templates", notificationTemplatesDefaults{
+				Title: "Downloaded chapters",
+				Body: `{{ .Manga.Title }}
+{{ .Summary }}
+{{- with .FailedList }}
+
+failed:
+{{ . }}{{ end }}
+{{- with .SucceedList }}
+
+succeed:
+{{ . }}{{ end }}
+{{- with .ToDownloadList }}
+
+to download:
+{{ . }}{{ end }}`,
+				Chapter: "> {{ with .Tag }}{{ . }} {{ end }}{{ .Number }} - {{ .Info.Title }}{{ if .Fallback }} (via {{ .Source }}){{ end }}",
+			}),
+		},
+		Script: configScript{
+			Path: reg(entry[string, string]{
+				Key:         "script.path",
+				Default:     filepath.Join(dir, "scripts"),
+				Description: "Comma separated list of directories where Lua modules are looked for by require, in order, before the default Lua paths.",
+				Validate: func(s string) error {
+					for _, path := range splitList(s) {
+						if path == "" {
+							return fmt.Errorf("empty directory in path list %q", s)
+						}
+					}
+					return nil
+				},
+			}),
+			Sandbox: reg(entry[bool, bool]{
+				Key:         "script.sandbox",
+				Default:     false,
+				Description: "Run the scripts and hooks without the io and os Lua libraries (nor dofile and loadfile), so the filesystem is only reachable through the fs module.",
+			}),
+			FS: configScriptFS{
+				Roots: reg(entry[string, string]{
+					Key:         "script.fs.roots",
+					Default:     "",
+					Description: "Comma separated list of directories the scripts fs module has access to (including subdirectories). Empty will only allow the library path.",
+					Validate: func(s string) error {
+						for _, root := range splitList(s) {
+							if root == "" {
+								return fmt.Errorf("empty directory in roots list %q", s)
+							}
+						}
+						return nil
+					},
+				}),
+				ReadOnly: reg(entry[bool, bool]{
+					Key:         "script.fs.read_only",
+					Default:     false,
+					Description: "Only allow read operations in the scripts fs module.",
+				}),
+			},
+			HTTP: configScriptHTTP{
+				Enabled: reg(entry[bool, bool]{
+					Key:         "script.http.enabled",
+					Default:     true,
+					Description: "Allow scripts to make requests with the http module (and the sdk http module, without the sdk headless module when restricted).",
+				}),
+				AllowedHosts: reg(entry[string, string]{
+					Key:         "script.http.allowed_hosts",
+					Default:     "",
+					Description: "Comma separated list of hosts the scripts http and sdk http modules can make requests to, subdomains included. Empty allows any host.",
+				}),
+				Timeout: reg(entry[string, string]{
+					Key:         "script.http.timeout",
+					Default:     "30s",
+					Description: "Timeout of the scripts http module requests, as a duration string (see cache.ttl).",
+					Validate: func(s string) error {
+						_, err := time.ParseDuration(s)
+						return err
+					},
+				}),
+			},
+			Hooks: configScriptHooks{
+				Enabled: reg(entry[bool, bool]{
+					Key:         "script.hooks.enabled",
+					Default:     true,
+					Description: "Run the download lifecycle hooks found in the hooks path.",
+				}),
+				Path: reg(entry[string, string]{
+					Key:         "script.hooks.path",
+					Default:     filepath.Join(dir, "hooks"),
+					Description: "Path where the Lua hooks (*.lua) are looked for. Each hook can define the functions before_download, after_chapter_downloaded, after_download_batch and on_error.",
+					Unmarshal: func(s string) (string, error) {
+						return expandPath(s)
+					},
+				}),
+			},
+		},
+		Log: configLog{
+			Level: reg(entry[string, string]{
+				Key:         "log.level",
+				Default:     "info",
+				Description: "Minimum level of the logs written (trace, debug, info, warn, error). Debug includes the provider requests.",
+				Validate: func(s string) error {
+					_, err := zerolog.ParseLevel(s)
+					if err == nil && s == "" {
+						return fmt.Errorf("empty log level")
+					}
+					return err
+				},
+			}),
+			MaxSize: reg(entry[int, int]{
+				Key:         "log.max_size",
+				Default:     10,
+				Description: "Maximum size in MB of a log file before it is rotated.",
+				Validate: func(i int) error {
+					if i <= 0 {
+						return fmt.Errorf("log max size must be positive")
+					}
+					return nil
+				},
+			}),
+			MaxAge: reg(entry[string, string]{
+				Key:         "log.max_age",
+				Default:     "168h",
+				Description: "Log files older than this are removed, as a duration string (see cache.ttl).",
+				Validate: func(s string) error {
+					_, err := time.ParseDuration(s)
+					return err
+				},
+			}),
+		},
+		Metrics: configMetrics{
+			Enabled: reg(entry[bool, bool]{
+				Key:         "metrics.enabled",
+				Default:     false,
+				Description: "Serve the Prometheus /metrics endpoint along the web server.",
+			}),
+			Address: reg(entry[string, string]{
+				Key:         "metrics.address",
+				Default:     "",
+				Description: "Address (for example :9090) to serve /metrics and /healthz on while running the inline and notify watch commands. Empty disables it.",
+			}),
+			File: reg(entry[string, string]{
+				Key:         "metrics.file",
+				Default:     "",
+				Description: "File where the metrics are written (Prometheus text format) after the inline and notify watch commands finish, for the node exporter textfile collector. Empty disables it.",
+				Unmarshal: func(s string) (string, error) {
+					return expandPath(s)
+				},
+			}),
 		},
 	}
 	// Load from "default" config paths
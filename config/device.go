@@ -0,0 +1,208 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/luevano/libmangal"
+	imageutil "github.com/luevano/mangal/util/image"
+	"github.com/spf13/viper"
+)
+
+// devicesKey is the config table where the user defined devices live, as in:
+//
+//	[devices.my-kobo]
+//	format = "CBZ"
+//	max_width = 1072
+//	max_height = 1448
+//	grayscale = true
+const devicesKey = "devices"
+
+// Device is a named profile bundling the download
+// format and page image settings for an e-reader.
+type Device struct {
+	Name        string `mapstructure:"-"`
+	Description string `mapstructure:"description"`
+	// Format is the download format name (see libmangal.FormatStrings).
+	Format string `mapstructure:"format"`
+	// ImageFormat is the page image format (see imageutil.ParseFormat).
+	ImageFormat  string `mapstructure:"image_format"`
+	MaxWidth     int    `mapstructure:"max_width"`
+	MaxHeight    int    `mapstructure:"max_height"`
+	Grayscale    bool   `mapstructure:"grayscale"`
+	SplitSpreads bool   `mapstructure:"split_spreads"`
+	LeftToRight  bool   `mapstructure:"left_to_right"`
+	// ChapterNameTemplate replaces the chapter name template, if not empty.
+	ChapterNameTemplate string `mapstructure:"chapter_name_template"`
+}
+
+// builtinDevices are the devices always available, user
+// defined devices with the same name take precedence.
+var builtinDevices = map[string]Device{
+	"kindle": {
+		Description: "Kindle Paperwhite (11th gen)",
+		Format:      libmangal.FormatPDF.String(),
+		ImageFormat: string(imageutil.FormatJPEG),
+		MaxWidth:    1236, MaxHeight: 1648,
+		Grayscale: true, SplitSpreads: true,
+	},
+	"kindle-scribe": {
+		Description: "Kindle Scribe",
+		Format:      libmangal.FormatPDF.String(),
+		ImageFormat: string(imageutil.FormatJPEG),
+		MaxWidth:    1860, MaxHeight: 2480,
+		Grayscale: true, SplitSpreads: true,
+	},
+	"kobo": {
+		Description: "Kobo Clara 2E/BW",
+		Format:      libmangal.FormatCBZ.String(),
+		ImageFormat: string(imageutil.FormatJPEG),
+		MaxWidth:    1072, MaxHeight: 1448,
+		Grayscale: true, SplitSpreads: true,
+	},
+	"kobo-libra": {
+		Description: "Kobo Libra 2",
+		Format:      libmangal.FormatCBZ.String(),
+		ImageFormat: string(imageutil.FormatJPEG),
+		MaxWidth:    1264, MaxHeight: 1680,
+		Grayscale: true, SplitSpreads: true,
+	},
+	"kobo-color": {
+		Description: "Kobo Libra/Clara Colour",
+		Format:      libmangal.FormatCBZ.String(),
+		ImageFormat: string(imageutil.FormatJPEG),
+		MaxWidth:    1264, MaxHeight: 1680,
+		SplitSpreads: true,
+	},
+}
+
+// Devices returns the built-in devices along the user defined ones.
+func Devices() (map[string]Device, error) {
+	devices := maps.Clone(builtinDevices)
+
+	var user map[string]Device
+	if err := viper.UnmarshalKey(devicesKey, &user); err != nil {
+		return nil, fmt.Errorf("error reading devices: %s", err.Error())
+	}
+	maps.Copy(devices, user)
+
+	for name, device := range devices {
+		device.Name = name
+		devices[name] = device
+	}
+	return devices, nil
+}
+
+// DeviceNames returns the sorted names of all the devices.
+func DeviceNames() []string {
+	devices, err := Devices()
+	if err != nil {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(devices))
+}
+
+// GetDevice returns the device by name.
+func GetDevice(name string) (Device, error) {
+	devices, err := Devices()
+	if err != nil {
+		return Device{}, err
+	}
+	device, ok := devices[name]
+	if !ok {
+		return Device{}, fmt.Errorf("unknown device %q, available: %s", name, strings.Join(slices.Sorted(maps.Keys(devices)), ", "))
+	}
+	return device, nil
+}
+
+// ApplyDevice sets the config values of the device by name.
+//
+// The config is not written to disk.
+func ApplyDevice(name string) error {
+	device, err := GetDevice(name)
+	if err != nil {
+		return err
+	}
+	return device.Apply()
+}
+
+// Apply sets the config values of the device.
+//
+// The config is not written to disk.
+func (d Device) Apply() error {
+	if err := d.validate(); err != nil {
+		return err
+	}
+
+	format, _ := libmangal.FormatString(d.Format)
+	imageFormat, _ := imageutil.ParseFormat(d.ImageFormat)
+	errs := []error{
+		Download.Format.Set(format),
+		Download.Image.Format.Set(imageFormat),
+		Download.Image.MaxWidth.Set(d.MaxWidth),
+		Download.Image.MaxHeight.Set(d.MaxHeight),
+		Download.Image.Grayscale.Set(d.Grayscale),
+		Download.Image.SplitSpreads.Set(d.SplitSpreads),
+		Download.Image.LeftToRight.Set(d.LeftToRight),
+	}
+	if d.ChapterNameTemplate != "" {
+		errs = append(errs, Download.Chapter.NameTemplate.Set(d.ChapterNameTemplate))
+	}
+	for _, err := range errs {
+		if err != nil {
+			return fmt.Errorf("error applying device %q: %s", d.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+// Applied returns true if the current config values match the device.
+func (d Device) Applied() bool {
+	if d.validate() != nil {
+		return false
+	}
+	format, _ := libmangal.FormatString(d.Format)
+	imageFormat, _ := imageutil.ParseFormat(d.ImageFormat)
+	return Download.Format.Get() == format &&
+		Download.Image.Format.Get() == imageFormat &&
+		Download.Image.MaxWidth.Get() == d.MaxWidth &&
+		Download.Image.MaxHeight.Get() == d.MaxHeight &&
+		Download.Image.Grayscale.Get() == d.Grayscale &&
+		Download.Image.SplitSpreads.Get() == d.SplitSpreads &&
+		Download.Image.LeftToRight.Get() == d.LeftToRight &&
+		(d.ChapterNameTemplate == "" || Download.Chapter.NameTemplate.Get() == d.ChapterNameTemplate)
+}
+
+func (d Device) validate() error {
+	if _, err := libmangal.FormatString(d.Format); err != nil {
+		return fmt.Errorf("device %q: invalid format %q", d.Name, d.Format)
+	}
+	if _, err := imageutil.ParseFormat(d.ImageFormat); err != nil {
+		return fmt.Errorf("device %q: %s", d.Name, err.Error())
+	}
+	if d.MaxWidth < 0 || d.MaxHeight < 0 {
+		return fmt.Errorf("device %q: negative max size %dx%d", d.Name, d.MaxWidth, d.MaxHeight)
+	}
+	return nil
+}
+
+// isDeviceKey returns true if the key belongs to a user defined device.
+func isDeviceKey(key string) bool {
+	return strings.HasPrefix(key, devicesKey+".")
+}
+
+// validateDevices validates all the devices.
+func validateDevices() error {
+	devices, err := Devices()
+	if err != nil {
+		return err
+	}
+	for _, device := range devices {
+		if err := device.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,10 +1,20 @@
 package config
 
 import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
 	"github.com/luevano/libmangal"
 	"github.com/luevano/libmangal/metadata"
+	imageutil "github.com/luevano/mangal/util/image"
 )
 
+// ThemesDir is the directory where the user defined themes (<name>.toml) are looked for.
+func ThemesDir() string {
+	return filepath.Join(dir, "themes")
+}
+
 // DownloadOptions constructs the libmangal.DownloadOptions populated by the Config.
 func DownloadOptions() libmangal.DownloadOptions {
 	// start from the defaults in case of new additions and build on top of it.
@@ -23,15 +33,109 @@ func DownloadOptions() libmangal.DownloadOptions {
 	o.SkipSeriesJSONIfOngoing = Download.Metadata.SkipSeriesJSONIfOngoing.Get()
 	o.WriteComicInfoXML = Download.Metadata.ComicInfoXML.Get()
 	o.ComicInfoXMLOptions = metadata.DefaultComicInfoOptions()
-	o.ImageTransformer = func(bytes []byte) ([]byte, error) {
-		return bytes, nil
-	}
 	return o
 }
 
+// ImageOptions constructs the image transformation options populated by the Config.
+func ImageOptions() imageutil.Options {
+	return imageutil.Options{
+		Format:       Download.Image.Format.Get(),
+		Quality:      Download.Image.Quality.Get(),
+		Grayscale:    Download.Image.Grayscale.Get(),
+		MaxWidth:     Download.Image.MaxWidth.Get(),
+		MaxHeight:    Download.Image.MaxHeight.Get(),
+		AutoCrop:     Download.Image.AutoCrop.Get(),
+		SplitSpreads: Download.Image.SplitSpreads.Get(),
+		LeftToRight:  Download.Image.LeftToRight.Get(),
+	}
+}
+
 func ReadOptions() libmangal.ReadOptions {
 	o := libmangal.DefaultReadOptions()
 	o.SaveHistory = Read.History.Local.Get()
 	o.SaveAnilist = Read.History.Anilist.Get()
 	return o
 }
+
+// FallbackProviders returns the list of provider IDs to fallback to when a chapter fails to download.
+func FallbackProviders() []string {
+	return splitList(Download.Fallback.Providers.Get())
+}
+
+// TrustedKeys returns the list of base64 encoded ed25519 public keys trusted to sign providers.
+func TrustedKeys() []string {
+	return splitList(Providers.TrustedKeys.Get())
+}
+
+// ScriptPaths returns the directories where the scripts Lua modules are looked for.
+func ScriptPaths() []string {
+	return expandPaths(splitList(Script.Path.Get()))
+}
+
+// ScriptFSRoots returns the directories the scripts fs module has access to,
+// defaults to the library path (which falls back to the download path).
+func ScriptFSRoots() []string {
+	roots := splitList(Script.FS.Roots.Get())
+	if len(roots) == 0 {
+		root := Library.Path.Get()
+		if root == "" {
+			root = Download.Path.Get()
+		}
+		return []string{root}
+	}
+	return expandPaths(roots)
+}
+
+// ScriptHTTPAllowedHosts returns the hosts the scripts http module can make requests to.
+func ScriptHTTPAllowedHosts() []string {
+	return splitList(Script.HTTP.AllowedHosts.Get())
+}
+
+// NotificationFilter returns the include existing and include directory
+// filters of the notification backend, falling back to the global ones.
+func NotificationFilter(filter configNotificationFilter) (includeExisting, includeDirectory bool) {
+	includeExisting, err := strconv.ParseBool(filter.IncludeExisting.Get())
+	if err != nil {
+		includeExisting = Notification.IncludeExisting.Get()
+	}
+	includeDirectory, err = strconv.ParseBool(filter.IncludeDirectory.Get())
+	if err != nil {
+		includeDirectory = Notification.IncludeDirectory.Get()
+	}
+	return includeExisting, includeDirectory
+}
+
+// NotificationURLs returns the Apprise-style notification backend URLs.
+func NotificationURLs() []string {
+	return splitList(Notification.URLs.Get())
+}
+
+// NotificationSMTPTo returns the email notification recipients.
+func NotificationSMTPTo() []string {
+	return splitList(Notification.SMTP.To.Get())
+}
+
+// NotificationWebhookHeaders returns the generic webhook headers.
+func NotificationWebhookHeaders() map[string]string {
+	headers := make(map[string]string)
+	for _, header := range splitList(Notification.Webhook.Headers.Get()) {
+		name, value, _ := strings.Cut(header, ":")
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return headers
+}
+
+// NotificationTemplates returns the title, body and chapter templates
+// of the notification backend, falling back to the global ones.
+func NotificationTemplates(templates configNotificationTemplates) (title, body, chapter string) {
+	or := func(e, fallback *entry[string, string]) string {
+		if v := e.Get(); v != "" {
+			return v
+		}
+		return fallback.Get()
+	}
+	global := Notification.Templates
+	return or(templates.Title, global.Title),
+		or(templates.Body, global.Body),
+		or(templates.Chapter, global.Chapter)
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// fallbackKey is the config table where the per series fallback chains
+// live, which take precedence over download.fallback.providers, as in:
+//
+//	[fallback.berserk]
+//	title = "Berserk"
+//	providers = ["mangapill", "mangadex"]
+const fallbackKey = "fallback"
+
+// FallbackChain are the providers to fallback to for a series.
+type FallbackChain struct {
+	Name string `mapstructure:"-"`
+	// Title of the manga (or its metadata) to match, case insensitive;
+	// defaults to the name of the chain.
+	Title     string   `mapstructure:"title"`
+	Providers []string `mapstructure:"providers"`
+}
+
+// Matches returns true if any of the titles is the title of the chain.
+func (f FallbackChain) Matches(titles ...string) bool {
+	return slices.ContainsFunc(titles, func(title string) bool {
+		return strings.EqualFold(strings.TrimSpace(title), f.Title)
+	})
+}
+
+// isFallbackKey returns true if the key belongs to a per series fallback chain.
+func isFallbackKey(key string) bool {
+	return strings.HasPrefix(key, fallbackKey+".")
+}
+
+// FallbackChains returns the per series fallback chains, sorted by name.
+func FallbackChains() ([]FallbackChain, error) {
+	var chains map[string]FallbackChain
+	if err := viper.UnmarshalKey(fallbackKey, &chains); err != nil {
+		return nil, fmt.Errorf("error reading fallback chains: %s", err.Error())
+	}
+
+	result := make([]FallbackChain, 0, len(chains))
+	for _, name := range slices.Sorted(maps.Keys(chains)) {
+		chain := chains[name]
+		chain.Name = name
+		if chain.Title == "" {
+			chain.Title = name
+		}
+		chain.Title = strings.TrimSpace(chain.Title)
+		if len(chain.Providers) == 0 || slices.Contains(chain.Providers, "") {
+			return nil, fmt.Errorf("fallback chain %q needs a list of non-empty provider IDs", name)
+		}
+		result = append(result, chain)
+	}
+	return result, nil
+}
@@ -10,6 +10,8 @@ import (
 var fields = make(map[string]field)
 
 type field struct {
+	// Raw is the type of the value as stored in the config file.
+	Raw         reflect.Type
 	Default     any
 	Description string
 	Unmarshal   func(any) (any, error)
@@ -74,6 +76,7 @@ func reg[Raw, Value any](e entry[Raw, Value]) *entry[Raw, Value] {
 	}
 
 	fields[e.Key] = field{
+		Raw:         reflect.TypeFor[Raw](),
 		Description: e.Description,
 		Default:     e.Default,
 		Marshal: func(a any) (any, error) {
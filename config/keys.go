@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// keysKey is the config table where the TUI keybinding overrides live,
+// by keymap and action name, as in:
+//
+//	[tui.keys.chapters]
+//	read = "R"
+//	download = ["d", "ctrl+d"]
+//
+//	[tui.keys.global]
+//	home = "ctrl+h"
+const keysKey = "tui.keys"
+
+// isKeysKey returns true if the key belongs to a keybinding override.
+func isKeysKey(key string) bool {
+	return strings.HasPrefix(key, keysKey+".")
+}
+
+// TUIKeys returns the keybinding overrides, the keys of each action by keymap.
+//
+// A single key can be set as a string, "space" means the space bar.
+func TUIKeys() (map[string]map[string][]string, error) {
+	var keyMaps map[string]map[string][]string
+	if err := viper.UnmarshalKey(keysKey, &keyMaps); err != nil {
+		return nil, fmt.Errorf("error reading TUI keys: %s", err.Error())
+	}
+
+	for keyMap, actions := range keyMaps {
+		for action, keys := range actions {
+			if len(keys) == 0 {
+				return nil, fmt.Errorf("TUI keys %q: action %q has no keys", keyMap, action)
+			}
+			for i, key := range keys {
+				switch key {
+				case "":
+					return nil, fmt.Errorf("TUI keys %q: action %q has an empty key", keyMap, action)
+				case "space":
+					keys[i] = " "
+				}
+			}
+		}
+	}
+	return keyMaps, nil
+}
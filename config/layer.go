@@ -0,0 +1,218 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/luevano/mangal/meta"
+	"github.com/luevano/mangal/util/afs"
+	"github.com/spf13/viper"
+)
+
+// Layer is where a config value comes from, in increasing order of precedence:
+// default, file, profile, project, env and override.
+type Layer string
+
+const (
+	LayerDefault Layer = "default"
+	// LayerFile is the main config file.
+	LayerFile Layer = "file"
+	// LayerProfile is the selected profile file, see Profile.
+	LayerProfile Layer = "profile"
+	// LayerProject is the .mangal.toml of the working directory.
+	LayerProject Layer = "project"
+	LayerEnv     Layer = "env"
+	// LayerOverride are the values set at runtime (flags or Set).
+	LayerOverride Layer = "override"
+)
+
+// Source of a config value.
+type Source struct {
+	Layer Layer `json:"layer"`
+	// Path of the file the value was read from, if any.
+	Path string `json:"path,omitempty"`
+}
+
+func (s Source) String() string {
+	if s.Path == "" {
+		return string(s.Layer)
+	}
+	return string(s.Layer) + " (" + s.Path + ")"
+}
+
+// EnvProfile is the environment variable to select the profile.
+const EnvProfile = "MANGAL_PROFILE"
+
+var (
+	// Profile is the name of the profile that overlays the main config file,
+	// read from <config dir>/profiles/<name>.toml.
+	Profile = os.Getenv(EnvProfile)
+	// ProjectFilename is the config file overlaying
+	// the others, read from the working directory.
+	// Only the ProjectKeys are read from it.
+	ProjectFilename = "." + filename
+	// ProjectKeys are the key prefixes allowed in the project config, as it's
+	// read from any working directory. Excludes the keys that run code, send
+	// requests or write outside of the downloads (scripts, providers,
+	// notifications, metrics, cache and logs).
+	ProjectKeys = []string{
+		"download.",
+		"read.",
+		"library.",
+		"tui.",
+		"providers.filter.",
+		devicesKey + ".",
+		fallbackKey + ".",
+	}
+
+	// sources of the values read from the config files
+	sources = make(map[string]Source)
+	// overlays are the profile and project files read
+	overlays []string
+	// ignored project keys already warned about, as the config can be loaded twice
+	ignored = make(map[string]struct{})
+)
+
+// ProfilesDir is the directory where the profiles are looked for.
+func ProfilesDir() string {
+	return filepath.Join(dir, "profiles")
+}
+
+// Profiles returns the names of the available profiles.
+func Profiles() ([]string, error) {
+	entries, err := afs.Afero.ReadDir(ProfilesDir())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var profiles []string
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".toml" {
+			profiles = append(profiles, strings.TrimSuffix(entry.Name(), ".toml"))
+		}
+	}
+	return profiles, nil
+}
+
+// Overlays returns the profile and project config files loaded.
+func Overlays() []string {
+	return slices.Clone(overlays)
+}
+
+// GetSource returns where the value of the key comes from.
+func GetSource(key string) Source {
+	source, ok := sources[key]
+	if ok && source.Layer == LayerOverride {
+		return source
+	}
+	// same environment variable as the one viper looks for
+	if _, ok := os.LookupEnv(strings.ToUpper(meta.AppName + "_" + key)); ok {
+		return Source{Layer: LayerEnv}
+	}
+	if ok {
+		return source
+	}
+	return Source{Layer: LayerDefault}
+}
+
+// loadLayers records the keys read from the main config file
+// and merges the profile and project files on top of it.
+func loadLayers() error {
+	// the runtime overrides are kept between loads
+	maps.DeleteFunc(sources, func(_ string, source Source) bool {
+		return source.Layer != LayerOverride
+	})
+	overlays = nil
+
+	if file := viper.ConfigFileUsed(); file != "" {
+		for key := range fields {
+			if viper.InConfig(key) {
+				sources[key] = Source{Layer: LayerFile, Path: file}
+			}
+		}
+	}
+
+	if Profile != "" {
+		path := filepath.Join(ProfilesDir(), Profile+".toml")
+		found, err := mergeLayer(LayerProfile, path)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("profile %q not found in %s", Profile, ProfilesDir())
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	if _, err := mergeLayer(LayerProject, filepath.Join(wd, ProjectFilename)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func isProjectKey(key string) bool {
+	return slices.ContainsFunc(ProjectKeys, func(prefix string) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
+
+// projectSettings returns the settings of the project config with only the
+// ProjectKeys, the rest are ignored with a warning (to stderr, log depends on config).
+func projectSettings(v *viper.Viper, path string) map[string]any {
+	allowed := viper.New()
+	for _, key := range v.AllKeys() {
+		if !isProjectKey(key) {
+			if _, ok := ignored[path+key]; !ok {
+				fmt.Fprintf(os.Stderr, "Config key %q is not allowed in the project config %s, ignoring it\n", key, path)
+				ignored[path+key] = struct{}{}
+			}
+			continue
+		}
+		allowed.Set(key, v.Get(key))
+	}
+	return allowed.AllSettings()
+}
+
+// mergeLayer merges the config file into the config, false if it doesn't exist.
+func mergeLayer(layer Layer, path string) (bool, error) {
+	if slices.Contains(overlays, path) {
+		return true, nil
+	}
+
+	v := viper.New()
+	v.SetFs(afs.Afero.Fs)
+	v.SetConfigFile(path)
+	v.SetConfigType("toml")
+	if err := v.ReadInConfig(); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error reading %s config %s: %s", layer, path, err.Error())
+	}
+
+	settings := v.AllSettings()
+	if layer == LayerProject {
+		settings = projectSettings(v, path)
+	}
+	if err := viper.MergeConfigMap(settings); err != nil {
+		return false, fmt.Errorf("error merging %s config %s: %s", layer, path, err.Error())
+	}
+	for _, key := range v.AllKeys() {
+		if layer != LayerProject || isProjectKey(key) {
+			sources[key] = Source{Layer: layer, Path: path}
+		}
+	}
+	overlays = append(overlays, path)
+	return true, nil
+}
@@ -1,7 +1,11 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"path/filepath"
+	"reflect"
 
 	"github.com/adrg/xdg"
 	"github.com/luevano/mangal/meta"
@@ -23,6 +27,53 @@ func Get(key string) any {
 	return viper.Get(key)
 }
 
+// GetRaw gets the value of the key in its raw form, as stored in the config file.
+func GetRaw(key string) (any, error) {
+	if ok := Exists(key); !ok {
+		return nil, errorf("GetRaw: key %q is not set", key)
+	}
+	return fields[key].Marshal(Get(key))
+}
+
+// ConvertRaw converts the value to the raw type of the key, useful for
+// loosely typed values (for example, numbers that are always float64).
+func ConvertRaw(key string, value any) (any, error) {
+	if ok := Exists(key); !ok {
+		return nil, errorf("ConvertRaw: key %q is not set", key)
+	}
+	raw := fields[key].Raw
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		return nil, errorf("ConvertRaw: nil value for key %q", key)
+	}
+	if v.Type() == raw {
+		return value, nil
+	}
+	if isNumber(v.Kind()) && isNumber(raw.Kind()) {
+		converted := v.Convert(raw)
+		// don't silently truncate decimals
+		if converted.Convert(v.Type()).Interface() != value {
+			return nil, errorf("ConvertRaw: can't convert %v to %s for key %q without losing precision", value, raw, key)
+		}
+		return converted.Interface(), nil
+	}
+	if v.Kind() == raw.Kind() {
+		return v.Convert(raw).Interface(), nil
+	}
+	return nil, errorf("ConvertRaw: can't convert %v (%T) to %s for key %q", value, value, raw, key)
+}
+
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
 // Default value for the key.
 func Default(key string) any {
 	if ok := Exists(key); !ok {
@@ -47,6 +98,7 @@ func Set(key string, value any) error {
 		return errorf("Set: %s", err.Error())
 	}
 	viper.Set(key, value)
+	sources[key] = Source{Layer: LayerOverride}
 	return nil
 }
 
@@ -117,6 +169,7 @@ func Init() {
 // Load the config file and validates all config keys.
 //
 // If path is empty, it will try to load from xdg.ConfigHome then from xdg.Home.
+// The Profile and the project config (ProjectFilename) are merged on top of it.
 func Load(path string) error {
 	if path != "" {
 		viper.SetConfigFile(path)
@@ -128,9 +181,15 @@ func Load(path string) error {
 			return errorf("Load: unexpected error reading in the config: %s", err.Error())
 		}
 	}
+	if err := loadLayers(); err != nil {
+		return errorf("Load: %s", err.Error())
+	}
 
 	// validate all values now that the config was read
 	for _, key := range viper.AllKeys() {
+		if isDeviceKey(key) || isWatchKey(key) || isKeysKey(key) || isFallbackKey(key) {
+			continue
+		}
 		if !Exists(key) {
 			fmt.Printf("Config key %q is not supported, update config file\n", key)
 			continue
@@ -146,22 +205,59 @@ func Load(path string) error {
 			return errorf("Load: %s", err.Error())
 		}
 	}
+	if err := validateDevices(); err != nil {
+		return errorf("Load: %s", err.Error())
+	}
+	if _, err := Watched(); err != nil {
+		return errorf("Load: %s", err.Error())
+	}
+	if _, err := FallbackChains(); err != nil {
+		return errorf("Load: %s", err.Error())
+	}
+	if _, err := TUIKeys(); err != nil {
+		return errorf("Load: %s", err.Error())
+	}
 	return nil
 }
 
-// Write current configuration to disk (to the set config directory/file).
+// Write current configuration to disk (to the set config file, or a new one in the config directory).
 //
-// Tries to write to the first path available.
+// Only the values of the config file itself, the defaults and the ones set at
+// runtime (Set) are written, the profile, project and env values are not.
 func Write() error {
-	switch viper.WriteConfig().(type) {
-	case nil:
-		return nil
-	case viper.ConfigFileNotFoundError:
-		if err := viper.SafeWriteConfig(); err != nil {
-			return errorf("Write: error writing new config file: %s", err.Error())
+	path := viper.ConfigFileUsed()
+	v := viper.New()
+	v.SetFs(afs.Afero.Fs)
+	v.SetConfigType("toml")
+	if path == "" {
+		path = filepath.Join(xdgConfig(), filename)
+	} else {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return errorf("Write: error reading the config file: %s", err.Error())
 		}
-		return nil
-	default:
-		return errorf("Write: unexpected error")
 	}
+
+	for key, field := range fields {
+		raw, err := field.Marshal(field.Default)
+		if err != nil {
+			return errorf("Write: error marshaling the default value for key %q: %s", key, err.Error())
+		}
+		v.SetDefault(key, raw)
+	}
+	for key, source := range sources {
+		if source.Layer != LayerOverride {
+			continue
+		}
+		raw, err := GetRaw(key)
+		if err != nil {
+			return errorf("Write: %s", err.Error())
+		}
+		v.Set(key, raw)
+	}
+
+	if err := v.WriteConfigAs(path); err != nil {
+		return errorf("Write: error writing the config file: %s", err.Error())
+	}
+	return nil
 }
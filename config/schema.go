@@ -5,10 +5,12 @@ import (
 
 	"github.com/luevano/libmangal"
 	"github.com/luevano/mangal/theme/icon"
+	imageutil "github.com/luevano/mangal/util/image"
 )
 
 type config struct {
 	Icons        *entry[string, icon.Type]
+	Theme        *entry[string, string]
 	Cache        configCache
 	CLI          configCLI
 	Read         configRead
@@ -17,6 +19,9 @@ type config struct {
 	Providers    configProviders
 	Library      configLibrary
 	Notification configNotification
+	Script       configScript
+	Log          configLog
+	Metrics      configMetrics
 }
 
 type configCLI struct {
@@ -47,6 +52,9 @@ type configDownload struct {
 	Volume       configDownloadVolume
 	Chapter      configDownloadChapter
 	Metadata     configDownloadMetadata
+	Fallback     configDownloadFallback
+	Image        configDownloadImage
+	Merge        configDownloadMerge
 }
 
 type configDownloadProvider struct {
@@ -71,6 +79,25 @@ type configDownloadChapter struct {
 	NameTemplate *entry[string, string]
 }
 
+type configDownloadImage struct {
+	Format       *entry[string, imageutil.Format]
+	Quality      *entry[int64, int]
+	Grayscale    *entry[bool, bool]
+	MaxWidth     *entry[int64, int]
+	MaxHeight    *entry[int64, int]
+	AutoCrop     *entry[bool, bool]
+	SplitSpreads *entry[bool, bool]
+	LeftToRight  *entry[bool, bool]
+}
+
+type configDownloadMerge struct {
+	NameTemplate *entry[string, string]
+}
+
+type configDownloadFallback struct {
+	Providers *entry[string, string]
+}
+
 type configDownloadMetadata struct {
 	Strict                  *entry[bool, bool]
 	Search                  *entry[bool, bool]
@@ -94,15 +121,20 @@ type configTUIChapter struct {
 	ShowNumber         *entry[bool, bool]
 	ShowDate           *entry[bool, bool]
 	ShowGroup          *entry[bool, bool]
+	PreferGroup        *entry[string, string]
 }
 
 type configProviders struct {
-	Path        *entry[string, string]
-	Parallelism *entry[int64, uint8]
-	Headless    configProvidersHeadless
-	Filter      configProvidersFilter
-	MangaDex    configProvidersMangaDex
-	MangaPlus   configProvidersMangaPlus
+	Path               *entry[string, string]
+	Parallelism        *entry[int64, uint8]
+	RequirePermissions *entry[bool, bool]
+	RequireSignature   *entry[bool, bool]
+	TrustedKeys        *entry[string, string]
+	TrustedKeyring     *entry[string, string]
+	Headless           configProvidersHeadless
+	Filter             configProvidersFilter
+	MangaDex           configProvidersMangaDex
+	MangaPlus          configProvidersMangaPlus
 }
 
 type configCache struct {
@@ -140,10 +172,104 @@ type configProvidersMangaDex struct {
 type configNotification struct {
 	IncludeExisting  *entry[bool, bool]
 	IncludeDirectory *entry[bool, bool]
+	URLs             *entry[string, string]
 	Discord          configNotificationDiscord
+	Webhook          configNotificationWebhook
+	Ntfy             configNotificationNtfy
+	Gotify           configNotificationGotify
+	SMTP             configNotificationSMTP
+	Templates        configNotificationTemplates
+}
+
+// configNotificationTemplates are the downloaded chapters message templates,
+// empty per backend values fall back to the notification ones.
+type configNotificationTemplates struct {
+	Title   *entry[string, string]
+	Body    *entry[string, string]
+	Chapter *entry[string, string]
+}
+
+// configNotificationFilter are the per backend filters,
+// empty values fall back to the notification ones.
+type configNotificationFilter struct {
+	IncludeExisting  *entry[string, string]
+	IncludeDirectory *entry[string, string]
 }
 
 type configNotificationDiscord struct {
 	Username   *entry[string, string]
 	WebhookURL *entry[string, string]
+	Filter     configNotificationFilter
+	Templates  configNotificationTemplates
+}
+
+type configNotificationWebhook struct {
+	URL             *entry[string, string]
+	Method          *entry[string, string]
+	Headers         *entry[string, string]
+	PayloadTemplate *entry[string, string]
+	Filter          configNotificationFilter
+	Templates       configNotificationTemplates
+}
+
+type configNotificationNtfy struct {
+	URL       *entry[string, string]
+	Token     *entry[string, string]
+	Filter    configNotificationFilter
+	Templates configNotificationTemplates
+}
+
+type configNotificationGotify struct {
+	URL       *entry[string, string]
+	Token     *entry[string, string]
+	Filter    configNotificationFilter
+	Templates configNotificationTemplates
+}
+
+type configNotificationSMTP struct {
+	Host      *entry[string, string]
+	Port      *entry[int, int]
+	TLS       *entry[bool, bool]
+	Username  *entry[string, string]
+	Password  *entry[string, string]
+	From      *entry[string, string]
+	To        *entry[string, string]
+	Filter    configNotificationFilter
+	Templates configNotificationTemplates
+}
+
+type configScript struct {
+	Path    *entry[string, string]
+	Sandbox *entry[bool, bool]
+	FS      configScriptFS
+	HTTP    configScriptHTTP
+	Hooks   configScriptHooks
+}
+
+type configScriptFS struct {
+	Roots    *entry[string, string]
+	ReadOnly *entry[bool, bool]
+}
+
+type configScriptHTTP struct {
+	Enabled      *entry[bool, bool]
+	AllowedHosts *entry[string, string]
+	Timeout      *entry[string, string]
+}
+
+type configScriptHooks struct {
+	Enabled *entry[bool, bool]
+	Path    *entry[string, string]
+}
+
+type configLog struct {
+	Level   *entry[string, string]
+	MaxSize *entry[int, int]
+	MaxAge  *entry[string, string]
+}
+
+type configMetrics struct {
+	Enabled *entry[bool, bool]
+	Address *entry[string, string]
+	File    *entry[string, string]
 }
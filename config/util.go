@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"path/filepath"
+	"strings"
 
 	"github.com/adrg/xdg"
 )
@@ -28,3 +29,27 @@ func expandPath(path string) (string, error) {
 
 	return filepath.Join(xdg.Home, path[1:]), nil
 }
+
+// expandPaths expands each path (see expandPath),
+// keeping as-is the ones that can't be expanded.
+func expandPaths(paths []string) []string {
+	for i, path := range paths {
+		if expanded, err := expandPath(path); err == nil {
+			paths[i] = expanded
+		}
+	}
+	return paths
+}
+
+// splitList splits a comma separated list into its trimmed elements.
+// An empty (or whitespace only) string results in an empty list.
+func splitList(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	list := strings.Split(s, ",")
+	for i, e := range list {
+		list[i] = strings.TrimSpace(e)
+	}
+	return list
+}
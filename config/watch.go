@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// watchKey is the config table where the watched (followed) series live, as in:
+//
+//	[watch.berserk]
+//	provider = "mangadex"
+//	query = "berserk"
+//	manga_selector = "exact"
+const watchKey = "watch"
+
+// Watch is a followed series, checked for new chapters without downloading them.
+type Watch struct {
+	Name     string `mapstructure:"-"`
+	Provider string `mapstructure:"provider"`
+	Query    string `mapstructure:"query"`
+	// MangaSelector needs to select a single manga, defaults to "first".
+	MangaSelector string `mapstructure:"manga_selector"`
+	// ChapterSelector defaults to "all".
+	ChapterSelector string `mapstructure:"chapter_selector"`
+}
+
+// isWatchKey returns true if the key belongs to a watched series.
+func isWatchKey(key string) bool {
+	return strings.HasPrefix(key, watchKey+".")
+}
+
+// Watched returns the watched series, sorted by name.
+func Watched() ([]Watch, error) {
+	var watched map[string]Watch
+	if err := viper.UnmarshalKey(watchKey, &watched); err != nil {
+		return nil, fmt.Errorf("error reading watched series: %s", err.Error())
+	}
+
+	series := make([]Watch, 0, len(watched))
+	for _, name := range slices.Sorted(maps.Keys(watched)) {
+		w := watched[name]
+		w.Name = name
+		if w.Provider == "" || w.Query == "" {
+			return nil, fmt.Errorf("watched series %q needs a provider and a query", name)
+		}
+		if w.MangaSelector == "" {
+			w.MangaSelector = "first"
+		}
+		if w.ChapterSelector == "" {
+			w.ChapterSelector = "all"
+		}
+		series = append(series, w)
+	}
+	return series, nil
+}
+
+// GetWatched returns the watched series by name.
+func GetWatched(name string) (Watch, error) {
+	series, err := Watched()
+	if err != nil {
+		return Watch{}, err
+	}
+	names := make([]string, len(series))
+	for i, w := range series {
+		if w.Name == name {
+			return w, nil
+		}
+		names[i] = w.Name
+	}
+	return Watch{}, fmt.Errorf("unknown watched series %q, available: %s", name, strings.Join(names, ", "))
+}
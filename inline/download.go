@@ -9,20 +9,39 @@ import (
 	"strings"
 	"time"
 
+	"github.com/luevano/libmangal"
+	"github.com/luevano/libmangal/mangadata"
 	"github.com/luevano/libmangal/metadata"
 	"github.com/luevano/mangal/client"
 	"github.com/luevano/mangal/config"
 	"github.com/luevano/mangal/log"
+	"github.com/luevano/mangal/metrics"
 	"github.com/luevano/mangal/notify"
+	"github.com/luevano/mangal/script/hook"
 	"github.com/luevano/mangal/util/chapter"
 )
 
 func RunDownload(ctx context.Context, args Args) error {
+	if args.Merge != "" && args.Merge != MergeVolume && args.Merge != MergeRange {
+		return fmt.Errorf("invalid merge mode %q, needs to be %q or %q", args.Merge, MergeVolume, MergeRange)
+	}
+
+	chains, err := config.FallbackChains()
+	if err != nil {
+		return notify.SendError(err)
+	}
+	fallback := client.NewFallback(config.FallbackProviders(), chains)
 	client, err := client.NewClientByID(ctx, args.Provider)
 	if err != nil {
 		return notify.SendError(err)
 	}
 
+	hooks, err := hook.Load(client)
+	if err != nil {
+		return notify.SendError(err)
+	}
+	defer hooks.Close()
+
 	mangas, err := client.SearchMangas(ctx, args.Query)
 	if err != nil {
 		return notify.SendError(err)
@@ -80,6 +99,7 @@ func RunDownload(ctx context.Context, args Args) error {
 	for i, ch := range rawChapters {
 		chapters[i] = &chapter.Chapter{
 			Chapter: ch,
+			Source:  args.Provider,
 		}
 	}
 
@@ -90,11 +110,49 @@ func RunDownload(ctx context.Context, args Args) error {
 		downloadOptions.SearchMetadata = false
 	}
 
+	if args.Merge != "" {
+		toMerge := make(chapter.Chapters, 0, len(chapters))
+		for _, ch := range chapters {
+			download, err := hooks.BeforeDownload(ctx, ch)
+			if err != nil {
+				ch.Err = err
+				continue
+			}
+			if !download {
+				hook.Skip(ch)
+				continue
+			}
+			toMerge = append(toMerge, ch)
+		}
+		if len(toMerge) != 0 {
+			if err := runMerge(ctx, client, hooks, args, toMerge, downloadOptions); err != nil {
+				return notify.SendError(err)
+			}
+		}
+		for _, ch := range chapters.Failed() {
+			hooks.OnError(ctx, ch)
+		}
+		hooks.AfterDownloadBatch(ctx, chapters)
+		metrics.Chapters(chapters)
+		return notify.Send(chapters)
+	}
+
 	// FIX: rework this mess
 	// TODO: make configurable
 	maxRetries := 10
 	retryCount := 0
 	for i, ch := range chapters {
+		download, err := hooks.BeforeDownload(ctx, ch)
+		if err != nil {
+			ch.Err = err
+			hooks.OnError(ctx, ch)
+			continue
+		}
+		if !download {
+			hook.Skip(ch)
+			continue
+		}
+
 		retry := true
 		for retry {
 			retry = false
@@ -126,11 +184,23 @@ func RunDownload(ctx context.Context, args Args) error {
 					continue
 				}
 
-				// In case that the error is not due to 429 code, just continue to the next chapter
-				// as ch.Down will not be available
-				if args.Provider == "mango-mangaplus" && i != len(chapters)-1 {
-					time.Sleep(time.Second)
+				// In case that the error is not due to 429 code, try the fallback providers
+				// and if still failed just continue to the next chapter as ch.Down will not be available
+				if fallback.Enabled() {
+					fallback.Download(ctx, ch, downloadOptions)
+				}
+				if ch.Failed() {
+					hooks.OnError(ctx, ch)
+					if args.Provider == "mango-mangaplus" && i != len(chapters)-1 {
+						time.Sleep(time.Second)
+					}
+					continue
 				}
+			}
+
+			if err := hooks.AfterChapterDownloaded(ctx, ch); err != nil {
+				ch.Err = err
+				hooks.OnError(ctx, ch)
 				continue
 			}
 
@@ -150,5 +220,89 @@ func RunDownload(ctx context.Context, args Args) error {
 			}
 		}
 	}
+	hooks.AfterDownloadBatch(ctx, chapters)
+	metrics.Chapters(chapters)
 	return notify.Send(chapters)
 }
+
+// runMerge downloads the chapters merged by volume or as a single range, depending on the args.
+//
+// The after_chapter_downloaded hooks run for each chapter of a merged file,
+// if one of them moves the file the rest of the chapters get the new location.
+func runMerge(
+	ctx context.Context,
+	c *libmangal.Client,
+	hooks *hook.Hooks,
+	args Args,
+	chapters chapter.Chapters,
+	downloadOptions libmangal.DownloadOptions,
+) error {
+	var groups []chapter.Chapters
+	switch args.Merge {
+	case MergeVolume:
+		index := make(map[float32]int)
+		for _, ch := range chapters {
+			number := ch.Chapter.Volume().Info().Number
+			i, ok := index[number]
+			if !ok {
+				i = len(groups)
+				index[number] = i
+				groups = append(groups, nil)
+			}
+			groups[i] = append(groups[i], ch)
+		}
+	case MergeRange:
+		groups = []chapter.Chapters{chapters}
+	}
+
+	for _, group := range groups {
+		rawChapters := make([]mangadata.Chapter, len(group))
+		for i, ch := range group {
+			rawChapters[i] = ch.Chapter
+		}
+
+		var merged *client.Merged
+		var err error
+		switch args.Merge {
+		case MergeVolume:
+			merged, err = client.MergeVolume(ctx, c, rawChapters[0].Volume(), rawChapters, downloadOptions)
+		case MergeRange:
+			merged, err = client.MergeRange(ctx, c, rawChapters, downloadOptions)
+		}
+		for _, ch := range group {
+			if err != nil {
+				ch.Err = err
+			} else {
+				ch.Down = merged.DownloadedChapter(ch.Chapter)
+			}
+		}
+		if err != nil {
+			log.Log("error merging chapters: %s", err.Error())
+			continue
+		}
+
+		for _, ch := range group {
+			if err := hooks.AfterChapterDownloaded(ctx, ch); err != nil {
+				ch.Err = err
+				continue
+			}
+			merged.Directory, merged.Filename = ch.Down.Directory, ch.Down.Filename
+			for _, other := range group {
+				other.Down.Directory, other.Down.Filename = merged.Directory, merged.Filename
+			}
+		}
+
+		if args.JSONOutput {
+			m, err := json.Marshal(merged)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(m))
+		} else {
+			fmt.Println(merged.Path())
+		}
+		// Searching metadata once is enough
+		downloadOptions.SearchMetadata = false
+	}
+	return nil
+}
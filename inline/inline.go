@@ -30,8 +30,16 @@ type Args struct {
 	AnilistID              int    `json:"anilist_id"`
 	AnilistDisable         bool   `json:"anilist_disable"`
 	JSONOutput             bool   `json:"json_output,omitempty"`
+	Merge                  string `json:"merge,omitempty"`
 }
 
+const (
+	// MergeVolume merges the selected chapters of each volume into one file.
+	MergeVolume = "volume"
+	// MergeRange merges all the selected chapters into one file.
+	MergeRange = "range"
+)
+
 type MangaSelectorError struct {
 	selector  string
 	extraInfo string
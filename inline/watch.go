@@ -0,0 +1,235 @@
+package inline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/luevano/libmangal"
+	"github.com/luevano/libmangal/mangadata"
+	"github.com/luevano/mangal/client"
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/notify"
+	"github.com/luevano/mangal/path"
+	"github.com/luevano/mangal/util/afs"
+	"github.com/luevano/mangal/util/cache"
+	"github.com/luevano/mangal/util/chapter"
+	stringutil "github.com/luevano/mangal/util/string"
+)
+
+// watchStateFilename is the file (in the cache dir) keeping
+// the chapters already seen of each watched series.
+const watchStateFilename = "watch.json"
+
+// WatchResult is the result of checking a watched series.
+type WatchResult struct {
+	Watch config.Watch
+	// Manga is the title of the selected manga.
+	Manga string
+	// New are the chapters not seen before and not downloaded.
+	New chapter.Chapters
+	// First is true if the series wasn't checked before, its
+	// chapters are only recorded as seen, without notifying.
+	First bool
+	Err   error
+}
+
+// watchState are the seen chapter keys by watched series name.
+type watchState map[string][]string
+
+// RunWatch checks the watched series for new chapters on their providers,
+// without downloading them, and notifies about them.
+//
+// The new chapters are only recorded as seen once notified, so they're
+// notified again on the next check if the notification failed.
+//
+// Errors of each series are notified and kept in their result,
+// the returned error is for the notification backends and the state file.
+func RunWatch(ctx context.Context, series []config.Watch) ([]WatchResult, error) {
+	notifiers, err := notify.Notifiers()
+	if err != nil {
+		return nil, fmt.Errorf("error loading the notification backends: %s", err.Error())
+	}
+	if len(notifiers) == 0 {
+		return nil, errors.New("no notification backends configured")
+	}
+
+	statePath := filepath.Join(path.CacheDir(), watchStateFilename)
+	state, err := readWatchState(statePath)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]WatchResult, len(series))
+	for i, w := range series {
+		results[i] = runWatched(ctx, w, state)
+	}
+
+	if err := writeWatchState(statePath, state); err != nil {
+		return results, fmt.Errorf("error saving the watch state: %s", err.Error())
+	}
+	return results, nil
+}
+
+// runWatched checks and notifies the watched series, updating
+// its seen chapters in the state if notified.
+func runWatched(ctx context.Context, w config.Watch, state watchState) WatchResult {
+	result := WatchResult{Watch: w}
+	seen, checked := state[w.Name]
+	check, err := checkWatched(ctx, w, seen)
+	if err != nil {
+		result.Err = notify.SendError(fmt.Errorf("watched series %q: %w", w.Name, err))
+		return result
+	}
+	result.Manga = check.title
+	result.First = !checked
+	if checked {
+		result.New = check.new
+	}
+
+	if err := notify.SendNew(result.New); err != nil {
+		result.Err = err
+		return result
+	}
+	state[w.Name] = check.seen
+
+	// kept as new (for the TUI) until read or downloaded
+	if len(result.New) > 0 {
+		numbers := make(cache.ChapterNumbers, len(result.New))
+		for i, ch := range result.New {
+			numbers[i] = ch.Chapter.Info().Number
+		}
+		result.Err = cache.AddNewChapters(check.mangaKey, numbers)
+	}
+	return result
+}
+
+// watchCheck is a checked watched series, pending its notification.
+type watchCheck struct {
+	title    string
+	mangaKey string
+	// new are the chapters not seen before and not downloaded.
+	new chapter.Chapters
+	// seen are the seen chapter keys, including the new ones.
+	seen []string
+}
+
+// checkWatched returns the chapters of the watched series that
+// aren't in the seen chapter keys.
+func checkWatched(ctx context.Context, w config.Watch, seen []string) (watchCheck, error) {
+	args := Args{
+		Query:           w.Query,
+		Provider:        w.Provider,
+		MangaSelector:   w.MangaSelector,
+		ChapterSelector: w.ChapterSelector,
+	}
+
+	c, err := client.NewClientByID(ctx, args.Provider)
+	if err != nil {
+		return watchCheck{}, err
+	}
+	mangas, err := c.SearchMangas(ctx, args.Query)
+	if err != nil {
+		return watchCheck{}, err
+	}
+	if len(mangas) == 0 {
+		return watchCheck{}, fmt.Errorf("no mangas found with provider ID %q and query %q", args.Provider, args.Query)
+	}
+	mangaResults, err := getSelectedMangaResults(args, mangas)
+	if err != nil {
+		return watchCheck{}, err
+	}
+	if len(mangaResults) != 1 {
+		return watchCheck{}, fmt.Errorf("invalid manga selector %q, needs to select 1 manga only", args.MangaSelector)
+	}
+
+	manga := mangaResults[0].Manga
+	rawChapters, err := getChapters(ctx, c, args, manga)
+	if err != nil {
+		return watchCheck{}, err
+	}
+
+	check := watchCheck{
+		title:    manga.Info().Title,
+		mangaKey: cache.MangaKey(c.Info().ID, manga.Info().ID),
+		seen:     append([]string(nil), seen...),
+	}
+	seenSet := make(map[string]struct{}, len(seen))
+	for _, key := range seen {
+		seenSet[key] = struct{}{}
+	}
+	for _, ch := range rawChapters {
+		key := watchKey(ch)
+		if _, ok := seenSet[key]; ok {
+			continue
+		}
+		seenSet[key] = struct{}{}
+		check.seen = append(check.seen, key)
+		if isDownloaded(c, ch) {
+			continue
+		}
+		check.new = append(check.new, &chapter.Chapter{
+			Chapter: ch,
+			Source:  args.Provider,
+		})
+	}
+	return check, nil
+}
+
+// watchKey identifies the chapter, its URL if available else its number.
+func watchKey(ch mangadata.Chapter) string {
+	if url := ch.Info().URL; url != "" {
+		return url
+	}
+	return stringutil.FormatFloa32(ch.Info().Number)
+}
+
+// isDownloaded returns true if the chapter exists in the download
+// directory, in any format.
+func isDownloaded(c *libmangal.Client, ch mangadata.Chapter) bool {
+	options := config.DownloadOptions()
+	directory := options.Directory
+	if options.CreateProviderDir {
+		directory = filepath.Join(directory, c.ProviderName(c.Info()))
+	}
+	if options.CreateMangaDir {
+		directory = filepath.Join(directory, c.MangaName(ch.Volume().Manga()))
+	}
+	if options.CreateVolumeDir {
+		directory = filepath.Join(directory, c.VolumeName(ch.Volume()))
+	}
+
+	for _, format := range libmangal.FormatValues() {
+		exists, err := afs.Afero.Exists(filepath.Join(directory, c.ChapterName(ch, format)))
+		if err == nil && exists {
+			return true
+		}
+	}
+	return false
+}
+
+func readWatchState(path string) (watchState, error) {
+	state := make(watchState)
+	data, err := afs.Afero.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("error reading watch state %q: %s", path, err.Error())
+	}
+	return state, nil
+}
+
+func writeWatchState(path string, state watchState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return afs.Afero.WriteFile(path, data, config.Download.ModeFile.Get())
+}
@@ -0,0 +1,205 @@
+// Package library indexes the downloaded mangas in the library path.
+package library
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/luevano/libmangal"
+	"github.com/luevano/libmangal/metadata"
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/util/afs"
+)
+
+// Chapter is a downloaded chapter file (or directory for FormatImages).
+type Chapter struct {
+	// Name is the filename without the format extension.
+	Name   string           `json:"name"`
+	Path   string           `json:"path"`
+	Format libmangal.Format `json:"format"`
+	// Volume is the volume directory name, if any.
+	Volume string `json:"volume,omitempty"`
+}
+
+// Manga is a manga directory containing downloaded chapters.
+type Manga struct {
+	// Name is the directory name.
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Series is the series.json metadata, if any.
+	Series   *metadata.SeriesJSON `json:"series,omitempty"`
+	Chapters []Chapter            `json:"chapters"`
+}
+
+// Title returns the series name if available, else the directory name.
+func (m *Manga) Title() string {
+	if m.Series != nil && m.Series.Name != "" {
+		return m.Series.Name
+	}
+	return m.Name
+}
+
+// Index of the mangas found under Root.
+type Index struct {
+	Root   string   `json:"root"`
+	Mangas []*Manga `json:"mangas"`
+}
+
+// Find the manga by its directory name or its series name.
+func (i *Index) Find(name string) (*Manga, bool) {
+	for _, manga := range i.Mangas {
+		if manga.Name == name || manga.Title() == name {
+			return manga, true
+		}
+	}
+	return nil, false
+}
+
+// Path returns the configured library path,
+// which falls back to the download path.
+func Path() string {
+	if path := config.Library.Path.Get(); path != "" {
+		return path
+	}
+	return config.Download.Path.Get()
+}
+
+// Scan walks the root directory and indexes the downloaded chapters.
+//
+// A chapter belongs to the nearest directory containing a series.json,
+// its own directory or the one above (when in a volume directory).
+// Without series.json the chapter directory is used as the manga directory.
+func Scan(root string) (*Index, error) {
+	index := &Index{Root: root}
+	mangas := make(map[string]*Manga)
+
+	addChapter := func(path string, format libmangal.Format) error {
+		dir := filepath.Dir(path)
+		mangaDir, volume := dir, ""
+		if !hasSeriesJSON(dir) && dir != root && hasSeriesJSON(filepath.Dir(dir)) {
+			mangaDir, volume = filepath.Dir(dir), filepath.Base(dir)
+		}
+
+		manga, ok := mangas[mangaDir]
+		if !ok {
+			var err error
+			manga, err = newManga(mangaDir)
+			if err != nil {
+				return err
+			}
+			mangas[mangaDir] = manga
+			index.Mangas = append(index.Mangas, manga)
+		}
+		manga.Chapters = append(manga.Chapters, Chapter{
+			Name:   strings.TrimSuffix(filepath.Base(path), format.Extension()),
+			Path:   path,
+			Format: format,
+			Volume: volume,
+		})
+		return nil
+	}
+
+	err := afs.Afero.Walk(root, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path == root {
+				return nil
+			}
+			images, err := isImagesDir(path)
+			if err != nil || !images {
+				return err
+			}
+			if err := addChapter(path, libmangal.FormatImages); err != nil {
+				return err
+			}
+			return filepath.SkipDir
+		}
+
+		format, ok := formatOf(path)
+		if !ok {
+			return nil
+		}
+		return addChapter(path, format)
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(index.Mangas, func(a, b *Manga) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	for _, manga := range index.Mangas {
+		slices.SortFunc(manga.Chapters, func(a, b Chapter) int {
+			return strings.Compare(a.Path, b.Path)
+		})
+	}
+	return index, nil
+}
+
+func newManga(dir string) (*Manga, error) {
+	manga := &Manga{
+		Name: filepath.Base(dir),
+		Path: dir,
+	}
+
+	data, err := afs.Afero.ReadFile(filepath.Join(dir, metadata.FilenameSeriesJSON))
+	if errors.Is(err, fs.ErrNotExist) {
+		return manga, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var series struct {
+		Metadata metadata.SeriesJSON `json:"metadata"`
+	}
+	// a broken series.json shouldn't break the whole index
+	if err := json.Unmarshal(data, &series); err == nil {
+		manga.Series = &series.Metadata
+	}
+	return manga, nil
+}
+
+func hasSeriesJSON(dir string) bool {
+	exists, _ := afs.Afero.Exists(filepath.Join(dir, metadata.FilenameSeriesJSON))
+	return exists
+}
+
+// formatOf returns the format of the chapter file by its extension.
+func formatOf(path string) (libmangal.Format, bool) {
+	for _, format := range libmangal.FormatValues() {
+		ext := format.Extension()
+		if ext != "" && strings.HasSuffix(path, ext) {
+			return format, true
+		}
+	}
+	return 0, false
+}
+
+var imageExtensions = []string{".jpg", ".jpeg", ".png", ".webp", ".gif", ".avif"}
+
+// isImagesDir returns true if the directory only contains images (FormatImages chapter).
+func isImagesDir(dir string) (bool, error) {
+	entries, err := afs.Afero.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+	if len(entries) == 0 {
+		return false, nil
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !slices.Contains(imageExtensions, strings.ToLower(filepath.Ext(entry.Name()))) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,82 @@
+package library
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/luevano/libmangal"
+)
+
+func writeFiles(t *testing.T, root string, files ...string) {
+	t.Helper()
+	for _, file := range files {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root,
+		"Berserk/series.json",
+		"Berserk/cover.jpg",
+		"Berserk/Vol. 1/[0001] The Black Swordsman.cbz",
+		"Berserk/Vol. 1/[0002] The Brand.cbz",
+		"Vinland Saga/[0001] Normanni.pdf",
+		"Vinland Saga/[0002] Sword/0001.jpg",
+		"Vinland Saga/[0002] Sword/0002.png",
+		"Vinland Saga/notes.txt",
+	)
+
+	index, err := Scan(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(index.Mangas) != 2 {
+		t.Fatalf("got %d mangas, want 2", len(index.Mangas))
+	}
+
+	berserk, ok := index.Find("Berserk")
+	if !ok {
+		t.Fatal("Berserk not found")
+	}
+	if berserk.Series == nil {
+		t.Error("Berserk series.json not read")
+	}
+	if len(berserk.Chapters) != 2 {
+		t.Fatalf("got %d Berserk chapters, want 2", len(berserk.Chapters))
+	}
+	if ch := berserk.Chapters[0]; ch.Name != "[0001] The Black Swordsman" || ch.Volume != "Vol. 1" || ch.Format != libmangal.FormatCBZ {
+		t.Errorf("unexpected chapter %+v", ch)
+	}
+
+	vinland, ok := index.Find("Vinland Saga")
+	if !ok {
+		t.Fatal("Vinland Saga not found")
+	}
+	formats := []libmangal.Format{libmangal.FormatPDF, libmangal.FormatImages}
+	if len(vinland.Chapters) != len(formats) {
+		t.Fatalf("got %d Vinland Saga chapters, want %d", len(vinland.Chapters), len(formats))
+	}
+	for i, format := range formats {
+		if vinland.Chapters[i].Format != format {
+			t.Errorf("chapter %d format = %s, want %s", i, vinland.Chapters[i].Format, format)
+		}
+	}
+}
+
+func TestScanMissingRoot(t *testing.T) {
+	index, err := Scan(filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(index.Mangas) != 0 {
+		t.Errorf("got %d mangas, want 0", len(index.Mangas))
+	}
+}
@@ -0,0 +1,423 @@
+package library
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/luevano/libmangal"
+	"github.com/luevano/libmangal/mangadata"
+	"github.com/luevano/libmangal/metadata"
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/template"
+	"github.com/luevano/mangal/util/afs"
+)
+
+// Move of a library file (or directory for FormatImages) to its new path.
+type Move struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Conflict is the reason the move can't be done, if any.
+	Conflict string `json:"conflict,omitempty"`
+}
+
+// mangaFiles are the manga level files that follow the manga directory.
+var mangaFiles = []string{
+	metadata.FilenameSeriesJSON,
+	metadata.FilenameCoverJPG,
+	metadata.FilenameBannerJPG,
+}
+
+// PlanRename re-renders the manga, volume and chapter names of the
+// indexed mangas with the current name templates and returns the
+// needed moves, unchanged paths are not included.
+//
+// The template data is rebuilt from the stored series.json and the
+// ComicInfo.xml of the CBZ chapters. The provider information is not
+// stored, so the names whose template uses it are kept. Mangas with
+// incomplete stored metadata keep their directory name, volumes whose
+// number can't be parsed (see parseVolume) keep their directory name, and
+// chapters without ComicInfo.xml (or merged chapters) keep their filename,
+// as their data can't be recovered.
+func PlanRename(index *Index) ([]Move, error) {
+	var moves []Move
+	targets := make(map[string]struct{})
+
+	add := func(from, to string) error {
+		if from == to {
+			return nil
+		}
+		move := Move{From: from, To: to}
+		if _, ok := targets[to]; ok {
+			move.Conflict = "duplicate target"
+		} else if exists, err := afs.Afero.Exists(to); err != nil {
+			return err
+		} else if exists {
+			move.Conflict = "target already exists"
+		}
+		targets[to] = struct{}{}
+		moves = append(moves, move)
+		return nil
+	}
+
+	for _, manga := range index.Mangas {
+		stored, err := readStored(manga)
+		if err != nil {
+			return nil, err
+		}
+
+		mangaDir := manga.Path
+		if name, ok := stored.mangaName(); ok && name != "" && manga.Path != index.Root {
+			mangaDir = filepath.Join(filepath.Dir(manga.Path), name)
+		}
+		if mangaDir != manga.Path {
+			for _, file := range mangaFiles {
+				from := filepath.Join(manga.Path, file)
+				exists, err := afs.Afero.Exists(from)
+				if err != nil {
+					return nil, err
+				}
+				if exists {
+					if err := add(from, filepath.Join(mangaDir, file)); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+
+		for _, chapter := range manga.Chapters {
+			dir := mangaDir
+			volume, volumeOK := mangadata.VolumeInfo{}, chapter.Volume == ""
+			if chapter.Volume != "" {
+				name := chapter.Volume
+				if volume, volumeOK = parseVolume(chapter.Volume, stored.info); volumeOK {
+					if rendered, ok := renderVolume(volume, stored.info); ok {
+						name = rendered
+					}
+				}
+				dir = filepath.Join(dir, name)
+			}
+
+			name := chapter.Name
+			if info, ok := stored.chapters[chapter.Path]; ok {
+				if rendered, ok := renderChapter(info, volume, volumeOK, stored.info); ok {
+					name = rendered
+				}
+			}
+			if err := add(chapter.Path, filepath.Join(dir, name+chapter.Format.Extension())); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return moves, nil
+}
+
+// Rename applies the moves, skipping the ones with conflicts, and removes
+// the directories left empty. Existing files are never overwritten.
+func Rename(index *Index, moves []Move) error {
+	var errs []error
+	for _, move := range moves {
+		if move.Conflict != "" {
+			continue
+		}
+		if err := rename(move); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s", move.From, err.Error()))
+			continue
+		}
+		removeEmptyDirs(index.Root, filepath.Dir(move.From))
+	}
+	return errors.Join(errs...)
+}
+
+func rename(move Move) error {
+	exists, err := afs.Afero.Exists(move.To)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%q already exists", move.To)
+	}
+	if err := afs.Afero.MkdirAll(filepath.Dir(move.To), config.Download.ModeDir.Get()); err != nil {
+		return err
+	}
+	return afs.Afero.Rename(move.From, move.To)
+}
+
+// removeEmptyDirs removes the directory and its parents
+// while they're empty, up to the root (excluded).
+func removeEmptyDirs(root, dir string) {
+	for dir != root && strings.HasPrefix(dir, root) {
+		empty, err := afs.Afero.IsEmpty(dir)
+		if err != nil || !empty {
+			return
+		}
+		if err := afs.Afero.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// otherProvider renders differently than the empty provider
+// information in the name templates that use it.
+var otherProvider = libmangal.ProviderInfo{
+	ID:          "id",
+	Name:        "name",
+	Version:     "0.1.0",
+	Description: "description",
+	Website:     "website",
+}
+
+// withoutProvider renders the name with the empty provider information,
+// false if the name depends on it.
+func withoutProvider(render func(provider libmangal.ProviderInfo) string) (string, bool) {
+	name := render(libmangal.ProviderInfo{})
+	return name, name == render(otherProvider)
+}
+
+// volumeNumberRegex matches the numbers of the volume directory name.
+var volumeNumberRegex = regexp.MustCompile(`\d+(\.\d+)?`)
+
+// parseVolume finds the volume number the directory name was rendered with:
+// the number that renders the same name with the volume name template, or
+// the only number in the name. False if there's none or it's ambiguous.
+func parseVolume(name string, manga mangadata.MangaInfo) (mangadata.VolumeInfo, bool) {
+	var volumes []mangadata.VolumeInfo
+	for _, s := range volumeNumberRegex.FindAllString(name, -1) {
+		number, err := strconv.ParseFloat(s, 32)
+		if err != nil {
+			continue
+		}
+		volume := mangadata.VolumeInfo{Number: float32(number)}
+		if rendered, ok := renderVolume(volume, manga); ok && rendered == name {
+			return volume, true
+		}
+		volumes = append(volumes, volume)
+	}
+	if len(volumes) != 1 {
+		return mangadata.VolumeInfo{}, false
+	}
+	return volumes[0], true
+}
+
+func renderVolume(volume mangadata.VolumeInfo, manga mangadata.MangaInfo) (string, bool) {
+	return withoutProvider(func(provider libmangal.ProviderInfo) string {
+		return template.VolumeInfo(provider, volume, manga)
+	})
+}
+
+// renderChapter renders the chapter name, false if it depends on the
+// provider or on the volume when its number is unknown (!volumeOK).
+func renderChapter(
+	chapter mangadata.ChapterInfo,
+	volume mangadata.VolumeInfo,
+	volumeOK bool,
+	manga mangadata.MangaInfo,
+) (string, bool) {
+	render := func(volume mangadata.VolumeInfo) (string, bool) {
+		return withoutProvider(func(provider libmangal.ProviderInfo) string {
+			return template.ChapterInfo(provider, chapter, volume, manga)
+		})
+	}
+	name, ok := render(volume)
+	if !ok || volumeOK {
+		return name, ok
+	}
+	other, _ := render(mangadata.VolumeInfo{Number: volume.Number + 1})
+	return name, name == other
+}
+
+// storedManga is the manga template data recovered from the stored metadata.
+type storedManga struct {
+	info     mangadata.MangaInfo
+	metadata *mangadata.Metadata
+	// chapters info by chapter path, only for those with ComicInfo.xml.
+	chapters map[string]mangadata.ChapterInfo
+}
+
+// mangaName renders the manga name, false if it shouldn't be renamed.
+func (s *storedManga) mangaName() (string, bool) {
+	// the manga was downloaded with metadata, but not enough
+	// of it was stored to render the same name template
+	if s.metadata != nil && metadata.Validate(s.metadata) != nil {
+		return "", false
+	}
+	var meta metadata.Metadata
+	if s.metadata != nil {
+		meta = s.metadata
+	}
+	return withoutProvider(func(provider libmangal.ProviderInfo) string {
+		return template.MangaInfo(provider, s.info, meta)
+	})
+}
+
+// idCodeRegex matches the metadata ID of the default manga name template, "[al-30002]".
+var idCodeRegex = regexp.MustCompile(`\[([a-z]+)-(\d+)\]`)
+
+func readStored(manga *Manga) (*storedManga, error) {
+	stored := &storedManga{
+		info:     mangadata.MangaInfo{Title: manga.Name},
+		chapters: make(map[string]mangadata.ChapterInfo),
+	}
+
+	var first *comicInfo
+	for _, chapter := range manga.Chapters {
+		if chapter.Format != libmangal.FormatCBZ {
+			continue
+		}
+		ci, err := readComicInfo(chapter.Path)
+		if err != nil {
+			return nil, err
+		}
+		if ci == nil {
+			continue
+		}
+		if first == nil {
+			first = ci
+		}
+		if ci.merged() {
+			continue
+		}
+		stored.chapters[chapter.Path] = mangadata.ChapterInfo{
+			Title:  ci.Title,
+			URL:    ci.Web,
+			Number: ci.Number,
+			Date: metadata.Date{
+				Year:  ci.Year,
+				Month: ci.Month,
+				Day:   ci.Day,
+			},
+		}
+	}
+
+	series := manga.Series
+	if series == nil && first == nil {
+		return stored, nil
+	}
+
+	m := &mangadata.Metadata{}
+	if first != nil {
+		m.EnglishTitle = first.Series
+		m.AuthorList = splitList(first.Writer)
+		m.GenreList = splitList(first.Genre)
+		m.TagList = splitList(first.Tags)
+		m.ProviderPublisher = first.Publisher
+	}
+	if series != nil {
+		if series.Name != "" {
+			m.EnglishTitle = series.Name
+		}
+		m.Summary = series.DescriptionText
+		m.DateStart = metadata.Date{Year: series.Year}
+		m.PublicationStatus = seriesStatus(series.Status)
+		if series.ComicID != 0 {
+			m.ProviderID = strconv.Itoa(series.ComicID)
+			m.ProviderIDCode = string(metadata.IDCodeAnilist)
+			if match := idCodeRegex.FindStringSubmatch(manga.Name); match != nil && match[2] == m.ProviderID {
+				m.ProviderIDCode = match[1]
+			}
+		}
+	}
+	if title := m.Title(); title != "" {
+		stored.info.Title = title
+	}
+	stored.metadata = m
+	return stored, nil
+}
+
+// seriesStatus reverts the series.json status, which only
+// keeps if the manga ended or is still being published.
+func seriesStatus(status string) metadata.Status {
+	switch status {
+	case "Ended":
+		return metadata.StatusFinished
+	case "Continuing":
+		return metadata.StatusReleasing
+	default:
+		return ""
+	}
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+// comicInfo are the ComicInfo.xml fields needed to rebuild the template data.
+type comicInfo struct {
+	Title     string  `xml:"Title"`
+	Series    string  `xml:"Series"`
+	Number    float32 `xml:"Number"`
+	Web       string  `xml:"Web"`
+	Year      int     `xml:"Year"`
+	Month     int     `xml:"Month"`
+	Day       int     `xml:"Day"`
+	Writer    string  `xml:"Writer"`
+	Genre     string  `xml:"Genre"`
+	Tags      string  `xml:"Tags"`
+	Publisher string  `xml:"Publisher"`
+	Pages     []struct {
+		Bookmark string `xml:"Bookmark,attr"`
+	} `xml:"Pages>Page"`
+}
+
+// merged returns true for the ComicInfo.xml of merged chapters,
+// which have a bookmark at the start of each chapter.
+func (c *comicInfo) merged() bool {
+	for _, page := range c.Pages {
+		if page.Bookmark != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// readComicInfo reads the ComicInfo.xml of the CBZ file, nil if it has none.
+func readComicInfo(path string) (*comicInfo, error) {
+	file, err := afs.Afero.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	reader, err := zip.NewReader(file, stat.Size())
+	if err != nil {
+		// not a valid CBZ, keep it as is
+		return nil, nil
+	}
+
+	entry, err := reader.Open(metadata.FilenameComicInfoXML)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer entry.Close()
+
+	data, err := io.ReadAll(entry)
+	if err != nil {
+		return nil, err
+	}
+	var ci comicInfo
+	if err := xml.Unmarshal(data, &ci); err != nil {
+		return nil, nil
+	}
+	return &ci, nil
+}
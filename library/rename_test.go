@@ -0,0 +1,122 @@
+package library
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/luevano/libmangal/mangadata"
+)
+
+func writeCBZ(t *testing.T, path, comicInfo string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	w := zip.NewWriter(file)
+	if _, err := w.Create("0001.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	if comicInfo != "" {
+		entry, err := w.Create("ComicInfo.xml")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := entry.Write([]byte(comicInfo)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRename(t *testing.T) {
+	root := t.TempDir()
+	oldManga := filepath.Join(root, "berserk")
+	series := `{"metadata":{"name":"Berserk","status":"Continuing","year":1989,"comicid":30002}}`
+	if err := os.MkdirAll(oldManga, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(oldManga, "series.json"), []byte(series), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeCBZ(t, filepath.Join(oldManga, "v1", "ch1.cbz"), `<ComicInfo>
+  <Title>The Black Swordsman</Title>
+  <Series>Berserk</Series>
+  <Number>1</Number>
+  <Writer>Kentaro Miura</Writer>
+</ComicInfo>`)
+	writeCBZ(t, filepath.Join(oldManga, "v1", "unknown.cbz"), "")
+
+	index, err := Scan(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	moves, err := PlanRename(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newManga := filepath.Join(root, "Berserk (1989) [al-30002]")
+	want := map[string]string{
+		filepath.Join(oldManga, "series.json"):       filepath.Join(newManga, "series.json"),
+		filepath.Join(oldManga, "v1", "ch1.cbz"):     filepath.Join(newManga, "Vol. 1.0", "[0001.0] The Black Swordsman.cbz"),
+		filepath.Join(oldManga, "v1", "unknown.cbz"): filepath.Join(newManga, "Vol. 1.0", "unknown.cbz"),
+	}
+	if len(moves) != len(want) {
+		t.Fatalf("got %d moves %+v, want %d", len(moves), moves, len(want))
+	}
+	for _, move := range moves {
+		if move.To != want[move.From] || move.Conflict != "" {
+			t.Errorf("unexpected move %+v, want to %q", move, want[move.From])
+		}
+	}
+
+	if err := Rename(index, moves); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(oldManga); !os.IsNotExist(err) {
+		t.Errorf("old manga directory not removed: %v", err)
+	}
+
+	// renaming again is a no-op
+	index, err = Scan(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	moves, err = PlanRename(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(moves) != 0 {
+		t.Errorf("got moves %+v after renaming, want none", moves)
+	}
+}
+
+func TestParseVolume(t *testing.T) {
+	manga := mangadata.MangaInfo{Title: "Berserk"}
+	tests := []struct {
+		name   string
+		number float32
+		ok     bool
+	}{
+		{"Vol. 2.0", 2, true},
+		{"v3", 3, true},
+		{"2nd Season 4", 0, false},
+		{"Extras", 0, false},
+	}
+	for _, tt := range tests {
+		volume, ok := parseVolume(tt.name, manga)
+		if ok != tt.ok || volume.Number != tt.number {
+			t.Errorf("parseVolume(%q) = %v, %t, want %v, %t", tt.name, volume.Number, ok, tt.number, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package log
+
+import (
+	"strings"
+	"sync"
+)
+
+// aggregateLines is the max number of lines kept in Aggregate.
+const aggregateLines = 1000
+
+// Aggregate is the buildup of the latest logs, as plain text.
+var Aggregate = newRing(aggregateLines)
+
+// ring keeps the last lines written to it.
+type ring struct {
+	mu    sync.Mutex
+	lines []string
+	// next is the index of the oldest line once full.
+	next int
+	// partial is the written line yet to end.
+	partial strings.Builder
+}
+
+func newRing(size int) *ring {
+	return &ring{lines: make([]string, 0, size)}
+}
+
+// Write implements io.Writer.
+func (r *ring) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	text := string(p)
+	for {
+		i := strings.IndexByte(text, '\n')
+		if i < 0 {
+			r.partial.WriteString(text)
+			return len(p), nil
+		}
+		r.partial.WriteString(text[:i+1])
+		r.push(r.partial.String())
+		r.partial.Reset()
+		text = text[i+1:]
+	}
+}
+
+func (r *ring) push(line string) {
+	if len(r.lines) < cap(r.lines) {
+		r.lines = append(r.lines, line)
+		return
+	}
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % len(r.lines)
+}
+
+// String returns the kept lines, oldest first.
+func (r *ring) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var sb strings.Builder
+	for _, line := range r.lines[r.next:] {
+		sb.WriteString(line)
+	}
+	for _, line := range r.lines[:r.next] {
+		sb.WriteString(line)
+	}
+	sb.WriteString(r.partial.String())
+	return sb.String()
+}
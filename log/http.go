@@ -0,0 +1,55 @@
+package log
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Transport wraps next logging the requests of the provider at
+// debug level, along their headers at trace level.
+// A nil next means http.DefaultTransport.
+func Transport(provider string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{provider: provider, next: next}
+}
+
+type transport struct {
+	provider string
+	next     http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if zerolog.GlobalLevel() > zerolog.DebugLevel {
+		return t.next.RoundTrip(req)
+	}
+	trace := zerolog.GlobalLevel() <= zerolog.TraceLevel
+
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+
+	event := L.Debug().
+		Str("provider", t.provider).
+		Str("method", req.Method).
+		Str("url", req.URL.String()).
+		Dur("duration", time.Since(start))
+	if trace {
+		event = event.Any("request_header", req.Header)
+	}
+	if err != nil {
+		event.Err(err).Msg("provider request failed")
+		return nil, err
+	}
+	event = event.
+		Int("status", res.StatusCode).
+		Int64("content_length", res.ContentLength)
+	if trace {
+		event = event.Any("response_header", res.Header)
+	}
+	event.Msg("provider request")
+	return res, nil
+}
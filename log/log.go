@@ -2,33 +2,62 @@ package log
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 	"time"
 
 	"github.com/luevano/mangal/config"
 	"github.com/luevano/mangal/path"
-	"github.com/luevano/mangal/util/afs"
 	"github.com/rs/zerolog"
 	"github.com/samber/lo"
 )
 
-var L = lo.Must(newLogger())
+// L is the structured logger, written as JSON lines to
+// the rotated log files and as plain text to the latest lines of Aggregate.
+var L = newLogger(lo.Must(zerolog.ParseLevel(config.Log.Level.Get())))
 
-func newLogger() (*zerolog.Logger, error) {
-	today := time.Now().Format(time.DateOnly)
+// writer of the current L.
+var writer *rotateWriter
 
-	logPath := filepath.Join(path.LogDir(), fmt.Sprint(today, ".log"))
-
-	file, err := afs.Afero.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, config.Download.ModeFile.Get())
-	if err != nil {
-		return nil, err
+func newLogger(level zerolog.Level) *zerolog.Logger {
+	if writer != nil {
+		writer.Close()
+	}
+	writer = &rotateWriter{
+		dir:     path.LogDir(),
+		maxSize: int64(config.Log.MaxSize.Get()) * 1024 * 1024,
 	}
+	// already validated by the config
+	writer.maxAge, _ = time.ParseDuration(config.Log.MaxAge.Get())
 
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	zerolog.SetGlobalLevel(level)
 
-	logger := zerolog.New(file)
+	aggregate := zerolog.ConsoleWriter{
+		Out:        Aggregate,
+		NoColor:    true,
+		TimeFormat: time.TimeOnly,
+	}
+	logger := zerolog.New(zerolog.MultiLevelWriter(writer, zerolog.SyncWriter(aggregate))).
+		With().
+		Timestamp().
+		Logger()
+	return &logger
+}
+
+// Setup replaces L with the log.* config, the verbose
+// level is debug unless the configured one is lower.
+func Setup(verbose bool) error {
+	level, err := zerolog.ParseLevel(config.Log.Level.Get())
+	if err != nil {
+		return fmt.Errorf("invalid log level: %s", err.Error())
+	}
+	if verbose && level > zerolog.DebugLevel {
+		level = zerolog.DebugLevel
+	}
 
-	return &logger, nil
+	L = newLogger(level)
+	// remove the old logs right away, not only when rotating
+	writer.mu.Lock()
+	writer.cleanup()
+	writer.mu.Unlock()
+	return nil
 }
@@ -0,0 +1,92 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestRotateAndRead(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "2000-01-01.log")
+	if err := os.WriteFile(old, []byte(`{"level":"info","message":"old"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(old, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	defer zerolog.SetGlobalLevel(zerolog.GlobalLevel())
+	zerolog.SetGlobalLevel(zerolog.TraceLevel)
+
+	w := &rotateWriter{dir: dir, maxSize: 100, maxAge: 24 * time.Hour}
+	defer w.Close()
+	logger := zerolog.New(w).With().Timestamp().Logger()
+
+	reader := NewReader(dir)
+	for i := range 5 {
+		logger.Info().Int("i", i).Msg("downloaded chapter")
+	}
+	logger.Debug().Str("provider", "mangadex").Msg("provider request")
+
+	files, err := Files(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Now().Format(time.DateOnly)
+	if len(files) < 2 || filepath.Base(files[0]) != day+".log" || filepath.Base(files[1]) != day+"_001.log" {
+		t.Errorf("expected rotated files of %s without the old one, got %v", day, files)
+	}
+
+	entries, err := reader.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 6 {
+		t.Fatalf("expected 6 entries, got %d", len(entries))
+	}
+	for i, entry := range entries[:5] {
+		if entry.Level != zerolog.InfoLevel || entry.Message != "downloaded chapter" || fmt.Sprint(entry.Fields["i"]) != strconv.Itoa(i) {
+			t.Errorf("unexpected entry %d: %+v", i, entry)
+		}
+	}
+
+	filter := Filter{Level: zerolog.TraceLevel, Provider: "mangadex"}
+	if !filter.Match(entries[5]) || filter.Match(entries[0]) {
+		t.Error("expected only the provider entry to match")
+	}
+	if (Filter{Level: zerolog.InfoLevel}).Match(entries[5]) {
+		t.Error("expected the debug entry to be filtered out")
+	}
+
+	// following reads only return the new entries
+	logger.Warn().Msg("new")
+	entries, err = reader.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Message != "new" || entries[0].Level != zerolog.WarnLevel {
+		t.Errorf("expected only the new entry, got %+v", entries)
+	}
+}
+
+func TestRing(t *testing.T) {
+	r := newRing(3)
+	for i := range 5 {
+		fmt.Fprintf(r, "line %d\n", i)
+	}
+	fmt.Fprint(r, "partial")
+	if got, want := r.String(), "line 2\nline 3\nline 4\npartial"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	fmt.Fprint(r, " end\n")
+	if got, want := r.String(), "line 3\nline 4\npartial end\n"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,152 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/luevano/mangal/util/afs"
+	"github.com/rs/zerolog"
+)
+
+// Entry is a log line read back from the log files.
+type Entry struct {
+	Time     time.Time
+	Level    zerolog.Level
+	Message  string
+	Provider string
+	Error    string
+	// Fields are the rest of the fields.
+	Fields map[string]any
+	// Raw is the JSON line.
+	Raw []byte
+}
+
+// ParseEntry parses a JSON log line.
+func ParseEntry(line []byte) (Entry, error) {
+	var fields map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return Entry{}, err
+	}
+
+	entry := Entry{
+		Level:  zerolog.NoLevel,
+		Fields: fields,
+		Raw:    line,
+	}
+	take := func(key string) string {
+		s, _ := fields[key].(string)
+		delete(fields, key)
+		return s
+	}
+	if level, err := zerolog.ParseLevel(take(zerolog.LevelFieldName)); err == nil {
+		entry.Level = level
+	}
+	entry.Message = take(zerolog.MessageFieldName)
+	entry.Error = take(zerolog.ErrorFieldName)
+	entry.Provider = take("provider")
+	if t, ok := fields[zerolog.TimestampFieldName].(json.Number); ok {
+		if unix, err := t.Int64(); err == nil {
+			entry.Time = time.Unix(unix, 0)
+		}
+		delete(fields, zerolog.TimestampFieldName)
+	}
+	return entry, nil
+}
+
+// Filter of the log entries.
+type Filter struct {
+	// Level is the minimum level, entries without level always match.
+	Level zerolog.Level
+	// Provider ID, empty matches any.
+	Provider string
+}
+
+// Match returns true if the entry passes the filter.
+func (f Filter) Match(entry Entry) bool {
+	if entry.Level != zerolog.NoLevel && entry.Level < f.Level {
+		return false
+	}
+	return f.Provider == "" || entry.Provider == f.Provider
+}
+
+// Reader reads the entries of the log files in a directory, in order.
+// Each Read continues where the previous one stopped, so new
+// entries (and new files) are read as they are written.
+type Reader struct {
+	dir    string
+	file   string
+	offset int64
+}
+
+// NewReader of the log files in the directory.
+func NewReader(dir string) *Reader {
+	return &Reader{dir: dir}
+}
+
+// Read the entries written since the last Read, lines that are not
+// valid JSON (or not yet complete) are skipped.
+func (r *Reader) Read() ([]Entry, error) {
+	files, err := Files(r.dir)
+	if err != nil {
+		return nil, err
+	}
+	start := 0
+	if r.file != "" {
+		// files are sorted chronologically, continue from the last one read
+		start, _ = slices.BinarySearch(files, r.file)
+	}
+
+	var entries []Entry
+	for _, file := range files[start:] {
+		if file != r.file {
+			r.file, r.offset = file, 0
+		}
+		read, err := r.readFile()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, read...)
+	}
+	return entries, nil
+}
+
+func (r *Reader) readFile() ([]Entry, error) {
+	file, err := afs.Afero.Open(r.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(r.offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	// keep the incomplete last line for the next read
+	end := bytes.LastIndexByte(data, '\n') + 1
+	r.offset += int64(end)
+
+	var entries []Entry
+	for _, line := range bytes.Split(data[:end], []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		entry, err := ParseEntry(line)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,148 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/util/afs"
+	"github.com/spf13/afero"
+)
+
+const logExtension = ".log"
+
+// rotateWriter writes to one file per day, a new file of the same day is
+// started when maxSize is reached. The files are named so that sorting them
+// by name is chronological: 2006-01-02.log, 2006-01-02_001.log and so on.
+//
+// Files older than maxAge are removed when rotating.
+type rotateWriter struct {
+	dir     string
+	maxSize int64
+	maxAge  time.Duration
+
+	mu   sync.Mutex
+	file afero.File
+	day  string
+	part int
+	size int64
+}
+
+func (w *rotateWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	day := time.Now().Format(time.DateOnly)
+	switch {
+	case w.file == nil || day != w.day:
+		if err := w.open(day, lastPart(w.dir, day)); err != nil {
+			return 0, err
+		}
+		w.cleanup()
+	case w.size > 0 && w.size+int64(len(p)) > w.maxSize:
+		if err := w.open(day, w.part+1); err != nil {
+			return 0, err
+		}
+		w.cleanup()
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *rotateWriter) open(day string, part int) error {
+	if w.file != nil {
+		w.file.Close()
+		w.file = nil
+	}
+
+	file, err := afs.Afero.OpenFile(filepath.Join(w.dir, logFilename(day, part)), os.O_WRONLY|os.O_CREATE|os.O_APPEND, config.Download.ModeFile.Get())
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	w.file, w.day, w.part, w.size = file, day, part, info.Size()
+	return nil
+}
+
+// cleanup removes the log files older than maxAge, except the current one.
+func (w *rotateWriter) cleanup() {
+	if w.maxAge <= 0 {
+		return
+	}
+	entries, err := afs.Afero.ReadDir(w.dir)
+	if err != nil {
+		return
+	}
+	current := logFilename(w.day, w.part)
+	for _, entry := range entries {
+		if entry.IsDir() ||
+			!strings.HasSuffix(entry.Name(), logExtension) ||
+			entry.Name() == current ||
+			time.Since(entry.ModTime()) < w.maxAge {
+			continue
+		}
+		afs.Afero.Remove(filepath.Join(w.dir, entry.Name()))
+	}
+}
+
+func (w *rotateWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func logFilename(day string, part int) string {
+	if part == 0 {
+		return day + logExtension
+	}
+	return fmt.Sprintf("%s_%03d%s", day, part, logExtension)
+}
+
+// lastPart returns the last part of the day's log files, to keep appending to it.
+func lastPart(dir, day string) int {
+	files, err := Files(dir)
+	if err != nil {
+		return 0
+	}
+	part := 0
+	for _, file := range files {
+		name := filepath.Base(file)
+		var p int
+		if _, err := fmt.Sscanf(name, day+"_%03d"+logExtension, &p); err == nil && p > part {
+			part = p
+		}
+	}
+	return part
+}
+
+// Files returns the log files in the directory, sorted chronologically.
+func Files(dir string) ([]string, error) {
+	entries, err := afs.Afero.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), logExtension) {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	slices.Sort(files)
+	return files, nil
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Transport wraps next observing the latency and the
+// received bytes of the provider requests.
+// A nil next means http.DefaultTransport.
+func Transport(provider string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{provider: provider, next: next}
+}
+
+type transport struct {
+	provider string
+	next     http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(res.StatusCode)
+	}
+	requestDuration.WithLabelValues(t.provider, code).Observe(time.Since(start).Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	res.Body = &countingBody{
+		ReadCloser: res.Body,
+		counter:    bytesTransferred.WithLabelValues(t.provider),
+	}
+	return res, nil
+}
+
+// countingBody counts the bytes read from the body.
+type countingBody struct {
+	io.ReadCloser
+	counter interface{ Add(float64) }
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.counter.Add(float64(n))
+	return n, err
+}
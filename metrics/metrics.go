@@ -0,0 +1,172 @@
+// Package metrics counts what mangal does (searches, downloads, provider
+// requests and cache lookups) and exposes it in the Prometheus format.
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/luevano/libmangal/metadata"
+	"github.com/luevano/mangal/meta"
+	"github.com/luevano/mangal/util/chapter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "mangal"
+
+var (
+	registry = prometheus.NewRegistry()
+	started  = time.Now()
+
+	searches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "searches_total",
+		Help:      "Manga searches by provider.",
+	}, []string{"provider"})
+	chaptersDownloaded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "chapters_downloaded_total",
+		Help:      "Chapters downloaded (new or overwritten) by provider.",
+	}, []string{"provider"})
+	chaptersFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "chapters_failed_total",
+		Help:      "Chapters failed to download by provider.",
+	}, []string{"provider"})
+	bytesTransferred = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "provider_bytes_total",
+		Help:      "Response bytes received from the providers.",
+	}, []string{"provider"})
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "provider_request_duration_seconds",
+		Help:      "Latency of the provider requests, until the response headers.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"provider", "code"})
+	cacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_lookups_total",
+		Help:      "Cache lookups by bucket and result (hit or miss).",
+	}, []string{"bucket", "result"})
+
+	// totals of cacheLookups, for the hit ratio
+	cacheHits, cacheMisses atomic.Uint64
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		searches,
+		chaptersDownloaded,
+		chaptersFailed,
+		bytesTransferred,
+		requestDuration,
+		cacheLookups,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cache_hit_ratio",
+			Help:      "Ratio of the cache lookups that were hits, since start.",
+		}, cacheHitRatio),
+	)
+}
+
+// Search counts a manga search of the provider.
+func Search(provider string) {
+	searches.WithLabelValues(provider).Inc()
+}
+
+// Chapters counts the downloaded and failed chapters, by the
+// provider they were downloaded with. Existing chapters are not counted.
+func Chapters(chapters chapter.Chapters) {
+	for _, ch := range chapters {
+		switch {
+		case ch.Failed():
+			chaptersFailed.WithLabelValues(ch.Source).Inc()
+		case ch.Down != nil &&
+			(ch.Down.ChapterStatus == metadata.DownloadStatusNew ||
+				ch.Down.ChapterStatus == metadata.DownloadStatusOverwritten):
+			chaptersDownloaded.WithLabelValues(ch.Source).Inc()
+		}
+	}
+}
+
+// CacheLookup counts a cache lookup of the bucket.
+func CacheLookup(bucket string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheLookups.WithLabelValues(bucket, result).Inc()
+	if hit {
+		cacheHits.Add(1)
+	} else {
+		cacheMisses.Add(1)
+	}
+}
+
+func cacheHitRatio() float64 {
+	total := cacheHits.Load() + cacheMisses.Load()
+	if total == 0 {
+		return 0
+	}
+	return float64(cacheHits.Load()) / float64(total)
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Health is the response of the health check.
+type Health struct {
+	Status  string `json:"status"`
+	Version string `json:"version"`
+	// Uptime in seconds.
+	Uptime float64 `json:"uptime"`
+}
+
+// HealthHandler serves the health check as JSON.
+func HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Health{
+			Status:  "ok",
+			Version: meta.Version,
+			Uptime:  time.Since(started).Seconds(),
+		})
+	})
+}
+
+// Serve /metrics and /healthz on the address until the context is done.
+func Serve(ctx context.Context, address string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	mux.Handle("/healthz", HealthHandler())
+	server := &http.Server{
+		Addr:    address,
+		Handler: mux,
+	}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// WriteFile writes the metrics to the file in the Prometheus text format,
+// for the node exporter textfile collector.
+func WriteFile(path string) error {
+	return prometheus.WriteToTextfile(path, registry)
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/luevano/libmangal/metadata"
+	"github.com/luevano/mangal/util/chapter"
+)
+
+func TestMetrics(t *testing.T) {
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("0123456789"))
+	}))
+	defer provider.Close()
+
+	client := &http.Client{Transport: Transport("test-provider", nil)}
+	res, err := client.Get(provider.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+
+	Search("test-provider")
+	Chapters(chapter.Chapters{
+		{Source: "test-provider", Down: &metadata.DownloadedChapter{ChapterStatus: metadata.DownloadStatusNew}},
+		{Source: "test-provider", Down: &metadata.DownloadedChapter{ChapterStatus: metadata.DownloadStatusExists}},
+		{Source: "test-provider", Err: errors.New("boom")},
+	})
+	CacheLookup("test-bucket", true)
+	CacheLookup("test-bucket", false)
+	CacheLookup("test-bucket", false)
+	CacheLookup("test-bucket", true)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		`mangal_searches_total{provider="test-provider"} 1`,
+		`mangal_chapters_downloaded_total{provider="test-provider"} 1`,
+		`mangal_chapters_failed_total{provider="test-provider"} 1`,
+		`mangal_provider_bytes_total{provider="test-provider"} 10`,
+		`mangal_provider_request_duration_seconds_count{code="200",provider="test-provider"} 1`,
+		`mangal_cache_lookups_total{bucket="test-bucket",result="hit"} 2`,
+		`mangal_cache_hit_ratio 0.5`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics don't contain %q", want)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"status":"ok"`) {
+		t.Errorf("unexpected health check %d: %s", rec.Code, rec.Body.String())
+	}
+}
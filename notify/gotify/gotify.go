@@ -0,0 +1,80 @@
+// Package gotify sends the notifications to a Gotify server.
+package gotify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/luevano/mangal/log"
+	"github.com/luevano/mangal/notify/internal/request"
+	"github.com/luevano/mangal/notify/message"
+)
+
+// Gotify pushes the messages with an application token.
+type Gotify struct {
+	// URL of the Gotify server.
+	URL    string
+	Token  string
+	Client *http.Client
+
+	filter message.Filter
+}
+
+// New Gotify notifier.
+func New(url, token string, filter message.Filter) *Gotify {
+	return &Gotify{
+		URL:    url,
+		Token:  token,
+		filter: filter,
+	}
+}
+
+func (g *Gotify) Name() string {
+	return "gotify"
+}
+
+func (g *Gotify) Filter() message.Filter {
+	return g.filter
+}
+
+func (g *Gotify) Notify(ctx context.Context, msg message.Message) error {
+	// Gotify priorities go from 0 to 10, 4-7 show a notification
+	// and 8-10 also make a sound
+	priority := 5
+	switch msg.Level {
+	case message.LevelError:
+		priority = 8
+	case message.LevelWarning:
+		priority = 6
+	}
+
+	body, err := json.Marshal(struct {
+		Title    string `json:"title"`
+		Message  string `json:"message"`
+		Priority int    `json:"priority"`
+	}{
+		Title:    msg.Title,
+		Message:  msg.Body,
+		Priority: priority,
+	})
+	if err != nil {
+		return err
+	}
+
+	url := strings.TrimSuffix(g.URL, "/") + "/message"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", g.Token)
+
+	if err := request.Do(g.Client, req); err != nil {
+		return err
+	}
+	log.Log("sent gotify message to %s", g.URL)
+	return nil
+}
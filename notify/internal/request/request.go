@@ -0,0 +1,38 @@
+// Package request sends the HTTP requests of the notify backends.
+package request
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/luevano/mangal/util/httprec"
+)
+
+// DefaultClient is used by the backends without a client.
+var DefaultClient = &http.Client{Timeout: 30 * time.Second}
+
+// Do sends the request and fails on non 2xx responses,
+// including the start of the response body in the error.
+func Do(client *http.Client, req *http.Request) error {
+	if client == nil {
+		client = DefaultClient
+	}
+	// the clients are created before the httprec mode is set
+	recorded := *client
+	recorded.Transport = httprec.Transport(client.Transport)
+	resp, err := recorded.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected response status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	_, err = io.Copy(io.Discard, resp.Body)
+	return err
+}
@@ -0,0 +1,247 @@
+// Package message builds the notification messages sent by the notify backends.
+package message
+
+import (
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/luevano/libmangal/metadata"
+	"github.com/luevano/mangal/util/chapter"
+)
+
+// Level of the message, backends map it to their colors or priorities.
+type Level string
+
+const (
+	LevelSuccess Level = "success"
+	LevelWarning Level = "warning"
+	LevelError   Level = "error"
+)
+
+// Filter of the chapters included in the message.
+type Filter struct {
+	// IncludeExisting includes the chapters that already existed, if false
+	// and all chapters already existed no message is built.
+	IncludeExisting bool
+	// IncludeDirectory includes the unique chapter download directories.
+	IncludeDirectory bool
+}
+
+// Message is a notification, the same for all backends.
+type Message struct {
+	Title string
+	Body  string
+	Level Level
+	// Manga is the title of the manga of the chapters, if any.
+	Manga string
+	// Cover is the URL of the manga cover, if any.
+	Cover string
+	// Chapters are the chapters of the message (already filtered), if any.
+	Chapters chapter.Chapters
+	// All the chapters of the message, before filtering, for the counts.
+	All chapter.Chapters
+	// Lists are the chapter lists rendered with the chapter template.
+	Lists Lists
+	// Err is the error of the message, if any.
+	Err error
+	// Filter the chapters were filtered with.
+	Filter Filter
+}
+
+// Lists of chapters, one chapter per line.
+type Lists struct {
+	Failed     string
+	Succeed    string
+	ToDownload string
+}
+
+// Text is a custom message.
+func Text(title, body string) Message {
+	return Message{
+		Title: title,
+		Body:  body,
+		Level: LevelSuccess,
+	}
+}
+
+// Error is a general error message.
+func Error(err error) Message {
+	return Message{
+		Title: "Error",
+		Body:  "General error found when downloading chapters:\n" + err.Error(),
+		Level: LevelError,
+		Err:   err,
+	}
+}
+
+// Chapters is the message of the downloaded chapters, false
+// if there is nothing to notify about after filtering.
+func Chapters(chapters chapter.Chapters, filter Filter, templates Templates) (Message, bool, error) {
+	toDownload, succeed, failed := chapters.GetEach()
+	existent := succeed.Existent()
+
+	// all downloaded chapters already existed
+	if len(chapters) == 0 || (len(chapters) == len(existent) && !filter.IncludeExisting) {
+		return Message{}, false, nil
+	}
+
+	level := LevelSuccess
+	switch {
+	case len(failed) != 0:
+		level = LevelError
+	case len(toDownload) != 0:
+		level = LevelWarning
+	}
+
+	include := func(chapters chapter.Chapters) chapter.Chapters {
+		var filtered chapter.Chapters
+		for _, ch := range chapters {
+			if filter.IncludeExisting || !isExistent(ch) {
+				filtered = append(filtered, ch)
+			}
+		}
+		return filtered
+	}
+
+	manga := chapters[0].Chapter.Volume().Manga()
+	data := Data{
+		Manga:      manga.Info(),
+		Cover:      manga.Info().Cover,
+		Level:      level,
+		Summary:    Summary(chapters),
+		Chapters:   include(chapters),
+		Succeed:    include(succeed),
+		Failed:     failed,
+		ToDownload: toDownload,
+		Existent:   existent,
+	}
+	if m := manga.Metadata(); metadata.Validate(m) == nil {
+		data.Metadata = m
+		if cover := m.Cover(); cover != "" {
+			data.Cover = cover
+		}
+	}
+
+	var err error
+	if data.FailedList, err = List(data.Failed, false, templates.Chapter); err != nil {
+		return Message{}, false, err
+	}
+	if data.SucceedList, err = List(data.Succeed, filter.IncludeDirectory, templates.Chapter); err != nil {
+		return Message{}, false, err
+	}
+	if data.ToDownloadList, err = List(data.ToDownload, false, templates.Chapter); err != nil {
+		return Message{}, false, err
+	}
+
+	title, err := execute(templates.Title, data)
+	if err != nil {
+		return Message{}, false, err
+	}
+	body, err := execute(templates.Body, data)
+	if err != nil {
+		return Message{}, false, err
+	}
+
+	return Message{
+		Title:    title,
+		Body:     body,
+		Level:    level,
+		Manga:    data.Manga.Title,
+		Cover:    data.Cover,
+		Chapters: data.Chapters,
+		All:      chapters,
+		Lists: Lists{
+			Failed:     data.FailedList,
+			Succeed:    data.SucceedList,
+			ToDownload: data.ToDownloadList,
+		},
+		Filter: filter,
+	}, true, nil
+}
+
+// NewChapters is the message of the chapters newly available on
+// the provider (not downloaded), listed with the chapter template.
+func NewChapters(chapters chapter.Chapters, templates Templates) (Message, error) {
+	manga := chapters[0].Chapter.Volume().Manga()
+	list, err := List(chapters, false, templates.Chapter)
+	if err != nil {
+		return Message{}, err
+	}
+
+	cover := manga.Info().Cover
+	if m := manga.Metadata(); metadata.Validate(m) == nil && m.Cover() != "" {
+		cover = m.Cover()
+	}
+	return Message{
+		Title: "New chapters",
+		Body:  manga.Info().Title + ": " + strconv.Itoa(len(chapters)) + " new\n" + list,
+		Level: LevelSuccess,
+		Manga: manga.Info().Title,
+		Cover: cover,
+	}, nil
+}
+
+// Summary is the count of succeed, failed and to download chapters.
+func Summary(chapters chapter.Chapters) string {
+	toDownload, _, failed := chapters.GetEach()
+	return "succeed: " + SucceedCount(chapters) +
+		", failed: " + strconv.Itoa(len(failed)) +
+		", to download: " + strconv.Itoa(len(toDownload))
+}
+
+// SucceedCount is the count of succeed chapters, along the existing ones if any.
+func SucceedCount(chapters chapter.Chapters) string {
+	succeed := chapters.Succeed()
+	count := strconv.Itoa(len(succeed))
+	if existent := succeed.Existent(); len(existent) != 0 {
+		count += " (" + strconv.Itoa(len(existent)) + " existed)"
+	}
+	return count
+}
+
+func isExistent(ch *chapter.Chapter) bool {
+	return ch.Down != nil && ch.Down.ChapterStatus == metadata.DownloadStatusExists
+}
+
+// List renders the chapters with the chapter template, one per line,
+// preceded by each unique download directory if includeDirs.
+func List(chapters chapter.Chapters, includeDirs bool, tmpl *template.Template) (string, error) {
+	var (
+		lines   []string
+		lastDir string
+	)
+	for _, ch := range chapters {
+		if includeDirs &&
+			ch.Down != nil &&
+			ch.Down.Directory != lastDir {
+			lastDir = ch.Down.Directory
+			lines = append(lines, lastDir+":")
+		}
+		line, err := execute(tmpl, newChapterData(ch))
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func statusTag(status metadata.DownloadStatus) string {
+	switch status {
+	case metadata.DownloadStatusNew:
+		return "[N]"
+	case metadata.DownloadStatusMissingMetadata:
+		return "[M]"
+	case metadata.DownloadStatusOverwritten:
+		return "[O]"
+	case metadata.DownloadStatusSkip:
+		return "[S]"
+	case metadata.DownloadStatusFailed:
+		return "[F]"
+	case metadata.DownloadStatusExists:
+		return "[E]"
+	default:
+		return "[?]"
+	}
+}
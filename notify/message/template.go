@@ -0,0 +1,104 @@
+package message
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/luevano/libmangal/mangadata"
+	"github.com/luevano/libmangal/metadata"
+	"github.com/luevano/mangal/template/funcs"
+	"github.com/luevano/mangal/util/chapter"
+	stringutil "github.com/luevano/mangal/util/string"
+)
+
+// Templates of the downloaded chapters messages.
+type Templates struct {
+	// Title and Body are executed with Data.
+	Title *template.Template
+	Body  *template.Template
+	// Chapter is executed with ChapterData, for each line of the chapter lists.
+	Chapter *template.Template
+}
+
+// ParseTemplates parses the title, body and chapter templates, with the name template funcs.
+func ParseTemplates(title, body, chapter string) (Templates, error) {
+	var (
+		templates Templates
+		err       error
+	)
+	parse := func(name, text string) *template.Template {
+		if err != nil {
+			return nil
+		}
+		var tmpl *template.Template
+		tmpl, err = template.New(name).Funcs(funcs.FuncMap).Parse(text)
+		return tmpl
+	}
+	templates.Title = parse("title", title)
+	templates.Body = parse("body", body)
+	templates.Chapter = parse("chapter", chapter)
+	return templates, err
+}
+
+// Data is the data of the title and body templates.
+type Data struct {
+	Manga mangadata.MangaInfo
+	// Metadata of the manga, nil if it isn't valid.
+	Metadata metadata.Metadata
+	// Cover URL of the metadata, else the provider's.
+	Cover string
+	Level Level
+	// Summary is the count of succeed, failed and to download chapters.
+	Summary string
+
+	// Chapters and Succeed don't include the existing chapters unless filtered so.
+	Chapters   chapter.Chapters
+	Succeed    chapter.Chapters
+	Failed     chapter.Chapters
+	ToDownload chapter.Chapters
+	Existent   chapter.Chapters
+
+	// Lists rendered with the chapter template.
+	SucceedList    string
+	FailedList     string
+	ToDownloadList string
+}
+
+// ChapterData is the data of the chapter template.
+type ChapterData struct {
+	*chapter.Chapter
+	Info mangadata.ChapterInfo
+	// Number without insignificant digits.
+	Number string
+	// Tag is the short download status ([N] for new, [E] for existent, etc.),
+	// empty if not downloaded.
+	Tag string
+	// Status is the download status, "failed" or "to download".
+	Status string
+}
+
+func newChapterData(ch *chapter.Chapter) ChapterData {
+	data := ChapterData{
+		Chapter: ch,
+		Info:    ch.Chapter.Info(),
+		Number:  stringutil.FormatFloa32(ch.Chapter.Info().Number),
+	}
+	switch {
+	case ch.Failed():
+		data.Status = "failed"
+	case ch.Down != nil:
+		data.Tag = statusTag(ch.Down.ChapterStatus)
+		data.Status = string(ch.Down.ChapterStatus)
+	default:
+		data.Status = "to download"
+	}
+	return data
+}
+
+func execute(tmpl *template.Template, data any) (string, error) {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
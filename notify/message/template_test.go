@@ -0,0 +1,91 @@
+package message
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/luevano/libmangal/mangadata"
+	"github.com/luevano/libmangal/metadata"
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/util/chapter"
+)
+
+type testManga struct{ mangadata.Manga }
+
+func (testManga) Info() mangadata.MangaInfo {
+	return mangadata.MangaInfo{Title: "Berserk", Cover: "https://example.com/cover.jpg"}
+}
+func (testManga) Metadata() metadata.Metadata { return nil }
+
+type testVolume struct{ mangadata.Volume }
+
+func (testVolume) Manga() mangadata.Manga { return testManga{} }
+
+type testChapter struct {
+	mangadata.Chapter
+	number float32
+	title  string
+}
+
+func (c testChapter) Info() mangadata.ChapterInfo {
+	return mangadata.ChapterInfo{Number: c.number, Title: c.title}
+}
+func (testChapter) Volume() mangadata.Volume { return testVolume{} }
+
+func TestChapters(t *testing.T) {
+	chapters := chapter.Chapters{
+		{
+			Chapter: testChapter{number: 1, title: "The Black Swordsman"},
+			Down:    &metadata.DownloadedChapter{ChapterStatus: metadata.DownloadStatusNew},
+		},
+		{
+			Chapter:  testChapter{number: 2.5, title: "The Brand"},
+			Down:     &metadata.DownloadedChapter{ChapterStatus: metadata.DownloadStatusNew},
+			Source:   "mangadex",
+			Fallback: true,
+		},
+		{
+			Chapter: testChapter{number: 3, title: "The Guardians of Desire"},
+			Err:     errors.New("boom"),
+		},
+	}
+
+	templates, err := ParseTemplates(config.NotificationTemplates(config.Notification.Templates))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, ok, err := Chapters(chapters, Filter{}, templates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected a message")
+	}
+
+	if msg.Level != LevelError || msg.Manga != "Berserk" || msg.Cover != "https://example.com/cover.jpg" {
+		t.Errorf("unexpected message %+v", msg)
+	}
+	for _, want := range []string{
+		"succeed: 2, failed: 1, to download: 0",
+		"> [N] 1 - The Black Swordsman",
+		"> [N] 2.5 - The Brand (via mangadex)",
+		"> 3 - The Guardians of Desire",
+	} {
+		if !strings.Contains(msg.Body, want) {
+			t.Errorf("body %q doesn't contain %q", msg.Body, want)
+		}
+	}
+
+	custom, err := ParseTemplates("{{ len .Succeed }} new for {{ .Manga.Title }}", "{{ .SucceedList }}", "{{ .Number }}|{{ .Status }}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, _, err = Chapters(chapters, Filter{}, custom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Title != "2 new for Berserk" || msg.Body != "1|new\n2.5|new" {
+		t.Errorf("unexpected custom message %q: %q", msg.Title, msg.Body)
+	}
+}
@@ -1,24 +1,226 @@
+// Package notify sends the notifications to the configured backends.
 package notify
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/luevano/mangal/config"
 	"github.com/luevano/mangal/notify/discord"
+	"github.com/luevano/mangal/notify/gotify"
+	"github.com/luevano/mangal/notify/message"
+	"github.com/luevano/mangal/notify/ntfy"
+	"github.com/luevano/mangal/notify/smtp"
+	"github.com/luevano/mangal/notify/webhook"
 	"github.com/luevano/mangal/util/chapter"
 )
 
+// Notifier is a notification backend.
+type Notifier interface {
+	// Name of the backend.
+	Name() string
+	// Filter of the chapters notified by the backend.
+	Filter() message.Filter
+	// Notify sends the message.
+	Notify(ctx context.Context, msg message.Message) error
+}
+
+// ErrorNotifyError is the error found when notifying an error.
+type ErrorNotifyError struct {
+	toSend error
+	found  error
+}
+
+func (e ErrorNotifyError) Error() string {
+	return "error notifying error (" + e.toSend.Error() + "): " + e.found.Error()
+}
+
+func (e ErrorNotifyError) Unwrap() []error {
+	return []error{e.toSend, e.found}
+}
+
+func filter(includeExisting, includeDirectory bool) message.Filter {
+	return message.Filter{
+		IncludeExisting:  includeExisting,
+		IncludeDirectory: includeDirectory,
+	}
+}
+
+// backend is a configured notifier along its message templates.
+type backend struct {
+	Notifier
+	templates message.Templates
+}
+
+// Notifiers returns the configured backends.
+func Notifiers() ([]Notifier, error) {
+	backends, err := backends()
+	if err != nil {
+		return nil, err
+	}
+	notifiers := make([]Notifier, len(backends))
+	for i, b := range backends {
+		notifiers[i] = b.Notifier
+	}
+	return notifiers, nil
+}
+
+func backends() ([]backend, error) {
+	var backends []backend
+	n := config.Notification
+
+	add := func(notifier Notifier, templates message.Templates, err error) error {
+		if err != nil {
+			return fmt.Errorf("%s: %s", notifier.Name(), err.Error())
+		}
+		backends = append(backends, backend{notifier, templates})
+		return nil
+	}
+
+	if url := n.Discord.WebhookURL.Get(); url != "" {
+		d := discord.New(
+			n.Discord.Username.Get(),
+			url,
+			filter(config.NotificationFilter(n.Discord.Filter)),
+		)
+		templates, err := message.ParseTemplates(config.NotificationTemplates(n.Discord.Templates))
+		if err := add(d, templates, err); err != nil {
+			return nil, err
+		}
+	}
+	if url := n.Webhook.URL.Get(); url != "" {
+		w, err := webhook.New(
+			url,
+			n.Webhook.Method.Get(),
+			config.NotificationWebhookHeaders(),
+			n.Webhook.PayloadTemplate.Get(),
+			filter(config.NotificationFilter(n.Webhook.Filter)),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("webhook: %s", err.Error())
+		}
+		templates, err := message.ParseTemplates(config.NotificationTemplates(n.Webhook.Templates))
+		if err := add(w, templates, err); err != nil {
+			return nil, err
+		}
+	}
+	if url := n.Ntfy.URL.Get(); url != "" {
+		nt := ntfy.New(
+			url,
+			n.Ntfy.Token.Get(),
+			filter(config.NotificationFilter(n.Ntfy.Filter)),
+		)
+		templates, err := message.ParseTemplates(config.NotificationTemplates(n.Ntfy.Templates))
+		if err := add(nt, templates, err); err != nil {
+			return nil, err
+		}
+	}
+	if url, token := n.Gotify.URL.Get(), n.Gotify.Token.Get(); url != "" && token != "" {
+		g := gotify.New(
+			url,
+			token,
+			filter(config.NotificationFilter(n.Gotify.Filter)),
+		)
+		templates, err := message.ParseTemplates(config.NotificationTemplates(n.Gotify.Templates))
+		if err := add(g, templates, err); err != nil {
+			return nil, err
+		}
+	}
+	if host, to := n.SMTP.Host.Get(), config.NotificationSMTPTo(); host != "" && len(to) != 0 {
+		s := smtp.New(
+			host,
+			n.SMTP.Port.Get(),
+			n.SMTP.TLS.Get(),
+			n.SMTP.Username.Get(),
+			n.SMTP.Password.Get(),
+			n.SMTP.From.Get(),
+			to,
+			filter(config.NotificationFilter(n.SMTP.Filter)),
+		)
+		templates, err := message.ParseTemplates(config.NotificationTemplates(n.SMTP.Templates))
+		if err := add(s, templates, err); err != nil {
+			return nil, err
+		}
+	}
+	for _, raw := range config.NotificationURLs() {
+		notifier, templates, err := fromURL(raw)
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, backend{notifier, templates})
+	}
+	return backends, nil
+}
+
+// timeout of each backend notification.
+const timeout = time.Minute
+
+// notify sends the message built for each backend, false skips the backend.
+func notify(build func(b backend) (message.Message, bool, error)) error {
+	backends, err := backends()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, b := range backends {
+		msg, ok, err := build(b)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", b.Name(), err))
+			continue
+		}
+		if !ok {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err = b.Notify(ctx, msg)
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", b.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // Send will send a notification to the configured services.
 func Send(chapters chapter.Chapters) error {
-	if config.Notification.Discord.WebhookURL.Get() != "" {
-		return discord.Send(chapters)
+	if len(chapters) == 0 {
+		return SendError(errors.New("No downloaded chapters to be notified of."))
 	}
-	return nil
+	return notify(func(b backend) (message.Message, bool, error) {
+		return message.Chapters(chapters, b.Filter(), b.templates)
+	})
+}
+
+// SendNew will send a notification of the chapters
+// newly available on the provider, if any.
+func SendNew(chapters chapter.Chapters) error {
+	if len(chapters) == 0 {
+		return nil
+	}
+	return notify(func(b backend) (message.Message, bool, error) {
+		msg, err := message.NewChapters(chapters, b.templates)
+		return msg, err == nil, err
+	})
+}
+
+// SendMessage will send a custom message to the configured services.
+func SendMessage(title, body string) error {
+	return notify(func(backend) (message.Message, bool, error) {
+		return message.Text(title, body), true, nil
+	})
 }
 
 // SendError is a wrapper error handling that will send a notification
 // to configured services and return the same error back.
-func SendError(err error) error {
-	if config.Notification.Discord.WebhookURL.Get() != "" {
-		return discord.SendError(err)
+func SendError(toSend error) error {
+	err := notify(func(backend) (message.Message, bool, error) {
+		return message.Error(toSend), true, nil
+	})
+	if err != nil {
+		return ErrorNotifyError{toSend, err}
 	}
-	return err
+	return toSend
 }
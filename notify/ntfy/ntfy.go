@@ -0,0 +1,66 @@
+// Package ntfy sends the notifications to a ntfy topic.
+package ntfy
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/luevano/mangal/log"
+	"github.com/luevano/mangal/notify/internal/request"
+	"github.com/luevano/mangal/notify/message"
+)
+
+// Ntfy publishes the messages to the topic URL.
+type Ntfy struct {
+	// URL of the topic, https://ntfy.sh/<topic> for example.
+	URL    string
+	Token  string
+	Client *http.Client
+
+	filter message.Filter
+}
+
+// New Ntfy notifier, the token is optional.
+func New(url, token string, filter message.Filter) *Ntfy {
+	return &Ntfy{
+		URL:    url,
+		Token:  token,
+		filter: filter,
+	}
+}
+
+func (n *Ntfy) Name() string {
+	return "ntfy"
+}
+
+func (n *Ntfy) Filter() message.Filter {
+	return n.filter
+}
+
+func (n *Ntfy) Notify(ctx context.Context, msg message.Message) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, strings.NewReader(msg.Body))
+	if err != nil {
+		return err
+	}
+
+	priority, tag := "default", "white_check_mark"
+	switch msg.Level {
+	case message.LevelError:
+		priority, tag = "high", "x"
+	case message.LevelWarning:
+		tag = "warning"
+	}
+	req.Header.Set("Title", msg.Title)
+	req.Header.Set("Priority", priority)
+	req.Header.Set("Tags", tag)
+	if n.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.Token)
+	}
+
+	if err := request.Do(n.Client, req); err != nil {
+		return err
+	}
+	log.Log("sent ntfy message to %s", n.URL)
+	return nil
+}
@@ -0,0 +1,149 @@
+// Package smtp sends the notifications by email.
+package smtp
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luevano/mangal/log"
+	"github.com/luevano/mangal/meta"
+	"github.com/luevano/mangal/notify/message"
+)
+
+// SMTP sends the messages as plain text emails.
+type SMTP struct {
+	Host string
+	Port int
+	// TLS connects with implicit TLS, else STARTTLS is used if supported.
+	TLS      bool
+	Username string
+	Password string
+	From     string
+	To       []string
+
+	filter message.Filter
+}
+
+// New SMTP notifier, from defaults to the username.
+func New(host string, port int, useTLS bool, username, password, from string, to []string, filter message.Filter) *SMTP {
+	if from == "" {
+		from = username
+	}
+	return &SMTP{
+		Host:     host,
+		Port:     port,
+		TLS:      useTLS,
+		Username: username,
+		Password: password,
+		From:     from,
+		To:       to,
+		filter:   filter,
+	}
+}
+
+func (s *SMTP) Name() string {
+	return "smtp"
+}
+
+func (s *SMTP) Filter() message.Filter {
+	return s.filter
+}
+
+func (s *SMTP) Notify(ctx context.Context, msg message.Message) error {
+	if len(s.To) == 0 {
+		return fmt.Errorf("no recipients")
+	}
+
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	var (
+		conn net.Conn
+		err  error
+	)
+	if s.TLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: s.Host}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if !s.TLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: s.Host}); err != nil {
+				return err
+			}
+		}
+	}
+	if s.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(s.From); err != nil {
+		return err
+	}
+	for _, to := range s.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(s.email(msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if err := client.Quit(); err != nil {
+		return err
+	}
+	log.Log("sent email message to %s", strings.Join(s.To, ", "))
+	return nil
+}
+
+// email builds the plain text email of the message.
+func (s *SMTP) email(msg message.Message) []byte {
+	subject := "[" + meta.AppName + "] " + msg.Title
+	if msg.Manga != "" {
+		subject += ": " + msg.Manga
+	}
+
+	var sb strings.Builder
+	header := func(name, value string) {
+		sb.WriteString(name + ": " + value + "\r\n")
+	}
+	header("From", s.From)
+	header("To", strings.Join(s.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", `text/plain; charset="utf-8"`)
+	header("Content-Transfer-Encoding", "8bit")
+	sb.WriteString("\r\n")
+	sb.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	sb.WriteString("\r\n")
+	return []byte(sb.String())
+}
@@ -0,0 +1,70 @@
+// Package webhook sends the notifications to a generic webhook with a templated payload.
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"text/template"
+
+	"github.com/luevano/mangal/log"
+	"github.com/luevano/mangal/notify/internal/request"
+	"github.com/luevano/mangal/notify/message"
+	"github.com/luevano/mangal/template/funcs"
+)
+
+// Webhook sends the messages as the rendered payload template.
+type Webhook struct {
+	URL     string
+	Method  string
+	Headers map[string]string
+	Client  *http.Client
+
+	payload *template.Template
+	filter  message.Filter
+}
+
+// New Webhook notifier, the payload template is executed with the message.
+func New(url, method string, headers map[string]string, payload string, filter message.Filter) (*Webhook, error) {
+	tmpl, err := template.New("payload").Funcs(funcs.FuncMap).Parse(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Webhook{
+		URL:     url,
+		Method:  method,
+		Headers: headers,
+		payload: tmpl,
+		filter:  filter,
+	}, nil
+}
+
+func (w *Webhook) Name() string {
+	return "webhook"
+}
+
+func (w *Webhook) Filter() message.Filter {
+	return w.filter
+}
+
+func (w *Webhook) Notify(ctx context.Context, msg message.Message) error {
+	var body strings.Builder
+	if err := w.payload.Execute(&body, msg); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, w.Method, w.URL, strings.NewReader(body.String()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range w.Headers {
+		req.Header.Set(name, value)
+	}
+
+	if err := request.Do(w.Client, req); err != nil {
+		return err
+	}
+	log.Log("sent webhook message to %s", w.URL)
+	return nil
+}
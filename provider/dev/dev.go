@@ -0,0 +1,183 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/luevano/libmangal"
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/provider/loader"
+	"github.com/luevano/mangal/theme/style"
+	"github.com/luevano/mangal/util/afs"
+)
+
+// debounce is the time to wait for more file changes before reloading,
+// editors usually write files in multiple steps.
+const debounce = 200 * time.Millisecond
+
+type Options struct {
+	// Dir is the Lua provider directory (containing mangal.toml and main.lua).
+	Dir string
+
+	// Query to search on each reload.
+	Query string
+
+	// Out is where the results and errors are written to.
+	Out io.Writer
+}
+
+// Run watches the provider directory and on each change rebuilds the
+// loader and runs the query from search through pages, until ctx is done.
+func Run(ctx context.Context, options Options) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := watchDirs(watcher, options.Dir); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(options.Out, style.Normal.Secondary.Render("Watching "+options.Dir))
+	reload(ctx, options)
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) &&
+				!event.Has(fsnotify.Remove) && !event.Has(fsnotify.Rename) {
+				continue
+			}
+			// watch the directories created after starting
+			if event.Has(fsnotify.Create) {
+				if info, err := afs.Afero.Stat(event.Name); err == nil && info.IsDir() && !hidden(info.Name()) {
+					if err := watchDirs(watcher, event.Name); err != nil {
+						printErr(options.Out, "watcher", err)
+					}
+				}
+			}
+			timer.Reset(debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			printErr(options.Out, "watcher", err)
+		case <-timer.C:
+			reload(ctx, options)
+		}
+	}
+}
+
+// watchDirs watches the directory and all of its non hidden sub directories
+// (lua modules), as fsnotify is not recursive.
+func watchDirs(watcher *fsnotify.Watcher, dir string) error {
+	return afs.Afero.Walk(dir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if hidden(info.Name()) && path != dir {
+				return filepath.SkipDir
+			}
+			return watcher.Add(path)
+		}
+		return nil
+	})
+}
+
+func hidden(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
+
+// reload rebuilds the loader and runs the query, printing the results.
+func reload(ctx context.Context, options Options) {
+	fmt.Fprintf(options.Out, "\n%s %s\n", style.Bold.Accent.Render("Reloading"), time.Now().Format(time.TimeOnly))
+
+	l, err := loader.DevLoader(options.Dir)
+	if err != nil {
+		printErr(options.Out, "loader", err)
+		return
+	}
+
+	clientOptions := libmangal.DefaultClientOptions()
+	clientOptions.FS = afs.Afero
+	clientOptions.UserAgent = config.Download.UserAgent.Get()
+	client, err := libmangal.NewClient(ctx, l, clientOptions)
+	if err != nil {
+		printErr(options.Out, "load", err)
+		return
+	}
+	defer client.Close()
+
+	start := time.Now()
+	mangas, err := client.SearchMangas(ctx, options.Query)
+	if err != nil {
+		printErr(options.Out, "search", err)
+		return
+	}
+	printStep(options.Out, "search", len(mangas), start)
+	if len(mangas) == 0 {
+		return
+	}
+	fmt.Fprintf(options.Out, "  first: %s (%s)\n", mangas[0].Info().Title, mangas[0].Info().URL)
+
+	start = time.Now()
+	volumes, err := client.MangaVolumes(ctx, mangas[0])
+	if err != nil {
+		printErr(options.Out, "volumes", err)
+		return
+	}
+	printStep(options.Out, "volumes", len(volumes), start)
+	if len(volumes) == 0 {
+		return
+	}
+
+	start = time.Now()
+	chapters, err := client.VolumeChapters(ctx, volumes[0])
+	if err != nil {
+		printErr(options.Out, "chapters", err)
+		return
+	}
+	printStep(options.Out, "chapters", len(chapters), start)
+	if len(chapters) == 0 {
+		return
+	}
+	fmt.Fprintf(options.Out, "  first: %s (%s)\n", chapters[0], chapters[0].Info().URL)
+
+	start = time.Now()
+	pages, err := client.ChapterPages(ctx, chapters[0])
+	if err != nil {
+		printErr(options.Out, "pages", err)
+		return
+	}
+	printStep(options.Out, "pages", len(pages), start)
+	if len(pages) != 0 {
+		fmt.Fprintf(options.Out, "  first: %s\n", pages[0])
+	}
+}
+
+func printStep(out io.Writer, step string, n int, start time.Time) {
+	fmt.Fprintf(out, "%s %d results %s\n",
+		style.Bold.Success.Render(step+":"),
+		n,
+		style.Normal.Secondary.Render("("+time.Since(start).Round(time.Millisecond).String()+")"),
+	)
+}
+
+func printErr(out io.Writer, step string, err error) {
+	fmt.Fprintf(out, "%s %s\n", style.Bold.Error.Render(step+":"), err.Error())
+}
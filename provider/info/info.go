@@ -24,7 +24,8 @@ const Filename = "mangal.toml"
 // Info contains libmangal info about provider with mangal specific type field
 type Info struct {
 	libmangal.ProviderInfo
-	Type Type `json:"type"`
+	Type        Type         `json:"type"`
+	Permissions *Permissions `json:"permissions,omitempty"`
 }
 
 // New parses info from reader
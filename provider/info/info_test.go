@@ -35,6 +35,30 @@ id = "some-id"
 			},
 			wantErr: false,
 		},
+		{
+			name: "permissions",
+			args: args{
+				r: strings.NewReader(`
+type = "lua"
+id = "some-id"
+
+[permissions]
+hosts = ["example.com", "*.cdn.example.com"]
+headless = true
+`),
+			},
+			wantInfo: Info{
+				ProviderInfo: libmangal.ProviderInfo{
+					ID: "some-id",
+				},
+				Type: TypeLua,
+				Permissions: &Permissions{
+					Hosts:    []string{"example.com", "*.cdn.example.com"},
+					Headless: true,
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -49,3 +73,41 @@ id = "some-id"
 		})
 	}
 }
+
+func TestPermissionsAllowsHost(t *testing.T) {
+	permissions := &Permissions{
+		Hosts: []string{"example.com", "*.cdn.example.org"},
+	}
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"example.com", true},
+		{"EXAMPLE.com", true},
+		{"www.example.com", false},
+		{"cdn.example.org", true},
+		{"img.cdn.example.org", true},
+		{"evilcdn.example.org", false},
+		{"example.org", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := permissions.AllowsHost(tt.host); got != tt.want {
+				t.Errorf("AllowsHost(%q) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermissionsDiff(t *testing.T) {
+	old := &Permissions{Hosts: []string{"a.com", "b.com"}}
+	new := &Permissions{Hosts: []string{"b.com", "c.com"}, Headless: true}
+
+	want := "+ host c.com\n- host a.com\n+ headless"
+	if got := new.Diff(old); got != want {
+		t.Errorf("Diff() = %q, want %q", got, want)
+	}
+	if got := new.Diff(new); got != "" {
+		t.Errorf("Diff() with itself = %q, want empty", got)
+	}
+}
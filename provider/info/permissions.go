@@ -0,0 +1,95 @@
+package info
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Permissions declares what a Lua provider is allowed to do,
+// enforced by the loader.
+type Permissions struct {
+	// Hosts the provider is allowed to make HTTP requests to.
+	// A "*." prefix matches any subdomain, "*" matches any host.
+	//
+	// Only the sdk http requests are guarded, not the headless browser.
+	Hosts []string `json:"hosts"`
+
+	// Headless browser access (sdk.headless). The browser does its own
+	// requests, which are not restricted to Hosts.
+	Headless bool `json:"headless"`
+
+	// Filesystem access (io and package libraries, require of
+	// Lua files, dofile, loadfile and load).
+	Filesystem bool `json:"filesystem"`
+}
+
+// AllowsHost returns true if the host matches any of the allowed hosts.
+func (p *Permissions) AllowsHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, h := range p.Hosts {
+		h = strings.ToLower(h)
+		switch {
+		case h == "*":
+			return true
+		case strings.HasPrefix(h, "*."):
+			if host == h[2:] || strings.HasSuffix(host, h[1:]) {
+				return true
+			}
+		case h == host:
+			return true
+		}
+	}
+	return false
+}
+
+// String is the human readable list of permissions.
+func (p *Permissions) String() string {
+	if p == nil {
+		return "unrestricted (no permissions declared)"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "hosts: %s\n", strings.Join(p.Hosts, ", "))
+	fmt.Fprintf(&sb, "headless: %t\n", p.Headless)
+	fmt.Fprintf(&sb, "filesystem: %t", p.Filesystem)
+	return sb.String()
+}
+
+// Diff returns the changes from old to p, one per line prefixed
+// by "+" (granted) or "-" (revoked). Empty if there are no changes.
+//
+// A nil old is treated as no permissions granted (fresh install).
+func (p *Permissions) Diff(old *Permissions) string {
+	if old == nil && p == nil {
+		return ""
+	}
+	if p == nil {
+		return "+ unrestricted (no permissions declared)"
+	}
+	if old == nil {
+		old = &Permissions{}
+	}
+
+	var lines []string
+	for _, h := range p.Hosts {
+		if !slices.Contains(old.Hosts, h) {
+			lines = append(lines, "+ host "+h)
+		}
+	}
+	for _, h := range old.Hosts {
+		if !slices.Contains(p.Hosts, h) {
+			lines = append(lines, "- host "+h)
+		}
+	}
+	diffBool := func(name string, old, new bool) {
+		switch {
+		case new && !old:
+			lines = append(lines, "+ "+name)
+		case !new && old:
+			lines = append(lines, "- "+name)
+		}
+	}
+	diffBool("headless", old.Headless, p.Headless)
+	diffBool("filesystem", old.Filesystem, p.Filesystem)
+	return strings.Join(lines, "\n")
+}
@@ -14,6 +14,10 @@ import (
 	"github.com/luevano/mangal/provider/info"
 	"github.com/luevano/mangal/util/afs"
 	"github.com/luevano/mangal/util/cache"
+	"github.com/luevano/mangal/util/cache/bbolt"
+	"github.com/luevano/mangal/util/httprec"
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
 )
 
 const mainLua = "main.lua"
@@ -85,7 +89,7 @@ func getLoaders(bundleID, dir string) ([]libmangal.ProviderLoader, error) {
 
 	switch providerInfo.Type {
 	case info.TypeLua:
-		loader, err := newLoader(providerInfo.ProviderInfo, dir)
+		loader, err := newLoader(providerInfo, dir, cache.CacheStore)
 		if err != nil {
 			return nil, err
 		}
@@ -98,7 +102,48 @@ func getLoaders(bundleID, dir string) ([]libmangal.ProviderLoader, error) {
 	}
 }
 
-func newLoader(info libmangal.ProviderInfo, dir string) (libmangal.ProviderLoader, error) {
+// devCacheDir is the cache sub directory of the providers in development.
+const devCacheDir = "dev"
+
+// DevLoader creates the loader for the Lua provider in dir, used while developing it.
+//
+// It uses its own cache store (in the "dev" cache sub directory) that is kept
+// across reloads and never expires, so the stale results are served instead
+// of repeating the requests; the actual cache is untouched.
+func DevLoader(dir string) (libmangal.ProviderLoader, error) {
+	infoFile, err := afs.Afero.OpenFile(
+		filepath.Join(dir, info.Filename),
+		os.O_RDONLY,
+		config.Download.ModeFile.Get(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer infoFile.Close()
+
+	providerInfo, err := info.New(infoFile)
+	if err != nil {
+		return nil, err
+	}
+	if providerInfo.Type != info.TypeLua {
+		return nil, fmt.Errorf("provider type %s not supported for development, only %s", providerInfo.Type, info.TypeLua)
+	}
+
+	return newLoader(providerInfo, dir, func(dbName, bucketName string) (gokv.Store, error) {
+		cacheDir := filepath.Join(path.CacheDir(), devCacheDir)
+		if err := afs.Afero.MkdirAll(cacheDir, config.Download.ModeDir.Get()); err != nil {
+			return nil, err
+		}
+		return bbolt.NewStore(bbolt.Options{
+			TTL:        0, // no expiry
+			BucketName: bucketName,
+			Path:       filepath.Join(cacheDir, dbName+".db"),
+			Codec:      encoding.Gob,
+		})
+	})
+}
+
+func newLoader(providerInfo info.Info, dir string, cacheStore func(dbName, bucketName string) (gokv.Store, error)) (libmangal.ProviderLoader, error) {
 	providerMainFilePath := filepath.Join(dir, mainLua)
 	exists, err := afs.Afero.Exists(providerMainFilePath)
 	if err != nil {
@@ -114,14 +159,30 @@ func newLoader(info libmangal.ProviderInfo, dir string) (libmangal.ProviderLoade
 		return nil, err
 	}
 
+	transport := httprec.Transport(nil)
+	permissions := providerInfo.Permissions
+	switch {
+	case permissions != nil:
+		transport = hostGuard{
+			id:          providerInfo.ID,
+			permissions: permissions,
+			next:        transport,
+		}
+		providerMainFileContents = append([]byte(sandboxPrelude(permissions)), providerMainFileContents...)
+	case config.Providers.RequirePermissions.Get():
+		return nil, fmt.Errorf("provider %q doesn't declare permissions in its %s", providerInfo.ID, info.Filename)
+	}
+	transport = providerTransport(providerInfo.ID, transport)
+
 	options := luaprovider.Options{
 		HTTPClient: &http.Client{
-			Timeout: time.Minute,
+			Timeout:   time.Minute,
+			Transport: transport,
 		},
 		UserAgent:    config.Download.UserAgent.Get(),
-		CacheStore:   cache.CacheStore,
+		CacheStore:   cacheStore,
 		PackagePaths: []string{dir},
 	}
 
-	return luaprovider.NewLoader(providerMainFileContents, info, options)
+	return luaprovider.NewLoader(providerMainFileContents, providerInfo.ProviderInfo, options)
 }
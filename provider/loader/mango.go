@@ -2,11 +2,13 @@ package loader
 
 import (
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/luevano/libmangal"
 	"github.com/luevano/mangal/config"
 	"github.com/luevano/mangal/util/cache"
+	"github.com/luevano/mangal/util/httprec"
 	mango "github.com/luevano/mangoprovider"
 	"github.com/luevano/mangoprovider/apis"
 	"github.com/luevano/mangoprovider/scrapers"
@@ -17,6 +19,7 @@ func MangoLoaders() ([]libmangal.ProviderLoader, error) {
 	o := mango.DefaultOptions()
 
 	o.HTTPClient.Timeout = time.Minute
+	o.HTTPClient.Transport = httprec.Transport(o.HTTPClient.Transport)
 	o.UserAgent = config.Download.UserAgent.Get()
 	o.CacheStore = cache.CacheStore
 	o.Parallelism = config.Providers.Parallelism.Get()
@@ -45,6 +48,13 @@ func MangoLoaders() ([]libmangal.ProviderLoader, error) {
 			// TODO: need to provide more info
 			return nil, fmt.Errorf("failed while loading providers")
 		}
+		// each provider gets its own client, to tell their requests apart
+		if l, ok := loader.(*mango.Loader); ok {
+			l.Options.HTTPClient = &http.Client{
+				Timeout:   o.HTTPClient.Timeout,
+				Transport: providerTransport(l.Info().ID, o.HTTPClient.Transport),
+			}
+		}
 	}
 
 	return loaders, nil
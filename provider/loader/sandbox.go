@@ -0,0 +1,70 @@
+package loader
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/luevano/mangal/provider/info"
+)
+
+var _ http.RoundTripper = (*hostGuard)(nil)
+
+// hostGuard only lets through requests to the hosts allowed by the permissions.
+type hostGuard struct {
+	id          string
+	permissions *info.Permissions
+	next        http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (g hostGuard) RoundTrip(req *http.Request) (*http.Response, error) {
+	if host := req.URL.Hostname(); !g.permissions.AllowsHost(host) {
+		return nil, fmt.Errorf("provider %q is not allowed to access host %q, not declared in its permissions", g.id, host)
+	}
+	return g.next.RoundTrip(req)
+}
+
+// sandboxPrelude returns Lua code to run before the provider script that
+// removes the capabilities not granted by the permissions.
+//
+// The os library is always reduced to time, clock and date, also when
+// required, and debug is removed. Without the filesystem permission, io,
+// package and the load* functions are removed (io also from the loaded
+// modules) and require only resolves the preloaded modules (sdk and the
+// standard libraries), not Lua files.
+//
+// It's a single line without a trailing newline, so that
+// line numbers reported in errors stay the same.
+func sandboxPrelude(permissions *info.Permissions) string {
+	statements := []string{
+		`if os then os = { time = os.time, clock = os.clock, date = os.date } end`,
+		`debug = nil`,
+		`if package and package.loaded then package.loaded.os = os; package.loaded.debug = nil end`,
+	}
+	if !permissions.Filesystem {
+		statements = append(statements,
+			`package.loaded.io = nil`,
+			`local __preload, __loaded = package.preload, package.loaded`,
+			`require = function(name) `+
+				`if __loaded[name] ~= nil then return __loaded[name] end; `+
+				`local loader = __preload[name]; `+
+				`if loader == nil then error("module '" .. tostring(name) .. "' not available, the provider has no filesystem permission", 2) end; `+
+				`local m = loader(name); if m == nil then m = true end; __loaded[name] = m; return m `+
+				`end`,
+			"io = nil",
+			"package = nil",
+			"dofile = nil",
+			"loadfile = nil",
+			"load = nil",
+			"loadstring = nil",
+		)
+	}
+	if !permissions.Headless {
+		statements = append(statements,
+			`local __require = require`,
+			`require = function(name) local m = __require(name); if name == "sdk" and type(m) == "table" then m.headless = nil end; return m end`,
+		)
+	}
+	return "do " + strings.Join(statements, "; ") + " end; "
+}
@@ -0,0 +1,70 @@
+package loader
+
+import (
+	"testing"
+
+	"github.com/luevano/luaprovider/lib"
+	"github.com/luevano/mangal/provider/info"
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestSandboxPrelude(t *testing.T) {
+	run := func(t *testing.T, permissions *info.Permissions, script string) error {
+		t.Helper()
+		// the same libraries as the luaprovider state
+		L := lua.NewState(lua.Options{SkipOpenLibs: true})
+		defer L.Close()
+		for _, open := range []lua.LGFunction{
+			lua.OpenBase,
+			lua.OpenTable,
+			lua.OpenString,
+			lua.OpenMath,
+			lua.OpenPackage,
+			lua.OpenIo,
+			lua.OpenCoroutine,
+			lua.OpenChannel,
+		} {
+			open(L)
+		}
+		lib.Preload(L, lib.DefaultOptions())
+		return L.DoString(sandboxPrelude(permissions) + script)
+	}
+
+	none := &info.Permissions{}
+	for _, script := range []string{
+		`os.execute("true")`,
+		`os.remove("x")`,
+		`io.open("x")`,
+		`dofile("x")`,
+		`load("return 1")`,
+		`package.loadlib("x", "y")`,
+		`require("some.module")`,
+		`require("io").open("x")`,
+		`require("os").execute("true")`,
+		`require("debug").getinfo(1)`,
+		`require("sdk").headless.browser()`,
+	} {
+		if err := run(t, none, script); err == nil {
+			t.Errorf("expected %q to fail without permissions", script)
+		}
+	}
+
+	for _, script := range []string{
+		`assert(require("sdk").strings ~= nil)`,
+		`assert(require("coroutine") == coroutine)`,
+	} {
+		if err := run(t, none, script); err != nil {
+			t.Errorf("expected %q to run without permissions: %s", script, err)
+		}
+	}
+
+	all := &info.Permissions{Filesystem: true, Headless: true}
+	if err := run(t, all, `assert(io.open ~= nil and require("sdk").headless ~= nil)`); err != nil {
+		t.Errorf("expected the granted capabilities to be available: %s", err)
+	}
+	for _, script := range []string{`os.execute("true")`, `require("os").execute("true")`} {
+		if err := run(t, all, script); err == nil {
+			t.Errorf("expected %q to fail even with all permissions", script)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package loader
+
+import (
+	"net/http"
+
+	"github.com/luevano/mangal/log"
+	"github.com/luevano/mangal/metrics"
+)
+
+// providerTransport wraps next with the request logging and metrics of the provider.
+func providerTransport(id string, next http.RoundTripper) http.RoundTripper {
+	return log.Transport(id, metrics.Transport(id, next))
+}
@@ -2,6 +2,7 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -10,6 +11,7 @@ import (
 	"path/filepath"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/luevano/libmangal"
 	"github.com/luevano/luaprovider"
 	"github.com/luevano/mangal/config"
@@ -17,26 +19,38 @@ import (
 	"github.com/luevano/mangal/provider/info"
 	"github.com/luevano/mangal/util/afs"
 	"github.com/pelletier/go-toml"
-	"github.com/pkg/errors"
 	"github.com/samber/lo"
 	"github.com/spf13/afero"
 )
 
+// ConfirmPermissions is called with the provider ID and the diff of the
+// requested permissions (see info.Permissions.Diff), returns true to proceed.
+type ConfirmPermissions func(ID, diff string) bool
+
 // TODO: add actual options and pass them
 type AddOptions struct {
 	URL *url.URL
+	// Commit hash to pin the provider to, pinned providers are not updated.
+	Commit string
+	// Confirm the requested permissions, if nil they're accepted.
+	Confirm ConfirmPermissions
 }
 
-type UpdateOptions struct{}
+type UpdateOptions struct {
+	// Confirm changes in the requested permissions, if nil they're accepted.
+	Confirm ConfirmPermissions
+}
 
 func Add(ctx context.Context, options AddOptions) error {
 	tempDir, err := afs.Afero.TempDir(path.TempDir(), "")
 	if err != nil {
 		return err
 	}
+	// no-op once moved to the providers dir
+	defer afs.Afero.RemoveAll(tempDir)
 
 	fmt.Println(tempDir)
-	_, err = git.PlainCloneContext(ctx, tempDir, false, &git.CloneOptions{
+	repo, err := git.PlainCloneContext(ctx, tempDir, false, &git.CloneOptions{
 		URL:      options.URL.String(),
 		Progress: os.Stdout, // TODO: change this
 	})
@@ -44,13 +58,17 @@ func Add(ctx context.Context, options AddOptions) error {
 		return err
 	}
 
-	infoFile, err := afs.Afero.OpenFile(filepath.Join(tempDir, info.Filename), os.O_RDONLY, config.Download.ModeFile.Get())
-	if err != nil {
-		return err
+	if options.Commit != "" {
+		if err := pin(repo, options.Commit); err != nil {
+			return err
+		}
 	}
-	defer infoFile.Close()
 
-	providerInfo, err := info.New(infoFile)
+	if err := verify(tempDir, repo); err != nil {
+		return fmt.Errorf("couldn't verify provider from %s: %w", options.URL, err)
+	}
+
+	providerInfo, err := readInfo(tempDir)
 	if err != nil {
 		return err
 	}
@@ -73,11 +91,17 @@ func Add(ctx context.Context, options AddOptions) error {
 		return fmt.Errorf("provider with ID %q already exists", ID)
 	}
 
+	if options.Confirm != nil && !options.Confirm(ID, providerInfo.Permissions.Diff(nil)) {
+		return fmt.Errorf("permissions for provider %q not accepted", ID)
+	}
+
 	target := filepath.Join(path.ProvidersDir(), ID)
 	fmt.Println(target)
 	return afs.Afero.Rename(tempDir, target)
 }
 
+// Update pulls the providers that are git repositories, the ones that
+// fail verification are reset and the rest are still updated.
 func Update(ctx context.Context, options UpdateOptions) error {
 	providersDir := path.ProvidersDir()
 	dirEntries, err := afs.Afero.ReadDir(providersDir)
@@ -85,8 +109,10 @@ func Update(ctx context.Context, options UpdateOptions) error {
 		return err
 	}
 
+	var errs []error
 	for _, dirEntry := range dirEntries {
-		repo, err := git.PlainOpen(filepath.Join(providersDir, dirEntry.Name()))
+		dir := filepath.Join(providersDir, dirEntry.Name())
+		repo, err := git.PlainOpen(dir)
 
 		if errors.Is(err, git.ErrRepositoryNotExists) {
 			continue
@@ -101,17 +127,90 @@ func Update(ctx context.Context, options UpdateOptions) error {
 			return err
 		}
 
+		head, err := repo.Head()
+		if err != nil {
+			return err
+		}
+		// pinned to a commit (detached HEAD)
+		if !head.Name().IsBranch() {
+			fmt.Printf("skipping %s, pinned to commit %s\n", dirEntry.Name(), head.Hash())
+			continue
+		}
+		oldInfo, err := readInfo(dir)
+		if err != nil {
+			return err
+		}
+
 		err = worktree.PullContext(ctx, &git.PullOptions{
 			Progress: os.Stdout,
 			Force:    true,
 		})
 
-		if !(errors.Is(err, git.NoErrAlreadyUpToDate) || errors.Is(err, git.ErrRemoteNotFound)) {
+		if errors.Is(err, git.NoErrAlreadyUpToDate) || errors.Is(err, git.ErrRemoteNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		// go back to the previous version
+		reset := func() error {
+			return worktree.Reset(&git.ResetOptions{
+				Commit: head.Hash(),
+				Mode:   git.HardReset,
+			})
+		}
+
+		if err := verify(dir, repo); err != nil {
+			if resetErr := reset(); resetErr != nil {
+				return resetErr
+			}
+			errs = append(errs, fmt.Errorf("refusing update of %s: %w", dirEntry.Name(), err))
+			continue
+		}
+
+		newInfo, err := readInfo(dir)
+		if err != nil {
+			return err
+		}
+		diff := newInfo.Permissions.Diff(oldInfo.Permissions)
+		if diff == "" || options.Confirm == nil || options.Confirm(newInfo.ID, diff) {
+			continue
+		}
+
+		// permissions not accepted
+		if err := reset(); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+// pin checks out the commit hash (detached HEAD).
+func pin(repo *git.Repository, commit string) error {
+	hash, err := repo.ResolveRevision(plumbing.Revision(commit))
+	if err != nil {
+		return fmt.Errorf("couldn't resolve commit %q: %w", commit, err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	return worktree.Checkout(&git.CheckoutOptions{
+		Hash: *hash,
+	})
+}
+
+// readInfo reads the provider info file from the directory.
+func readInfo(dir string) (info.Info, error) {
+	infoFile, err := afs.Afero.OpenFile(filepath.Join(dir, info.Filename), os.O_RDONLY, config.Download.ModeFile.Get())
+	if err != nil {
+		return info.Info{}, err
+	}
+	defer infoFile.Close()
+
+	return info.New(infoFile)
 }
 
 func Remove(tag string) error {
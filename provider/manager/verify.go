@@ -0,0 +1,202 @@
+package manager
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/util/afs"
+)
+
+// SignatureFilename is the file containing the base64 ed25519
+// signature over the provider files digest (see Digest).
+const SignatureFilename = "mangal.sig"
+
+// ErrUnsigned is returned when the provider couldn't be verified
+// by any method and a signature is required.
+var ErrUnsigned = errors.New("provider is not signed by any trusted key")
+
+// Digest computes the digest of all the provider files in dir,
+// excluding the .git directory and the signature file.
+//
+// Each file contributes its slash separated relative path and
+// the sha256 of its contents, in lexical order of the paths.
+func Digest(dir string) ([]byte, error) {
+	var paths []string
+	err := afs.Afero.Walk(dir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == SignatureFilename {
+			return nil
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	digest := sha256.New()
+	for _, path := range paths {
+		file, err := afs.Afero.Open(filepath.Join(dir, filepath.FromSlash(path)))
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, file)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(digest, "%s\x00%x\n", path, h.Sum(nil))
+	}
+	return digest.Sum(nil), nil
+}
+
+// Sign the provider files in dir with the private key,
+// writes the signature file into dir.
+func Sign(dir string, key ed25519.PrivateKey) error {
+	digest, err := Digest(dir)
+	if err != nil {
+		return err
+	}
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(key, digest))
+	return afs.Afero.WriteFile(filepath.Join(dir, SignatureFilename), []byte(signature+"\n"), config.Download.ModeFile.Get())
+}
+
+// trustedKeys parses the configured trusted ed25519 public keys.
+func trustedKeys() ([]ed25519.PublicKey, error) {
+	var keys []ed25519.PublicKey
+	for _, encoded := range config.TrustedKeys() {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted key %q: %s", encoded, err.Error())
+		}
+		if len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid trusted key %q: wrong size %d", encoded, len(key))
+		}
+		keys = append(keys, ed25519.PublicKey(key))
+	}
+	return keys, nil
+}
+
+// verify checks the provider in dir either by its signature file against the
+// trusted keys or by a signed tag pointing at HEAD against the trusted PGP keyring.
+//
+// A signature that doesn't match any trusted key (or with no trusted keys set)
+// counts as no signature at all, which is only an error (ErrUnsigned)
+// if signatures are required.
+func verify(dir string, repo *git.Repository) error {
+	fileErr := verifySignatureFile(dir)
+	if fileErr == nil {
+		return nil
+	}
+	if !errors.Is(fileErr, ErrUnsigned) {
+		return fileErr
+	}
+
+	err := verifySignedTag(repo)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, ErrUnsigned) {
+		return err
+	}
+	if config.Providers.RequireSignature.Get() {
+		// the signature file error is more descriptive when there is one
+		return fileErr
+	}
+	return nil
+}
+
+func verifySignatureFile(dir string) error {
+	data, err := afs.Afero.ReadFile(filepath.Join(dir, SignatureFilename))
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrUnsigned
+	}
+	if err != nil {
+		return err
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return fmt.Errorf("%w: invalid %s: %s", ErrUnsigned, SignatureFilename, err.Error())
+	}
+	keys, err := trustedKeys()
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("%w: %s found but no trusted keys are set", ErrUnsigned, SignatureFilename)
+	}
+	digest, err := Digest(dir)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if ed25519.Verify(key, digest, signature) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s doesn't match the provider files or any trusted key", ErrUnsigned, SignatureFilename)
+}
+
+func verifySignedTag(repo *git.Repository) error {
+	keyringPath := config.Providers.TrustedKeyring.Get()
+	if keyringPath == "" {
+		return ErrUnsigned
+	}
+	keyring, err := afs.Afero.ReadFile(keyringPath)
+	if err != nil {
+		return err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return err
+	}
+	tags, err := repo.TagObjects()
+	if err != nil {
+		return err
+	}
+
+	verified := false
+	err = tags.ForEach(func(tag *object.Tag) error {
+		if tag.Target != head.Hash() || tag.PGPSignature == "" {
+			return nil
+		}
+		if _, err := tag.Verify(string(keyring)); err == nil {
+			verified = true
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return ErrUnsigned
+	}
+	return nil
+}
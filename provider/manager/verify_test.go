@@ -0,0 +1,99 @@
+package manager
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/luevano/mangal/config"
+)
+
+func TestSignatureFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("main.lua", "print('hi')")
+	write("lib/util.lua", "return {}")
+	write(".git/HEAD", "ignored")
+
+	if err := verifySignatureFile(dir); !errors.Is(err, ErrUnsigned) {
+		t.Fatalf("unsigned provider error = %v, want %v", err, ErrUnsigned)
+	}
+
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Sign(dir, private); err != nil {
+		t.Fatal(err)
+	}
+
+	// without trusted keys the signature can't be checked
+	if err := verifySignatureFile(dir); !errors.Is(err, ErrUnsigned) {
+		t.Fatalf("signed provider without trusted keys error = %v, want %v", err, ErrUnsigned)
+	}
+
+	original := config.Providers.TrustedKeys.Get()
+	defer config.Providers.TrustedKeys.Set(original)
+	if err := config.Providers.TrustedKeys.Set(base64.StdEncoding.EncodeToString(public)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := verifySignatureFile(dir); err != nil {
+		t.Errorf("signed provider error = %v, want nil", err)
+	}
+
+	// git metadata is not part of the digest
+	write(".git/HEAD", "changed")
+	if err := verifySignatureFile(dir); err != nil {
+		t.Errorf("signed provider with changed .git error = %v, want nil", err)
+	}
+
+	write("lib/util.lua", "return { evil = true }")
+	if err := verifySignatureFile(dir); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("tampered provider error = %v, want %v", err, ErrUnsigned)
+	}
+}
+
+func TestVerifyUnmatchedSignature(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.lua"), []byte("print('hi')"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Sign(dir, private); err != nil {
+		t.Fatal(err)
+	}
+
+	original := config.Providers.RequireSignature.Get()
+	defer config.Providers.RequireSignature.Set(original)
+
+	// no trusted keys nor keyring, so the tag check is never reached
+	if err := config.Providers.RequireSignature.Set(false); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(dir, nil); err != nil {
+		t.Errorf("unmatched signature not required error = %v, want nil", err)
+	}
+
+	if err := config.Providers.RequireSignature.Set(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(dir, nil); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("unmatched signature required error = %v, want %v", err, ErrUnsigned)
+	}
+}
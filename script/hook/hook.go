@@ -0,0 +1,308 @@
+// Package hook runs the user Lua hooks on the download lifecycle events.
+//
+// Each *.lua file in the hooks path can define any of the global functions
+// named after the events, receiving a single table argument:
+//
+//	function before_download(event) return true end -- false skips the chapter
+//	function after_chapter_downloaded(event) return event.path end -- a new path moves the file
+//	function after_download_batch(event) end
+//	function on_error(event) end
+//
+// When chapters are merged into a single file, before_download runs for each
+// chapter before merging and after_chapter_downloaded for each chapter of the
+// merged file (all sharing its path).
+//
+// The hooks have the mangal library available, same as the scripts.
+package hook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sync"
+
+	"github.com/luevano/libmangal"
+	"github.com/luevano/libmangal/mangadata"
+	"github.com/luevano/libmangal/metadata"
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/log"
+	"github.com/luevano/mangal/meta"
+	"github.com/luevano/mangal/script/lib"
+	"github.com/luevano/mangal/script/lib/client"
+	"github.com/luevano/mangal/util/afs"
+	"github.com/luevano/mangal/util/chapter"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Event is a download lifecycle event, also the name of the hook function.
+type Event string
+
+const (
+	BeforeDownload         Event = "before_download"
+	AfterChapterDownloaded Event = "after_chapter_downloaded"
+	AfterDownloadBatch     Event = "after_download_batch"
+	OnError                Event = "on_error"
+)
+
+// Hooks are the loaded hook files, a nil *Hooks is valid and runs nothing.
+type Hooks struct {
+	mu     sync.Mutex
+	client *libmangal.Client
+	hooks  []*hook
+}
+
+type hook struct {
+	name  string
+	state *lua.LState
+}
+
+// Load the hooks from the configured hooks path, if enabled.
+func Load(client *libmangal.Client) (*Hooks, error) {
+	if !config.Script.Hooks.Enabled.Get() {
+		return nil, nil
+	}
+
+	path := config.Script.Hooks.Path.Get()
+	entries, err := afs.Afero.ReadDir(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	h := &Hooks{client: client}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".lua" {
+			continue
+		}
+		hook, err := loadHook(client, filepath.Join(path, entry.Name()))
+		if err != nil {
+			h.Close()
+			return nil, err
+		}
+		h.hooks = append(h.hooks, hook)
+	}
+	if len(h.hooks) == 0 {
+		return nil, nil
+	}
+	return h, nil
+}
+
+func loadHook(client *libmangal.Client, path string) (*hook, error) {
+	state := lib.NewState()
+	lib.Preload(state, client)
+
+	// load the library so that the userdata types are registered
+	err := state.CallByParam(lua.P{
+		Fn:      state.GetGlobal("require"),
+		Protect: true,
+	}, lua.LString(meta.AppName))
+	if err == nil {
+		err = state.DoFile(path)
+	}
+	if err != nil {
+		state.Close()
+		return nil, fmt.Errorf("error loading hook %q: %s", path, err.Error())
+	}
+	return &hook{
+		name:  filepath.Base(path),
+		state: state,
+	}, nil
+}
+
+// Close the hooks Lua states.
+func (h *Hooks) Close() {
+	if h == nil {
+		return
+	}
+	for _, hook := range h.hooks {
+		hook.state.Close()
+	}
+}
+
+// call the event function of each hook that defines it,
+// args builds the event table for each hook state.
+func (h *Hooks) call(
+	ctx context.Context,
+	event Event,
+	args func(state *lua.LState) *lua.LTable,
+	result func(ret lua.LValue) error,
+) error {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, hook := range h.hooks {
+		fn, ok := hook.state.GetGlobal(string(event)).(*lua.LFunction)
+		if !ok {
+			continue
+		}
+
+		hook.state.SetContext(ctx)
+		err := hook.state.CallByParam(lua.P{
+			Fn:      fn,
+			NRet:    1,
+			Protect: true,
+		}, args(hook.state))
+		hook.state.RemoveContext()
+		if err != nil {
+			return fmt.Errorf("hook %s (%s): %s", event, hook.name, err.Error())
+		}
+
+		ret := hook.state.Get(-1)
+		hook.state.Pop(1)
+		if err := result(ret); err != nil {
+			return fmt.Errorf("hook %s (%s): %s", event, hook.name, err.Error())
+		}
+	}
+	return nil
+}
+
+func noResult(lua.LValue) error {
+	return nil
+}
+
+// chapterTable is the common event information of the chapter.
+func (h *Hooks) chapterTable(state *lua.LState, ch *chapter.Chapter) *lua.LTable {
+	table := state.NewTable()
+	table.RawSetString("chapter", client.NewChapter(state, ch.Chapter))
+	table.RawSetString("provider", lua.LString(ch.Source))
+	table.RawSetString("metadata", metadataTable(state, ch.Chapter))
+	if ch.Down != nil {
+		table.RawSetString("downloaded", client.NewDownloadedChapter(state, ch.Down))
+		table.RawSetString("path", lua.LString(ch.Down.Path()))
+	}
+	if ch.Err != nil {
+		table.RawSetString("error", lua.LString(ch.Err.Error()))
+	}
+	return table
+}
+
+func metadataTable(state *lua.LState, ch mangadata.Chapter) *lua.LTable {
+	info := ch.Info()
+	volume := ch.Volume()
+	manga := volume.Manga()
+
+	table := state.NewTable()
+	table.RawSetString("manga", lua.LString(manga.Info().Title))
+	table.RawSetString("volume", lua.LNumber(volume.Info().Number))
+	table.RawSetString("number", lua.LNumber(info.Number))
+	table.RawSetString("title", lua.LString(info.Title))
+	table.RawSetString("url", lua.LString(info.URL))
+	if m := manga.Metadata(); m != nil && metadata.Validate(m) == nil {
+		table.RawSetString("status", lua.LString(m.Status()))
+		table.RawSetString("year", lua.LNumber(m.StartDate().Year))
+		table.RawSetString("genres", stringsTable(state, m.Genres()))
+		table.RawSetString("tags", stringsTable(state, m.Tags()))
+		table.RawSetString("authors", stringsTable(state, m.Authors()))
+	}
+	return table
+}
+
+func stringsTable(state *lua.LState, s []string) *lua.LTable {
+	table := state.NewTable()
+	for _, e := range s {
+		table.Append(lua.LString(e))
+	}
+	return table
+}
+
+// BeforeDownload runs the before_download hooks, the chapter
+// should be skipped if false is returned by any of them.
+func (h *Hooks) BeforeDownload(ctx context.Context, ch *chapter.Chapter) (bool, error) {
+	download := true
+	err := h.call(ctx, BeforeDownload, func(state *lua.LState) *lua.LTable {
+		return h.chapterTable(state, ch)
+	}, func(ret lua.LValue) error {
+		if ret == lua.LFalse {
+			download = false
+		}
+		return nil
+	})
+	return download, err
+}
+
+// Skip marks the chapter as skipped, for chapters vetoed by BeforeDownload.
+func Skip(ch *chapter.Chapter) {
+	info := ch.Chapter.Info()
+	ch.Down = &metadata.DownloadedChapter{
+		Number:             info.Number,
+		Title:              info.Title,
+		ChapterStatus:      metadata.DownloadStatusSkip,
+		SeriesJSONStatus:   metadata.DownloadStatusSkip,
+		ComicInfoXMLStatus: metadata.DownloadStatusSkip,
+		CoverStatus:        metadata.DownloadStatusSkip,
+		BannerStatus:       metadata.DownloadStatusSkip,
+	}
+}
+
+// AfterChapterDownloaded runs the after_chapter_downloaded hooks,
+// if a hook returns a different path the chapter file is moved there
+// (relative paths are relative to the chapter directory).
+func (h *Hooks) AfterChapterDownloaded(ctx context.Context, ch *chapter.Chapter) error {
+	if ch.Down == nil {
+		return nil
+	}
+	return h.call(ctx, AfterChapterDownloaded, func(state *lua.LState) *lua.LTable {
+		return h.chapterTable(state, ch)
+	}, func(ret lua.LValue) error {
+		path, ok := ret.(lua.LString)
+		if !ok || path == "" {
+			return nil
+		}
+		return move(ch.Down, string(path))
+	})
+}
+
+// move the downloaded chapter file (or directory) and update its location.
+func move(down *metadata.DownloadedChapter, path string) error {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(down.Directory, path)
+	}
+	path = filepath.Clean(path)
+	if path == down.Path() {
+		return nil
+	}
+
+	if err := afs.Afero.MkdirAll(filepath.Dir(path), config.Download.ModeDir.Get()); err != nil {
+		return err
+	}
+	if err := afs.Afero.Rename(down.Path(), path); err != nil {
+		return err
+	}
+	log.Log("hook moved chapter %q to %q", down.Path(), path)
+	down.Directory, down.Filename = filepath.Dir(path), filepath.Base(path)
+	return nil
+}
+
+// AfterDownloadBatch runs the after_download_batch hooks with all the chapters,
+// errors are only logged.
+func (h *Hooks) AfterDownloadBatch(ctx context.Context, chapters chapter.Chapters) {
+	logError(h.call(ctx, AfterDownloadBatch, func(state *lua.LState) *lua.LTable {
+		list := state.NewTable()
+		for _, ch := range chapters {
+			list.Append(h.chapterTable(state, ch))
+		}
+		table := state.NewTable()
+		table.RawSetString("chapters", list)
+		return table
+	}, noResult))
+}
+
+// OnError runs the on_error hooks for the failed chapter, errors are only logged.
+func (h *Hooks) OnError(ctx context.Context, ch *chapter.Chapter) {
+	logError(h.call(ctx, OnError, func(state *lua.LState) *lua.LTable {
+		return h.chapterTable(state, ch)
+	}, noResult))
+}
+
+func logError(err error) {
+	if err != nil {
+		log.Log("%s", err.Error())
+	}
+}
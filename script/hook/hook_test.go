@@ -0,0 +1,109 @@
+package hook
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/luevano/libmangal/mangadata"
+	"github.com/luevano/libmangal/metadata"
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/util/chapter"
+)
+
+type testManga struct{ mangadata.Manga }
+
+func (testManga) Info() mangadata.MangaInfo   { return mangadata.MangaInfo{Title: "Berserk"} }
+func (testManga) Metadata() metadata.Metadata { return nil }
+
+type testVolume struct{ mangadata.Volume }
+
+func (testVolume) Info() mangadata.VolumeInfo { return mangadata.VolumeInfo{Number: 1} }
+func (testVolume) Manga() mangadata.Manga     { return testManga{} }
+
+type testChapter struct {
+	mangadata.Chapter
+	number float32
+}
+
+func (c testChapter) Info() mangadata.ChapterInfo { return mangadata.ChapterInfo{Number: c.number} }
+func (testChapter) Volume() mangadata.Volume      { return testVolume{} }
+
+func loadTestHooks(t *testing.T, script string) *Hooks {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "test.lua"), []byte(script), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Script.Hooks.Path.Set(dir); err != nil {
+		t.Fatal(err)
+	}
+	hooks, err := Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(hooks.Close)
+	return hooks
+}
+
+func TestBeforeDownload(t *testing.T) {
+	hooks := loadTestHooks(t, `
+function before_download(event)
+	return event.metadata.manga == "Berserk" and event.metadata.number ~= 2
+end
+`)
+
+	for number, want := range map[float32]bool{1: true, 2: false} {
+		download, err := hooks.BeforeDownload(context.Background(), &chapter.Chapter{Chapter: testChapter{number: number}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if download != want {
+			t.Errorf("chapter %v: download = %v, want %v", number, download, want)
+		}
+	}
+}
+
+func TestAfterChapterDownloaded(t *testing.T) {
+	hooks := loadTestHooks(t, `
+function after_chapter_downloaded(event)
+	return "komga/" .. event.metadata.manga .. "/" .. event.metadata.number .. ".cbz"
+end
+`)
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "1.cbz"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ch := &chapter.Chapter{
+		Chapter: testChapter{number: 1},
+		Down:    &metadata.DownloadedChapter{Filename: "1.cbz", Directory: dir},
+	}
+	if err := hooks.AfterChapterDownloaded(context.Background(), ch); err != nil {
+		t.Fatal(err)
+	}
+
+	want := filepath.Join(dir, "komga", "Berserk", "1.cbz")
+	if ch.Down.Path() != want {
+		t.Errorf("path = %q, want %q", ch.Down.Path(), want)
+	}
+	if _, err := os.Stat(want); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLoadMissingPath(t *testing.T) {
+	if err := config.Script.Hooks.Path.Set(filepath.Join(t.TempDir(), "missing")); err != nil {
+		t.Fatal(err)
+	}
+	hooks, err := Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a nil *Hooks runs nothing
+	download, err := hooks.BeforeDownload(context.Background(), &chapter.Chapter{Chapter: testChapter{number: 1}})
+	if err != nil || !download {
+		t.Errorf("BeforeDownload = %v, %v, want true, nil", download, err)
+	}
+}
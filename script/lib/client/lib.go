@@ -4,7 +4,7 @@ import (
 	luadoc "github.com/luevano/gopher-luadoc"
 	"github.com/luevano/libmangal"
 	"github.com/luevano/libmangal/mangadata"
-	"github.com/luevano/mangal/config"
+	"github.com/luevano/libmangal/metadata"
 	"github.com/luevano/mangal/script/lib/util"
 	lua "github.com/yuin/gopher-lua"
 )
@@ -93,6 +93,27 @@ func Lib(client *libmangal.Client) *luadoc.Lib {
 	classDownloadedChapter := &luadoc.Class{
 		Name:        downloadedChapterTypeName,
 		Description: "Downloaded chapter data",
+		Methods: []*luadoc.Method{
+			{
+				Name:        "info",
+				Description: "",
+				Value:       downloadedChapterInfo,
+				Returns: []*luadoc.Param{
+					{
+						Name:        "info",
+						Description: "Status is one of 'new', 'exists', 'overwritten', 'failed', 'skip' or 'missing-metadata'",
+						Type: luadoc.TableLiteral(
+							"number", luadoc.Number,
+							"title", luadoc.String,
+							"filename", luadoc.String,
+							"directory", luadoc.String,
+							"path", luadoc.String,
+							"status", luadoc.String,
+						),
+					},
+				},
+			},
+		},
 	}
 
 	classPage := &luadoc.Class{
@@ -138,6 +159,7 @@ func Lib(client *libmangal.Client) *luadoc.Lib {
 			classManga,
 			classVolume,
 			classChapter,
+			classDownloadedChapter,
 			classPageWithImage,
 		},
 		Funcs: []*luadoc.Func{
@@ -235,6 +257,12 @@ func Lib(client *libmangal.Client) *luadoc.Lib {
 						Name: "chapter",
 						Type: classChapter.Name,
 					},
+					{
+						Name:        "options",
+						Description: "Download options, missing fields are taken from the config",
+						Type:        downloadOptionsType,
+						Optional:    true,
+					},
 				},
 				Returns: []*luadoc.Param{
 					{
@@ -290,7 +318,7 @@ func chapterInfo(state *lua.LState) int {
 }
 
 func missingClientError(state *lua.LState) int {
-	state.RaiseError("missing provider client")
+	state.RaiseError("no provider set, use providers.load")
 	return 0
 }
 
@@ -390,6 +418,22 @@ func newDownloadPage(client *libmangal.Client) lua.LGFunction {
 	}
 }
 
+func downloadedChapterInfo(state *lua.LState) int {
+	downChap := util.Check[*metadata.DownloadedChapter](state, 1)
+
+	table := state.NewTable()
+
+	table.RawSetString("number", lua.LNumber(downChap.Number))
+	table.RawSetString("title", lua.LString(downChap.Title))
+	table.RawSetString("filename", lua.LString(downChap.Filename))
+	table.RawSetString("directory", lua.LString(downChap.Directory))
+	table.RawSetString("path", lua.LString(downChap.Path()))
+	table.RawSetString("status", lua.LString(downChap.ChapterStatus))
+
+	state.Push(table)
+	return 1
+}
+
 func pageImage(state *lua.LState) int {
 	page := util.Check[mangadata.PageWithImage](state, 1)
 	image := page.Image()
@@ -414,10 +458,22 @@ func newDownloadChapter(client *libmangal.Client) lua.LGFunction {
 	return func(state *lua.LState) int {
 		chapter := util.Check[mangadata.Chapter](state, 1)
 
-		downChap, err := client.DownloadChapter(state.Context(), chapter, config.DownloadOptions())
+		options := checkDownloadOptions(state, 2)
+
+		downChap, err := client.DownloadChapter(state.Context(), chapter, options)
 		util.Must(state, err)
 
 		util.Push(state, downChap, downloadedChapterTypeName)
 		return 1
 	}
 }
+
+// NewChapter wraps the chapter for the scripts, the library needs to be loaded first.
+func NewChapter(state *lua.LState, chapter mangadata.Chapter) *lua.LUserData {
+	return util.NewUserData(state, chapter, chapterTypeName)
+}
+
+// NewDownloadedChapter wraps the downloaded chapter for the scripts, the library needs to be loaded first.
+func NewDownloadedChapter(state *lua.LState, downChap *metadata.DownloadedChapter) *lua.LUserData {
+	return util.NewUserData(state, downChap, downloadedChapterTypeName)
+}
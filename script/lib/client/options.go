@@ -0,0 +1,83 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	luadoc "github.com/luevano/gopher-luadoc"
+	"github.com/luevano/libmangal"
+	"github.com/luevano/mangal/config"
+	lua "github.com/yuin/gopher-lua"
+)
+
+var downloadOptionsType = luadoc.TableLiteral(
+	"format", luadoc.Enum(libmangal.FormatStrings()...),
+	"directory", luadoc.String,
+	"create_provider_dir", luadoc.Boolean,
+	"create_manga_dir", luadoc.Boolean,
+	"create_volume_dir", luadoc.Boolean,
+	"skip_if_exists", luadoc.Boolean,
+	"strict", luadoc.Boolean,
+	"search_metadata", luadoc.Boolean,
+	"download_manga_cover", luadoc.Boolean,
+	"download_manga_banner", luadoc.Boolean,
+	"write_series_json", luadoc.Boolean,
+	"skip_series_json_if_ongoing", luadoc.Boolean,
+	"write_comicinfo_xml", luadoc.Boolean,
+)
+
+// checkDownloadOptions builds the download options from the config,
+// overridden by the optional table at n.
+func checkDownloadOptions(state *lua.LState, n int) libmangal.DownloadOptions {
+	options := config.DownloadOptions()
+
+	table := state.OptTable(n, nil)
+	if table == nil {
+		return options
+	}
+
+	bools := map[string]*bool{
+		"create_provider_dir":         &options.CreateProviderDir,
+		"create_manga_dir":            &options.CreateMangaDir,
+		"create_volume_dir":           &options.CreateVolumeDir,
+		"skip_if_exists":              &options.SkipIfExists,
+		"strict":                      &options.Strict,
+		"search_metadata":             &options.SearchMetadata,
+		"download_manga_cover":        &options.DownloadMangaCover,
+		"download_manga_banner":       &options.DownloadMangaBanner,
+		"write_series_json":           &options.WriteSeriesJSON,
+		"skip_series_json_if_ongoing": &options.SkipSeriesJSONIfOngoing,
+		"write_comicinfo_xml":         &options.WriteComicInfoXML,
+	}
+
+	table.ForEach(func(key, value lua.LValue) {
+		name := key.String()
+		if b, ok := bools[name]; ok {
+			v, ok := value.(lua.LBool)
+			if !ok {
+				state.ArgError(n, fmt.Sprintf("%s: boolean expected, got %s", name, value.Type()))
+			}
+			*b = bool(v)
+			return
+		}
+
+		switch name {
+		case "format":
+			format, err := libmangal.FormatString(value.String())
+			if err != nil {
+				state.ArgError(n, fmt.Sprintf("format: one of %s expected, got %q", strings.Join(libmangal.FormatStrings(), ", "), value.String()))
+			}
+			options.Format = format
+		case "directory":
+			v, ok := value.(lua.LString)
+			if !ok {
+				state.ArgError(n, fmt.Sprintf("directory: string expected, got %s", value.Type()))
+			}
+			options.Directory = string(v)
+		default:
+			state.ArgError(n, fmt.Sprintf("unknown download option %q", name))
+		}
+	})
+
+	return options
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	luadoc "github.com/luevano/gopher-luadoc"
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/script/lib/util"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const libName = "config"
+
+func Lib() *luadoc.Lib {
+	return &luadoc.Lib{
+		Name:        libName,
+		Description: "Read and write config values",
+		Funcs: []*luadoc.Func{
+			{
+				Name:        "get",
+				Description: "Get the value of the config key, as stored in the config file",
+				Value:       get,
+				Params: []*luadoc.Param{
+					{
+						Name:        "key",
+						Description: "Config key, for example 'download.path'",
+						Type:        luadoc.String,
+					},
+				},
+				Returns: []*luadoc.Param{
+					{
+						Name: "value",
+						Type: luadoc.Any,
+					},
+				},
+			},
+			{
+				Name:        "set",
+				Description: "Set the value of the config key for the rest of the script, use write to persist it. The script, provider paths and provider trust keys can't be set",
+				Value:       set,
+				Params: []*luadoc.Param{
+					{
+						Name:        "key",
+						Description: "Config key, for example 'download.path'",
+						Type:        luadoc.String,
+					},
+					{
+						Name: "value",
+						Type: luadoc.Any,
+					},
+				},
+			},
+			{
+				Name:        "write",
+				Description: "Write the current config to the config file",
+				Value:       write,
+			},
+		},
+	}
+}
+
+func checkKey(state *lua.LState, n int) string {
+	key := state.CheckString(n)
+	if !config.Exists(key) {
+		state.ArgError(n, fmt.Sprintf("config key %q doesn't exist", key))
+	}
+	return key
+}
+
+// protectedKeys can't be set by scripts, as they'd allow
+// to escape the script sandbox or to load untrusted code.
+var protectedKeys = []string{
+	"providers.path",
+	"providers.require_permissions",
+	"providers.require_signature",
+	"providers.trusted_keys",
+	"providers.trusted_keyring",
+}
+
+func protected(key string) bool {
+	return strings.HasPrefix(key, "script.") || slices.Contains(protectedKeys, key)
+}
+
+func get(state *lua.LState) int {
+	key := checkKey(state, 1)
+
+	value, err := config.GetRaw(key)
+	util.Must(state, err)
+
+	state.Push(util.FromGoValue(state, value))
+	return 1
+}
+
+func set(state *lua.LState) int {
+	key := checkKey(state, 1)
+	if protected(key) {
+		state.ArgError(1, fmt.Sprintf("config key %q can't be set from a script", key))
+	}
+
+	value, err := config.ConvertRaw(key, util.ToGoValue(state.CheckAny(2)))
+	util.Must(state, err)
+	util.Must(state, config.Set(key, value))
+	return 0
+}
+
+func write(state *lua.LState) int {
+	util.Must(state, config.Write())
+	return 0
+}
@@ -0,0 +1,304 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	luadoc "github.com/luevano/gopher-luadoc"
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/script/lib/util"
+	"github.com/luevano/mangal/util/afs"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const libName = "fs"
+
+func Lib() *luadoc.Lib {
+	pathParam := &luadoc.Param{
+		Name:        "path",
+		Description: "Relative paths are relative to the first root",
+		Type:        luadoc.String,
+	}
+	contentParam := &luadoc.Param{
+		Name: "content",
+		Type: luadoc.String,
+	}
+
+	return &luadoc.Lib{
+		Name:        libName,
+		Description: "Filesystem access, restricted to the script.fs.roots directories",
+		Funcs: []*luadoc.Func{
+			{
+				Name:        "roots",
+				Description: "Get the directories the scripts have access to",
+				Value:       roots,
+				Returns: []*luadoc.Param{
+					{
+						Name: "roots",
+						Type: luadoc.List(luadoc.String),
+					},
+				},
+			},
+			{
+				Name:        "exists",
+				Description: "Check if the path exists",
+				Value:       exists,
+				Params:      []*luadoc.Param{pathParam},
+				Returns: []*luadoc.Param{
+					{
+						Name: "exists",
+						Type: luadoc.Boolean,
+					},
+				},
+			},
+			{
+				Name:        "read",
+				Description: "Read the whole file",
+				Value:       read,
+				Params:      []*luadoc.Param{pathParam},
+				Returns:     []*luadoc.Param{contentParam},
+			},
+			{
+				Name:        "lines",
+				Description: "Read the non empty lines of the file, trimmed",
+				Value:       lines,
+				Params:      []*luadoc.Param{pathParam},
+				Returns: []*luadoc.Param{
+					{
+						Name: "lines",
+						Type: luadoc.List(luadoc.String),
+					},
+				},
+			},
+			{
+				Name:        "write",
+				Description: "Write the file, replacing it if it exists. Parent directories are created",
+				Value:       write,
+				Params:      []*luadoc.Param{pathParam, contentParam},
+			},
+			{
+				Name:        "append",
+				Description: "Append to the file, creating it if it doesn't exist",
+				Value:       appendFile,
+				Params:      []*luadoc.Param{pathParam, contentParam},
+			},
+			{
+				Name:        "list",
+				Description: "List the directory entries",
+				Value:       list,
+				Params:      []*luadoc.Param{pathParam},
+				Returns: []*luadoc.Param{
+					{
+						Name: "entries",
+						Type: luadoc.List(luadoc.TableLiteral(
+							"name", luadoc.String,
+							"path", luadoc.String,
+							"dir", luadoc.Boolean,
+							"size", luadoc.Number,
+						)),
+					},
+				},
+			},
+			{
+				Name:        "mkdir",
+				Description: "Create the directory and its parents",
+				Value:       mkdir,
+				Params:      []*luadoc.Param{pathParam},
+			},
+			{
+				Name:        "remove",
+				Description: "Remove the file or empty directory",
+				Value:       remove,
+				Params:      []*luadoc.Param{pathParam},
+			},
+		},
+	}
+}
+
+// absRoots returns the absolute roots with the symlinks resolved.
+func absRoots() ([]string, error) {
+	var abs []string
+	for _, root := range config.ScriptFSRoots() {
+		r, err := resolve(root)
+		if err != nil {
+			return nil, err
+		}
+		abs = append(abs, r)
+	}
+	return abs, nil
+}
+
+// resolve returns the absolute path with the symlinks of
+// its longest existing ancestor resolved.
+func resolve(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	var missing []string
+	current := path
+	for {
+		resolved, err := filepath.EvalSymlinks(current)
+		if err == nil {
+			slices.Reverse(missing)
+			return filepath.Join(append([]string{resolved}, missing...)...), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return path, nil
+		}
+		missing = append(missing, filepath.Base(current))
+		current = parent
+	}
+}
+
+func within(path, root string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// checkPath gets the path argument, returns its absolute path
+// if it is within one of the roots or raises an error otherwise.
+func checkPath(state *lua.LState, n int, write bool) string {
+	path := state.CheckString(n)
+	if write && config.Script.FS.ReadOnly.Get() {
+		state.RaiseError("write access is disabled (script.fs.read_only)")
+	}
+
+	roots, err := absRoots()
+	util.Must(state, err)
+	if len(roots) == 0 {
+		state.RaiseError("no filesystem roots configured (script.fs.roots)")
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(roots[0], path)
+	}
+	resolved, err := resolve(path)
+	util.Must(state, err)
+
+	for _, root := range roots {
+		if within(resolved, root) {
+			return resolved
+		}
+	}
+	state.ArgError(n, fmt.Sprintf("path %q is outside of the allowed roots (script.fs.roots)", path))
+	return ""
+}
+
+func roots(state *lua.LState) int {
+	roots, err := absRoots()
+	util.Must(state, err)
+
+	state.Push(util.SliceToTable(state, roots, func(root string) lua.LValue {
+		return lua.LString(root)
+	}))
+	return 1
+}
+
+func exists(state *lua.LState) int {
+	path := checkPath(state, 1, false)
+
+	exists, err := afs.Afero.Exists(path)
+	util.Must(state, err)
+
+	state.Push(lua.LBool(exists))
+	return 1
+}
+
+func read(state *lua.LState) int {
+	path := checkPath(state, 1, false)
+
+	data, err := afs.Afero.ReadFile(path)
+	util.Must(state, err)
+
+	state.Push(lua.LString(data))
+	return 1
+}
+
+func lines(state *lua.LState) int {
+	path := checkPath(state, 1, false)
+
+	data, err := afs.Afero.ReadFile(path)
+	util.Must(state, err)
+
+	table := state.NewTable()
+	for line := range strings.Lines(string(data)) {
+		if line = strings.TrimSpace(line); line != "" {
+			table.Append(lua.LString(line))
+		}
+	}
+
+	state.Push(table)
+	return 1
+}
+
+func write(state *lua.LState) int {
+	path := checkPath(state, 1, true)
+	content := state.CheckString(2)
+
+	util.Must(state, afs.Afero.MkdirAll(filepath.Dir(path), config.Download.ModeDir.Get()))
+	util.Must(state, afs.Afero.WriteFile(path, []byte(content), config.Download.ModeFile.Get()))
+	return 0
+}
+
+func appendFile(state *lua.LState) int {
+	path := checkPath(state, 1, true)
+	content := state.CheckString(2)
+
+	file, err := afs.Afero.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, config.Download.ModeFile.Get())
+	util.Must(state, err)
+
+	_, err = file.WriteString(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	util.Must(state, err)
+	return 0
+}
+
+func list(state *lua.LState) int {
+	path := checkPath(state, 1, false)
+
+	entries, err := afs.Afero.ReadDir(path)
+	util.Must(state, err)
+
+	state.Push(util.SliceToTable(state, entries, func(entry fs.FileInfo) lua.LValue {
+		table := state.NewTable()
+		table.RawSetString("name", lua.LString(entry.Name()))
+		table.RawSetString("path", lua.LString(filepath.Join(path, entry.Name())))
+		table.RawSetString("dir", lua.LBool(entry.IsDir()))
+		table.RawSetString("size", lua.LNumber(entry.Size()))
+		return table
+	}))
+	return 1
+}
+
+func mkdir(state *lua.LState) int {
+	path := checkPath(state, 1, true)
+
+	util.Must(state, afs.Afero.MkdirAll(path, config.Download.ModeDir.Get()))
+	return 0
+}
+
+func remove(state *lua.LState) int {
+	path := checkPath(state, 1, true)
+
+	roots, err := absRoots()
+	util.Must(state, err)
+	if slices.Contains(roots, path) {
+		state.ArgError(1, "can't remove a root directory")
+	}
+
+	util.Must(state, afs.Afero.Remove(path))
+	return 0
+}
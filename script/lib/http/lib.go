@@ -0,0 +1,270 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	luadoc "github.com/luevano/gopher-luadoc"
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/script/lib/util"
+	"github.com/luevano/mangal/util/httprec"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const libName = "http"
+
+// maxBodySize is the maximum response body size read, 32MiB.
+const maxBodySize = 32 << 20
+
+var (
+	headersType  = luadoc.Map(luadoc.String, luadoc.String)
+	responseType = luadoc.TableLiteral(
+		"status", luadoc.Number,
+		"body", luadoc.String,
+		"headers", headersType,
+	)
+)
+
+func Lib() *luadoc.Lib {
+	return &luadoc.Lib{
+		Name:        libName,
+		Description: "Simple HTTP requests, restricted to the script.http.allowed_hosts",
+		Funcs: []*luadoc.Func{
+			{
+				Name:        "request",
+				Description: "Make an HTTP request",
+				Value:       request,
+				Params: []*luadoc.Param{
+					{
+						Name:        "options",
+						Description: "Method defaults to GET, Content-Type defaults to application/json when there is a body",
+						Type: luadoc.TableLiteral(
+							"url", luadoc.String,
+							"method", luadoc.String+"?",
+							"headers", headersType+"?",
+							"body", luadoc.String+"?",
+						),
+					},
+				},
+				Returns: []*luadoc.Param{
+					{
+						Name: "response",
+						Type: responseType,
+					},
+				},
+			},
+			{
+				Name:        "get",
+				Description: "Make a GET request",
+				Value:       get,
+				Params: []*luadoc.Param{
+					{
+						Name: "url",
+						Type: luadoc.String,
+					},
+					{
+						Name:     "headers",
+						Type:     headersType,
+						Optional: true,
+					},
+				},
+				Returns: []*luadoc.Param{
+					{
+						Name: "response",
+						Type: responseType,
+					},
+				},
+			},
+			{
+				Name:        "post",
+				Description: "Make a POST request, Content-Type defaults to application/json",
+				Value:       post,
+				Params: []*luadoc.Param{
+					{
+						Name: "url",
+						Type: luadoc.String,
+					},
+					{
+						Name: "body",
+						Type: luadoc.String,
+					},
+					{
+						Name:     "headers",
+						Type:     headersType,
+						Optional: true,
+					},
+				},
+				Returns: []*luadoc.Param{
+					{
+						Name: "response",
+						Type: responseType,
+					},
+				},
+			},
+		},
+	}
+}
+
+// allowed returns an error if requests to the URL are not allowed by the config.
+func allowed(u *url.URL) error {
+	if !config.Script.HTTP.Enabled.Get() {
+		return errors.New("http requests are disabled (script.http.enabled)")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+
+	hosts := config.ScriptHTTPAllowedHosts()
+	if len(hosts) == 0 {
+		return nil
+	}
+	host := strings.ToLower(u.Hostname())
+	if slices.ContainsFunc(hosts, func(allowed string) bool {
+		allowed = strings.ToLower(allowed)
+		return host == allowed || strings.HasSuffix(host, "."+allowed)
+	}) {
+		return nil
+	}
+	return fmt.Errorf("host %q is not allowed (script.http.allowed_hosts)", host)
+}
+
+// Restricted returns true if the config limits the requests (disabled or
+// with allowed hosts), applied to the sdk modules of the scripts too.
+func Restricted() bool {
+	return !config.Script.HTTP.Enabled.Get() || len(config.ScriptHTTPAllowedHosts()) != 0
+}
+
+// SDKClient returns the client for the sdk http module, with the
+// same restrictions as this module.
+func SDKClient() (*http.Client, error) {
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	client.Transport = guard{client.Transport}
+	return client, nil
+}
+
+// guard only lets through the requests allowed by the config.
+type guard struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (g guard) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := allowed(req.URL); err != nil {
+		return nil, err
+	}
+	return g.next.RoundTrip(req)
+}
+
+func newClient() (*http.Client, error) {
+	timeout, err := time.ParseDuration(config.Script.HTTP.Timeout.Get())
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: httprec.Transport(nil),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return allowed(req.URL)
+		},
+	}, nil
+}
+
+func do(state *lua.LState, method, rawURL, body string, headers *lua.LTable) int {
+	u, err := url.Parse(rawURL)
+	util.Must(state, err)
+	util.Must(state, allowed(u))
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(state.Context(), method, u.String(), reader)
+	util.Must(state, err)
+
+	req.Header.Set("User-Agent", config.Download.UserAgent.Get())
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if headers != nil {
+		headers.ForEach(func(key, value lua.LValue) {
+			req.Header.Set(key.String(), value.String())
+		})
+	}
+
+	client, err := newClient()
+	util.Must(state, err)
+
+	resp, err := client.Do(req)
+	util.Must(state, err)
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	util.Must(state, err)
+
+	respHeaders := state.NewTable()
+	for key := range resp.Header {
+		respHeaders.RawSetString(key, lua.LString(resp.Header.Get(key)))
+	}
+
+	response := state.NewTable()
+	response.RawSetString("status", lua.LNumber(resp.StatusCode))
+	response.RawSetString("body", lua.LString(data))
+	response.RawSetString("headers", respHeaders)
+
+	state.Push(response)
+	return 1
+}
+
+func optTable(state *lua.LState, n int) *lua.LTable {
+	if state.Get(n) == lua.LNil {
+		return nil
+	}
+	return state.CheckTable(n)
+}
+
+func request(state *lua.LState) int {
+	options := state.CheckTable(1)
+
+	rawURL, ok := options.RawGetString("url").(lua.LString)
+	if !ok || rawURL == "" {
+		state.ArgError(1, "url expected")
+	}
+	method := http.MethodGet
+	if m, ok := options.RawGetString("method").(lua.LString); ok && m != "" {
+		method = strings.ToUpper(string(m))
+	}
+	var body string
+	if b, ok := options.RawGetString("body").(lua.LString); ok {
+		body = string(b)
+	}
+	headers, _ := options.RawGetString("headers").(*lua.LTable)
+
+	return do(state, method, string(rawURL), body, headers)
+}
+
+func get(state *lua.LState) int {
+	rawURL := state.CheckString(1)
+	headers := optTable(state, 2)
+
+	return do(state, http.MethodGet, rawURL, "", headers)
+}
+
+func post(state *lua.LState) int {
+	rawURL := state.CheckString(1)
+	body := state.CheckString(2)
+	headers := optTable(state, 3)
+
+	return do(state, http.MethodPost, rawURL, body, headers)
+}
@@ -1,32 +1,71 @@
 package lib
 
 import (
+	"slices"
+
 	luadoc "github.com/luevano/gopher-luadoc"
 	"github.com/luevano/libmangal"
 	"github.com/luevano/libmangal/metadata"
 	sdk "github.com/luevano/luaprovider/lib"
+	mangalanilist "github.com/luevano/mangal/client/anilist"
+	mangalconfig "github.com/luevano/mangal/config"
 	"github.com/luevano/mangal/meta"
 	"github.com/luevano/mangal/script/lib/anilist"
 	"github.com/luevano/mangal/script/lib/client"
+	"github.com/luevano/mangal/script/lib/config"
+	"github.com/luevano/mangal/script/lib/fs"
+	"github.com/luevano/mangal/script/lib/http"
 	"github.com/luevano/mangal/script/lib/json"
+	"github.com/luevano/mangal/script/lib/library"
+	"github.com/luevano/mangal/script/lib/notify"
 	"github.com/luevano/mangal/script/lib/prompt"
+	"github.com/luevano/mangal/script/lib/providers"
+	"github.com/luevano/mangal/script/lib/regex"
+	"github.com/luevano/mangal/script/lib/strings"
+	"github.com/luevano/mangal/script/lib/time"
+	"github.com/luevano/mangal/script/lib/util"
 	lua "github.com/yuin/gopher-lua"
 )
 
 func Lib(state *lua.LState, lmclient *libmangal.Client) *luadoc.Lib {
 	SDKOptions := sdk.DefaultOptions()
+	// already validated by the config
+	if client, err := http.SDKClient(); err == nil {
+		SDKOptions.HTTPClient = client
+	}
+	SDK := sdk.Lib(state, SDKOptions)
+	// the headless browser can't be limited to the allowed hosts
+	if http.Restricted() {
+		SDK.Libs = slices.DeleteFunc(SDK.Libs, func(lib *luadoc.Lib) bool {
+			return lib.Name == "headless"
+		})
+	}
 
 	libs := []*luadoc.Lib{
-		sdk.Lib(state, SDKOptions),
+		SDK,
 		prompt.Lib(),
 		json.Lib(),
 		client.Lib(lmclient),
+		providers.Lib(),
+		config.Lib(),
+		notify.Lib(),
+		library.Lib(),
+		http.Lib(),
+		fs.Lib(),
+		regex.Lib(),
+		time.Lib(),
+		strings.Lib(),
 	}
 
-	ani, err := lmclient.GetMetadataProvider(metadata.IDSourceAnilist)
-	if err == nil {
-		libs = append(libs, anilist.Lib(ani))
+	// the same anilist every client uses, also without a provider
+	var ani *metadata.ProviderWithCache
+	if lmclient != nil {
+		ani, _ = lmclient.GetMetadataProvider(metadata.IDSourceAnilist)
 	}
+	if ani == nil {
+		ani = mangalanilist.Anilist()
+	}
+	libs = append(libs, anilist.Lib(ani))
 
 	return &luadoc.Lib{
 		Name:        meta.AppName,
@@ -35,7 +74,39 @@ func Lib(state *lua.LState, lmclient *libmangal.Client) *luadoc.Lib {
 	}
 }
 
+// NewState creates a Lua state with all the standard libraries, unless
+// script.sandbox is set: then only with the base, package, table, string, math
+// and coroutine libraries, the filesystem is only reachable through the fs module
+// (restricted by script.fs.roots) and the scripts path (require).
+func NewState() *lua.LState {
+	if !mangalconfig.Script.Sandbox.Get() {
+		return lua.NewState()
+	}
+
+	state := lua.NewState(lua.Options{SkipOpenLibs: true})
+	for _, l := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.LoadLibName, lua.OpenPackage},
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+		{lua.CoroutineLibName, lua.OpenCoroutine},
+	} {
+		state.Push(state.NewFunction(l.open))
+		state.Push(lua.LString(l.name))
+		state.Call(1, 0)
+	}
+	// would read any file
+	state.SetGlobal("dofile", lua.LNil)
+	state.SetGlobal("loadfile", lua.LNil)
+	return state
+}
+
 func Preload(state *lua.LState, client *libmangal.Client) {
 	lib := Lib(state, client)
 	state.PreloadModule(lib.Name, lib.Loader())
+	util.SetPackagePath(state, mangalconfig.ScriptPaths())
 }
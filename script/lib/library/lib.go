@@ -0,0 +1,102 @@
+package library
+
+import (
+	luadoc "github.com/luevano/gopher-luadoc"
+	"github.com/luevano/mangal/library"
+	"github.com/luevano/mangal/script/lib/util"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const libName = "library"
+
+var (
+	chapterType = luadoc.TableLiteral(
+		"name", luadoc.String,
+		"path", luadoc.String,
+		"format", luadoc.String,
+		"volume", luadoc.String,
+	)
+	mangaType = luadoc.TableLiteral(
+		"name", luadoc.String,
+		"title", luadoc.String,
+		"path", luadoc.String,
+		"status", luadoc.String,
+		"year", luadoc.Number,
+		"chapters", luadoc.List(chapterType),
+	)
+)
+
+func Lib() *luadoc.Lib {
+	return &luadoc.Lib{
+		Name:        libName,
+		Description: "Local library (downloaded mangas) index",
+		Funcs: []*luadoc.Func{
+			{
+				Name:        "path",
+				Description: "Get the library path (library.path, falls back to download.path)",
+				Value:       path,
+				Returns: []*luadoc.Param{
+					{
+						Name: "path",
+						Type: luadoc.String,
+					},
+				},
+			},
+			{
+				Name:        "index",
+				Description: "Scan the library and get the downloaded mangas with their chapters",
+				Value:       index,
+				Params: []*luadoc.Param{
+					{
+						Name:        "path",
+						Description: "Path to scan instead of the library path",
+						Type:        luadoc.String,
+						Optional:    true,
+					},
+				},
+				Returns: []*luadoc.Param{
+					{
+						Name:        "mangas",
+						Description: "Status and year are only set if the manga has a series.json",
+						Type:        luadoc.List(mangaType),
+					},
+				},
+			},
+		},
+	}
+}
+
+func path(state *lua.LState) int {
+	state.Push(lua.LString(library.Path()))
+	return 1
+}
+
+func index(state *lua.LState) int {
+	root := state.OptString(1, library.Path())
+
+	idx, err := library.Scan(root)
+	util.Must(state, err)
+
+	table := util.SliceToTable(state, idx.Mangas, func(manga *library.Manga) lua.LValue {
+		m := state.NewTable()
+		m.RawSetString("name", lua.LString(manga.Name))
+		m.RawSetString("title", lua.LString(manga.Title()))
+		m.RawSetString("path", lua.LString(manga.Path))
+		if manga.Series != nil {
+			m.RawSetString("status", lua.LString(manga.Series.Status))
+			m.RawSetString("year", lua.LNumber(manga.Series.Year))
+		}
+		m.RawSetString("chapters", util.SliceToTable(state, manga.Chapters, func(chapter library.Chapter) lua.LValue {
+			c := state.NewTable()
+			c.RawSetString("name", lua.LString(chapter.Name))
+			c.RawSetString("path", lua.LString(chapter.Path))
+			c.RawSetString("format", lua.LString(chapter.Format.String()))
+			c.RawSetString("volume", lua.LString(chapter.Volume))
+			return c
+		}))
+		return m
+	})
+
+	state.Push(table)
+	return 1
+}
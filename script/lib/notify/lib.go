@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"errors"
+
+	luadoc "github.com/luevano/gopher-luadoc"
+	"github.com/luevano/libmangal/mangadata"
+	"github.com/luevano/libmangal/metadata"
+	"github.com/luevano/mangal/notify"
+	"github.com/luevano/mangal/script/lib/util"
+	"github.com/luevano/mangal/util/chapter"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const libName = "notify"
+
+func Lib() *luadoc.Lib {
+	return &luadoc.Lib{
+		Name:        libName,
+		Description: "Send notifications to the configured services",
+		Funcs: []*luadoc.Func{
+			{
+				Name:        "send",
+				Description: "Send the downloaded chapters summary, same as the one sent after downloads",
+				Value:       send,
+				Params: []*luadoc.Param{
+					{
+						Name:        "chapters",
+						Description: "Chapters with their download result, error is set for failed chapters",
+						Type: luadoc.List(luadoc.TableLiteral(
+							"chapter", "client_chapter",
+							"downloaded", "client_downloaded_chapter?",
+							"error", "string?",
+							"source", "string?",
+						)),
+					},
+				},
+			},
+			{
+				Name:        "send_message",
+				Description: "Send a custom message",
+				Value:       sendMessage,
+				Params: []*luadoc.Param{
+					{
+						Name: "title",
+						Type: luadoc.String,
+					},
+					{
+						Name: "message",
+						Type: luadoc.String,
+					},
+				},
+			},
+			{
+				Name:        "send_error",
+				Description: "Send an error message",
+				Value:       sendError,
+				Params: []*luadoc.Param{
+					{
+						Name: "message",
+						Type: luadoc.String,
+					},
+				},
+			},
+		},
+	}
+}
+
+func send(state *lua.LState) int {
+	table := state.CheckTable(1)
+
+	var chapters chapter.Chapters
+	var failed bool
+	table.ForEach(func(_, value lua.LValue) {
+		entry, ok := value.(*lua.LTable)
+		if !ok {
+			failed = true
+			return
+		}
+
+		ch := &chapter.Chapter{}
+		if ud, ok := entry.RawGetString("chapter").(*lua.LUserData); ok {
+			ch.Chapter, _ = ud.Value.(mangadata.Chapter)
+		}
+		if ch.Chapter == nil {
+			failed = true
+			return
+		}
+		if ud, ok := entry.RawGetString("downloaded").(*lua.LUserData); ok {
+			ch.Down, _ = ud.Value.(*metadata.DownloadedChapter)
+		}
+		if err, ok := entry.RawGetString("error").(lua.LString); ok && err != "" {
+			ch.Err = errors.New(string(err))
+		}
+		if source, ok := entry.RawGetString("source").(lua.LString); ok {
+			ch.Source = string(source)
+		}
+		chapters = append(chapters, ch)
+	})
+	if failed {
+		state.ArgError(1, "list of {chapter = client_chapter, ...} expected")
+	}
+
+	util.Must(state, notify.Send(chapters))
+	return 0
+}
+
+func sendMessage(state *lua.LState) int {
+	title := state.CheckString(1)
+	message := state.CheckString(2)
+
+	util.Must(state, notify.SendMessage(title, message))
+	return 0
+}
+
+func sendError(state *lua.LState) int {
+	message := state.CheckString(1)
+
+	toSend := errors.New(message)
+	// SendError returns the same error back on success
+	if err := notify.SendError(toSend); err != toSend {
+		util.Must(state, err)
+	}
+	return 0
+}
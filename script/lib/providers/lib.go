@@ -0,0 +1,101 @@
+package providers
+
+import (
+	"fmt"
+
+	luadoc "github.com/luevano/gopher-luadoc"
+	"github.com/luevano/libmangal"
+	mangalclient "github.com/luevano/mangal/client"
+	"github.com/luevano/mangal/provider/manager"
+	"github.com/luevano/mangal/script/lib/client"
+	"github.com/luevano/mangal/script/lib/util"
+	"github.com/samber/lo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const libName = "providers"
+
+func Lib() *luadoc.Lib {
+	return &luadoc.Lib{
+		Name:        libName,
+		Description: "Installed providers, to use multiple providers in the same script",
+		Funcs: []*luadoc.Func{
+			{
+				Name:        "list",
+				Description: "List the installed providers",
+				Value:       list,
+				Returns: []*luadoc.Param{
+					{
+						Name: "providers",
+						Type: luadoc.List(luadoc.TableLiteral(
+							"id", luadoc.String,
+							"name", luadoc.String,
+							"version", luadoc.String,
+							"description", luadoc.String,
+							"website", luadoc.String,
+						)),
+					},
+				},
+			},
+			{
+				Name:        "load",
+				Description: "Load the provider by ID, the same provider is only loaded once",
+				Value:       load,
+				Params: []*luadoc.Param{
+					{
+						Name:        "id",
+						Description: "Provider ID, as in 'mangal providers list'",
+						Type:        luadoc.String,
+					},
+				},
+				Returns: []*luadoc.Param{
+					{
+						Name:        "client",
+						Description: "Same as the client module, using the loaded provider",
+						Type:        "client",
+					},
+				},
+			},
+		},
+	}
+}
+
+func list(state *lua.LState) int {
+	loaders, err := manager.Loaders()
+	util.Must(state, err)
+
+	state.Push(util.SliceToTable(state, loaders, func(loader libmangal.ProviderLoader) lua.LValue {
+		info := loader.Info()
+		table := state.NewTable()
+		table.RawSetString("id", lua.LString(info.ID))
+		table.RawSetString("name", lua.LString(info.Name))
+		table.RawSetString("version", lua.LString(info.Version))
+		table.RawSetString("description", lua.LString(info.Description))
+		table.RawSetString("website", lua.LString(info.Website))
+		return table
+	}))
+	return 1
+}
+
+func load(state *lua.LState) int {
+	id := state.CheckString(1)
+
+	loaders, err := manager.Loaders()
+	util.Must(state, err)
+
+	loader, ok := lo.Find(loaders, func(loader libmangal.ProviderLoader) bool {
+		return loader.Info().ID == id
+	})
+	if !ok {
+		state.ArgError(1, fmt.Sprintf("provider with ID %q not found", id))
+	}
+
+	c := mangalclient.Get(loader)
+	if c == nil {
+		c, err = mangalclient.NewClient(state.Context(), loader)
+		util.Must(state, err)
+	}
+
+	state.Push(client.Lib(c).Value(state))
+	return 1
+}
@@ -0,0 +1,211 @@
+package regex
+
+import (
+	"regexp"
+
+	luadoc "github.com/luevano/gopher-luadoc"
+	"github.com/luevano/mangal/script/lib/util"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const libName = "regex"
+
+func Lib() *luadoc.Lib {
+	patternParam := &luadoc.Param{
+		Name:        "pattern",
+		Description: "Go regular expression (RE2 syntax)",
+		Type:        luadoc.String,
+	}
+	sParam := &luadoc.Param{
+		Name: "s",
+		Type: luadoc.String,
+	}
+	nParam := &luadoc.Param{
+		Name:        "n",
+		Description: "Maximum number of results, negative (default) for all",
+		Type:        luadoc.Number,
+		Optional:    true,
+	}
+
+	return &luadoc.Lib{
+		Name:        libName,
+		Description: "Regular expressions, using the Go RE2 syntax",
+		Funcs: []*luadoc.Func{
+			{
+				Name:        "match",
+				Description: "Check if the string contains any match of the pattern",
+				Value:       match,
+				Params:      []*luadoc.Param{patternParam, sParam},
+				Returns: []*luadoc.Param{
+					{
+						Name: "matched",
+						Type: luadoc.Boolean,
+					},
+				},
+			},
+			{
+				Name:        "find",
+				Description: "Find the first match of the pattern",
+				Value:       find,
+				Params:      []*luadoc.Param{patternParam, sParam},
+				Returns: []*luadoc.Param{
+					{
+						Name:        "match",
+						Description: "Nil if there is no match",
+						Type:        luadoc.String + "?",
+					},
+				},
+			},
+			{
+				Name:        "find_all",
+				Description: "Find all the successive matches of the pattern",
+				Value:       findAll,
+				Params:      []*luadoc.Param{patternParam, sParam, nParam},
+				Returns: []*luadoc.Param{
+					{
+						Name: "matches",
+						Type: luadoc.List(luadoc.String),
+					},
+				},
+			},
+			{
+				Name:        "submatch",
+				Description: "Find the first match of the pattern and its groups",
+				Value:       submatch,
+				Params:      []*luadoc.Param{patternParam, sParam},
+				Returns: []*luadoc.Param{
+					{
+						Name:        "groups",
+						Description: "The whole match followed by the groups, nil if there is no match",
+						Type:        luadoc.List(luadoc.String) + "?",
+					},
+				},
+			},
+			{
+				Name:        "replace",
+				Description: "Replace all the matches of the pattern, $1 or ${name} in the replacement are expanded to the groups",
+				Value:       replace,
+				Params: []*luadoc.Param{
+					patternParam,
+					sParam,
+					{
+						Name: "replacement",
+						Type: luadoc.String,
+					},
+				},
+				Returns: []*luadoc.Param{
+					{
+						Name: "replaced",
+						Type: luadoc.String,
+					},
+				},
+			},
+			{
+				Name:        "split",
+				Description: "Split the string by the matches of the pattern",
+				Value:       split,
+				Params:      []*luadoc.Param{patternParam, sParam, nParam},
+				Returns: []*luadoc.Param{
+					{
+						Name: "parts",
+						Type: luadoc.List(luadoc.String),
+					},
+				},
+			},
+			{
+				Name:        "quote",
+				Description: "Escape all the regular expression metacharacters of the string",
+				Value:       quote,
+				Params:      []*luadoc.Param{sParam},
+				Returns: []*luadoc.Param{
+					{
+						Name: "quoted",
+						Type: luadoc.String,
+					},
+				},
+			},
+		},
+	}
+}
+
+func checkRegexp(state *lua.LState, n int) *regexp.Regexp {
+	re, err := regexp.Compile(state.CheckString(n))
+	if err != nil {
+		state.ArgError(n, err.Error())
+	}
+	return re
+}
+
+func stringsTable(state *lua.LState, s []string) *lua.LTable {
+	return util.SliceToTable(state, s, func(s string) lua.LValue {
+		return lua.LString(s)
+	})
+}
+
+func match(state *lua.LState) int {
+	re := checkRegexp(state, 1)
+	s := state.CheckString(2)
+
+	state.Push(lua.LBool(re.MatchString(s)))
+	return 1
+}
+
+func find(state *lua.LState) int {
+	re := checkRegexp(state, 1)
+	s := state.CheckString(2)
+
+	loc := re.FindStringIndex(s)
+	if loc == nil {
+		state.Push(lua.LNil)
+		return 1
+	}
+	state.Push(lua.LString(s[loc[0]:loc[1]]))
+	return 1
+}
+
+func findAll(state *lua.LState) int {
+	re := checkRegexp(state, 1)
+	s := state.CheckString(2)
+	n := state.OptInt(3, -1)
+
+	state.Push(stringsTable(state, re.FindAllString(s, n)))
+	return 1
+}
+
+func submatch(state *lua.LState) int {
+	re := checkRegexp(state, 1)
+	s := state.CheckString(2)
+
+	groups := re.FindStringSubmatch(s)
+	if groups == nil {
+		state.Push(lua.LNil)
+		return 1
+	}
+	state.Push(stringsTable(state, groups))
+	return 1
+}
+
+func replace(state *lua.LState) int {
+	re := checkRegexp(state, 1)
+	s := state.CheckString(2)
+	replacement := state.CheckString(3)
+
+	state.Push(lua.LString(re.ReplaceAllString(s, replacement)))
+	return 1
+}
+
+func split(state *lua.LState) int {
+	re := checkRegexp(state, 1)
+	s := state.CheckString(2)
+	n := state.OptInt(3, -1)
+
+	state.Push(stringsTable(state, re.Split(s, n)))
+	return 1
+}
+
+func quote(state *lua.LState) int {
+	s := state.CheckString(1)
+
+	state.Push(lua.LString(regexp.QuoteMeta(s)))
+	return 1
+}
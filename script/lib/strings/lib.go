@@ -0,0 +1,276 @@
+package strings
+
+import (
+	"strings"
+
+	luadoc "github.com/luevano/gopher-luadoc"
+	"github.com/luevano/mangal/script/lib/util"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const libName = "strings"
+
+func Lib() *luadoc.Lib {
+	sParam := &luadoc.Param{
+		Name: "s",
+		Type: luadoc.String,
+	}
+	substrParam := &luadoc.Param{
+		Name: "substr",
+		Type: luadoc.String,
+	}
+	stringReturn := []*luadoc.Param{
+		{
+			Name: "result",
+			Type: luadoc.String,
+		},
+	}
+	booleanReturn := []*luadoc.Param{
+		{
+			Name: "result",
+			Type: luadoc.Boolean,
+		},
+	}
+	listReturn := []*luadoc.Param{
+		{
+			Name: "parts",
+			Type: luadoc.List(luadoc.String),
+		},
+	}
+
+	return &luadoc.Lib{
+		Name:        libName,
+		Description: "Plain (no patterns) string utilities, see regex for patterns",
+		Funcs: []*luadoc.Func{
+			{
+				Name:        "split",
+				Description: "Split the string by the separator",
+				Value:       split,
+				Params: []*luadoc.Param{
+					sParam,
+					{
+						Name: "sep",
+						Type: luadoc.String,
+					},
+					{
+						Name:        "n",
+						Description: "Maximum number of parts, negative (default) for all",
+						Type:        luadoc.Number,
+						Optional:    true,
+					},
+				},
+				Returns: listReturn,
+			},
+			{
+				Name:        "fields",
+				Description: "Split the string by whitespace",
+				Value:       fields,
+				Params:      []*luadoc.Param{sParam},
+				Returns:     listReturn,
+			},
+			{
+				Name:        "join",
+				Description: "Join the strings with the separator",
+				Value:       join,
+				Params: []*luadoc.Param{
+					{
+						Name: "list",
+						Type: luadoc.List(luadoc.String),
+					},
+					{
+						Name: "sep",
+						Type: luadoc.String,
+					},
+				},
+				Returns: stringReturn,
+			},
+			{
+				Name:        "trim",
+				Description: "Remove the leading and trailing whitespace, or the characters in cutset",
+				Value:       trim,
+				Params: []*luadoc.Param{
+					sParam,
+					{
+						Name:     "cutset",
+						Type:     luadoc.String,
+						Optional: true,
+					},
+				},
+				Returns: stringReturn,
+			},
+			{
+				Name:        "trim_prefix",
+				Description: "Remove the prefix, if present",
+				Value:       trimPrefix,
+				Params:      []*luadoc.Param{sParam, substrParam},
+				Returns:     stringReturn,
+			},
+			{
+				Name:        "trim_suffix",
+				Description: "Remove the suffix, if present",
+				Value:       trimSuffix,
+				Params:      []*luadoc.Param{sParam, substrParam},
+				Returns:     stringReturn,
+			},
+			{
+				Name:        "has_prefix",
+				Description: "Check if the string starts with the prefix",
+				Value:       hasPrefix,
+				Params:      []*luadoc.Param{sParam, substrParam},
+				Returns:     booleanReturn,
+			},
+			{
+				Name:        "has_suffix",
+				Description: "Check if the string ends with the suffix",
+				Value:       hasSuffix,
+				Params:      []*luadoc.Param{sParam, substrParam},
+				Returns:     booleanReturn,
+			},
+			{
+				Name:        "contains",
+				Description: "Check if the string contains the substring",
+				Value:       contains,
+				Params:      []*luadoc.Param{sParam, substrParam},
+				Returns:     booleanReturn,
+			},
+			{
+				Name:        "replace",
+				Description: "Replace the occurrences of old with new",
+				Value:       replace,
+				Params: []*luadoc.Param{
+					sParam,
+					{
+						Name: "old",
+						Type: luadoc.String,
+					},
+					{
+						Name: "new",
+						Type: luadoc.String,
+					},
+					{
+						Name:        "n",
+						Description: "Maximum number of replacements, negative (default) for all",
+						Type:        luadoc.Number,
+						Optional:    true,
+					},
+				},
+				Returns: stringReturn,
+			},
+			{
+				Name:        "lower",
+				Description: "Convert the string to lower case",
+				Value:       lower,
+				Params:      []*luadoc.Param{sParam},
+				Returns:     stringReturn,
+			},
+			{
+				Name:        "upper",
+				Description: "Convert the string to upper case",
+				Value:       upper,
+				Params:      []*luadoc.Param{sParam},
+				Returns:     stringReturn,
+			},
+			{
+				Name:        "equal_fold",
+				Description: "Check if the strings are equal, ignoring case",
+				Value:       equalFold,
+				Params:      []*luadoc.Param{sParam, substrParam},
+				Returns:     booleanReturn,
+			},
+		},
+	}
+}
+
+func stringsTable(state *lua.LState, s []string) *lua.LTable {
+	return util.SliceToTable(state, s, func(s string) lua.LValue {
+		return lua.LString(s)
+	})
+}
+
+func split(state *lua.LState) int {
+	s := state.CheckString(1)
+	sep := state.CheckString(2)
+	n := state.OptInt(3, -1)
+
+	state.Push(stringsTable(state, strings.SplitN(s, sep, n)))
+	return 1
+}
+
+func fields(state *lua.LState) int {
+	state.Push(stringsTable(state, strings.Fields(state.CheckString(1))))
+	return 1
+}
+
+func join(state *lua.LState) int {
+	table := state.CheckTable(1)
+	sep := state.CheckString(2)
+
+	var list []string
+	for i := 1; i <= table.Len(); i++ {
+		list = append(list, table.RawGetInt(i).String())
+	}
+
+	state.Push(lua.LString(strings.Join(list, sep)))
+	return 1
+}
+
+func trim(state *lua.LState) int {
+	s := state.CheckString(1)
+	if state.GetTop() < 2 {
+		state.Push(lua.LString(strings.TrimSpace(s)))
+		return 1
+	}
+
+	state.Push(lua.LString(strings.Trim(s, state.CheckString(2))))
+	return 1
+}
+
+func trimPrefix(state *lua.LState) int {
+	state.Push(lua.LString(strings.TrimPrefix(state.CheckString(1), state.CheckString(2))))
+	return 1
+}
+
+func trimSuffix(state *lua.LState) int {
+	state.Push(lua.LString(strings.TrimSuffix(state.CheckString(1), state.CheckString(2))))
+	return 1
+}
+
+func hasPrefix(state *lua.LState) int {
+	state.Push(lua.LBool(strings.HasPrefix(state.CheckString(1), state.CheckString(2))))
+	return 1
+}
+
+func hasSuffix(state *lua.LState) int {
+	state.Push(lua.LBool(strings.HasSuffix(state.CheckString(1), state.CheckString(2))))
+	return 1
+}
+
+func contains(state *lua.LState) int {
+	state.Push(lua.LBool(strings.Contains(state.CheckString(1), state.CheckString(2))))
+	return 1
+}
+
+func replace(state *lua.LState) int {
+	s := state.CheckString(1)
+	old := state.CheckString(2)
+	new := state.CheckString(3)
+	n := state.OptInt(4, -1)
+
+	state.Push(lua.LString(strings.Replace(s, old, new, n)))
+	return 1
+}
+
+func lower(state *lua.LState) int {
+	state.Push(lua.LString(strings.ToLower(state.CheckString(1))))
+	return 1
+}
+
+func upper(state *lua.LState) int {
+	state.Push(lua.LString(strings.ToUpper(state.CheckString(1))))
+	return 1
+}
+
+func equalFold(state *lua.LState) int {
+	state.Push(lua.LBool(strings.EqualFold(state.CheckString(1), state.CheckString(2))))
+	return 1
+}
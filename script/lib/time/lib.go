@@ -0,0 +1,162 @@
+package time
+
+import (
+	"time"
+
+	luadoc "github.com/luevano/gopher-luadoc"
+	"github.com/luevano/mangal/script/lib/util"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const libName = "time"
+
+func Lib() *luadoc.Lib {
+	layoutParam := &luadoc.Param{
+		Name:        "layout",
+		Description: "Go time layout, for example '2006-01-02 15:04'. Defaults to RFC 3339",
+		Type:        luadoc.String,
+		Optional:    true,
+	}
+
+	return &luadoc.Lib{
+		Name:        libName,
+		Description: "Time utilities, times are unix timestamps in seconds",
+		Funcs: []*luadoc.Func{
+			{
+				Name:        "now",
+				Description: "Get the current time",
+				Value:       now,
+				Returns: []*luadoc.Param{
+					{
+						Name:        "time",
+						Description: "Includes the fraction of second",
+						Type:        luadoc.Number,
+					},
+				},
+			},
+			{
+				Name:        "format",
+				Description: "Format the time in the local timezone",
+				Value:       format,
+				Params: []*luadoc.Param{
+					{
+						Name: "time",
+						Type: luadoc.Number,
+					},
+					layoutParam,
+				},
+				Returns: []*luadoc.Param{
+					{
+						Name: "formatted",
+						Type: luadoc.String,
+					},
+				},
+			},
+			{
+				Name:        "parse",
+				Description: "Parse the time, in the local timezone if the layout has none",
+				Value:       parse,
+				Params: []*luadoc.Param{
+					{
+						Name: "value",
+						Type: luadoc.String,
+					},
+					layoutParam,
+				},
+				Returns: []*luadoc.Param{
+					{
+						Name: "time",
+						Type: luadoc.Number,
+					},
+				},
+			},
+			{
+				Name:        "duration",
+				Description: `Parse the duration string, such as "1h30m" or "300ms"`,
+				Value:       duration,
+				Params: []*luadoc.Param{
+					{
+						Name: "value",
+						Type: luadoc.String,
+					},
+				},
+				Returns: []*luadoc.Param{
+					{
+						Name: "seconds",
+						Type: luadoc.Number,
+					},
+				},
+			},
+			{
+				Name:        "sleep",
+				Description: "Pause the script, interrupted if the script is cancelled",
+				Value:       sleep,
+				Params: []*luadoc.Param{
+					{
+						Name: "seconds",
+						Type: luadoc.Number,
+					},
+				},
+			},
+		},
+	}
+}
+
+func toTime(seconds float64) time.Time {
+	return time.UnixMilli(int64(seconds * 1000))
+}
+
+func fromTime(t time.Time) lua.LNumber {
+	return lua.LNumber(float64(t.UnixMilli()) / 1000)
+}
+
+func now(state *lua.LState) int {
+	state.Push(fromTime(time.Now()))
+	return 1
+}
+
+func format(state *lua.LState) int {
+	t := toTime(float64(state.CheckNumber(1)))
+	layout := state.OptString(2, time.RFC3339)
+
+	state.Push(lua.LString(t.Format(layout)))
+	return 1
+}
+
+func parse(state *lua.LState) int {
+	value := state.CheckString(1)
+	layout := state.OptString(2, time.RFC3339)
+
+	t, err := time.ParseInLocation(layout, value, time.Local)
+	util.Must(state, err)
+
+	state.Push(fromTime(t))
+	return 1
+}
+
+func duration(state *lua.LState) int {
+	d, err := time.ParseDuration(state.CheckString(1))
+	util.Must(state, err)
+
+	state.Push(lua.LNumber(d.Seconds()))
+	return 1
+}
+
+func sleep(state *lua.LState) int {
+	d := time.Duration(float64(state.CheckNumber(1)) * float64(time.Second))
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	ctx := state.Context()
+	if ctx == nil {
+		<-timer.C
+		return 0
+	}
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		util.Must(state, ctx.Err())
+	}
+	return 0
+}
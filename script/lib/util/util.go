@@ -2,6 +2,9 @@ package util
 
 import (
 	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
 
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 	lua "github.com/yuin/gopher-lua"
@@ -90,3 +93,69 @@ func ToGoValue(value lua.LValue) any {
 		return nil
 	}
 }
+
+// FromGoValue converts basic Go values (and slices/maps of them) into Lua values,
+// numeric kinds are converted to numbers and any other value to its string representation.
+func FromGoValue(state *lua.LState, value any) lua.LValue {
+	if value == nil {
+		return lua.LNil
+	}
+
+	switch v := value.(type) {
+	case lua.LValue:
+		return v
+	case string:
+		return lua.LString(v)
+	case bool:
+		return lua.LBool(v)
+	case fmt.Stringer:
+		return lua.LString(v.String())
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return lua.LNumber(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return lua.LNumber(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return lua.LNumber(rv.Float())
+	case reflect.Slice, reflect.Array:
+		table := state.NewTable()
+		for i := 0; i < rv.Len(); i++ {
+			table.Append(FromGoValue(state, rv.Index(i).Interface()))
+		}
+		return table
+	case reflect.Map:
+		table := state.NewTable()
+		iter := rv.MapRange()
+		for iter.Next() {
+			table.RawSet(FromGoValue(state, iter.Key().Interface()), FromGoValue(state, iter.Value().Interface()))
+		}
+		return table
+	default:
+		return lua.LString(fmt.Sprint(value))
+	}
+}
+
+// SetPackagePath prepends the directories to the Lua package.path,
+// so that their modules can be required.
+func SetPackagePath(state *lua.LState, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	pkg, ok := state.GetGlobal("package").(*lua.LTable)
+	if !ok {
+		return
+	}
+
+	var patterns []string
+	for _, path := range paths {
+		patterns = append(patterns,
+			filepath.Join(path, "?.lua"),
+			filepath.Join(path, "?", "init.lua"),
+		)
+	}
+	patterns = append(patterns, lua.LVAsString(pkg.RawGetString("path")))
+	pkg.RawSetString("path", lua.LString(strings.Join(patterns, ";")))
+}
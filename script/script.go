@@ -13,9 +13,11 @@ import (
 type Variables = map[string]string
 
 type Args struct {
-	File      string
-	String    string
-	Stdin     bool
+	File   string
+	String string
+	Stdin  bool
+	// Provider is the provider ID of the client module, optional
+	// as other providers can be loaded with the providers module.
 	Provider  string
 	Variables Variables
 }
@@ -33,17 +35,25 @@ func addClient(state *lua.LState, client *libmangal.Client) {
 	lib.Preload(state, client)
 }
 
+// Run the script until it finishes or the context is canceled.
 func Run(ctx context.Context, args Args, script io.Reader) error {
-	client, err := client.NewClientByID(context.Background(), args.Provider)
-	if err != nil {
-		return err
+	defer client.CloseAll()
+
+	var lmclient *libmangal.Client
+	if args.Provider != "" {
+		var err error
+		lmclient, err = client.NewClientByID(ctx, args.Provider)
+		if err != nil {
+			return err
+		}
 	}
 
-	state := lua.NewState()
+	state := lib.NewState()
+	defer state.Close()
 	state.SetContext(ctx)
 
 	addVarsTable(state, args.Variables)
-	addClient(state, client)
+	addClient(state, lmclient)
 
 	lFunction, err := state.Load(script, "script")
 	if err != nil {
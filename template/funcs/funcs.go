@@ -6,6 +6,7 @@ import (
 	"runtime"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/luevano/mangal/util/string/path"
 	"github.com/spf13/viper"
@@ -67,6 +68,42 @@ var Funcs = map[string]TemplateFunc{
 		Value:       strings.ToTitle,
 		Description: "Returns s with all Unicode letters mapped to their Unicode title case.",
 	},
+	"padNumber": {
+		Value:       padNumber,
+		Description: "Zero pads the integer part of the number to the width, keeping the decimals if any: padNumber 3 12.5 -> 012.5.",
+	},
+	"formatDate": {
+		Value:       formatDate,
+		Description: `Formats the date (such as .Chapter.Date) with the Go layout, empty dates result in an empty string: formatDate "2006-01" .Chapter.Date.`,
+	},
+	"now": {
+		Value:       time.Now,
+		Description: "Returns the current time, to use with formatDate.",
+	},
+	"slugify": {
+		Value:       slugify,
+		Description: "Transliterates s and converts it to lowercase words separated by hyphens: \"Ōkami: The Wolf\" -> okami-the-wolf.",
+	},
+	"transliterate": {
+		Value:       transliterate,
+		Description: "Romanizes kana (Hepburn) and removes the latin diacritics: はじめの一歩 -> hajimeno一歩, Pokémon -> Pokemon.",
+	},
+	"truncateBytes": {
+		Value:       truncateBytes,
+		Description: "Truncates s to at most n bytes without cutting a character, useful for filesystem name limits: truncateBytes 255 .Manga.Title.",
+	},
+	"default": {
+		Value:       defaultValue,
+		Description: `Returns the value if not empty, else the default: .Chapter.Title | default "Untitled".`,
+	},
+	"coalesce": {
+		Value:       coalesce,
+		Description: "Returns the first non-empty value: coalesce .Chapter.Title .Manga.Title.",
+	},
+	"toJSON": {
+		Value:       toJSON,
+		Description: "Encodes the value as JSON, useful to build JSON payloads: {\"title\": {{ toJSON .Title }}}.",
+	},
 	"getConfig": {
 		Value:       viper.Get,
 		Description: "Returns the config associated with the given key.",
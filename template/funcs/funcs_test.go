@@ -0,0 +1,99 @@
+package funcs
+
+import (
+	"testing"
+
+	"github.com/luevano/libmangal/metadata"
+)
+
+func TestPadNumber(t *testing.T) {
+	for _, tt := range []struct {
+		width  int
+		number any
+		want   string
+	}{
+		{3, float32(12), "012"},
+		{3, float32(12.5), "012.5"},
+		{3, float32(10.1), "010.1"},
+		{4, float64(-1.25), "-0001.25"},
+		{2, 123, "123"},
+		{4, "7", "0007"},
+	} {
+		got, err := padNumber(tt.width, tt.number)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("padNumber(%d, %v) = %q, want %q", tt.width, tt.number, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	for _, tt := range []struct {
+		date any
+		want string
+	}{
+		{metadata.Date{Year: 2024, Month: 3, Day: 9}, "2024-03-09"},
+		{metadata.Date{Year: 2024}, "2024-01-01"},
+		{metadata.Date{}, ""},
+		{"2021-12-31", "2021-12-31"},
+	} {
+		got, err := formatDate("2006-01-02", tt.date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("formatDate(%v) = %q, want %q", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestTransliterate(t *testing.T) {
+	for s, want := range map[string]string{
+		"Pokémon Ōkami": "Pokemon Okami",
+		"はじめの一歩":        "hajimeno一歩",
+		"しょうねん ジャンプ":    "shounen janpu",
+		"きゃっと":          "kyatto",
+		"マッチ":           "matchi",
+		"ラーメン":          "raamen",
+		"Straße":        "Strasse",
+	} {
+		if got := transliterate(s); got != want {
+			t.Errorf("transliterate(%q) = %q, want %q", s, got, want)
+		}
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	for s, want := range map[string]string{
+		"Ōkami: The Wolf!":      "okami-the-wolf",
+		"  Vol. 1 - Chapter 2 ": "vol-1-chapter-2",
+	} {
+		if got := slugify(s); got != want {
+			t.Errorf("slugify(%q) = %q, want %q", s, got, want)
+		}
+	}
+}
+
+func TestTruncateBytes(t *testing.T) {
+	// "é" is 2 bytes
+	if got := truncateBytes(4, "abcé"); got != "abc" {
+		t.Errorf("truncateBytes = %q, want %q", got, "abc")
+	}
+	if got := truncateBytes(5, "abcé"); got != "abcé" {
+		t.Errorf("truncateBytes = %q, want %q", got, "abcé")
+	}
+}
+
+func TestDefault(t *testing.T) {
+	if got := defaultValue("Untitled", ""); got != "Untitled" {
+		t.Errorf("default = %v, want Untitled", got)
+	}
+	if got := defaultValue("Untitled", "Title"); got != "Title" {
+		t.Errorf("default = %v, want Title", got)
+	}
+	if got := coalesce("", 0, "Manga"); got != "Manga" {
+		t.Errorf("coalesce = %v, want Manga", got)
+	}
+}
@@ -0,0 +1,269 @@
+package funcs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/luevano/libmangal/metadata"
+	"golang.org/x/text/unicode/norm"
+)
+
+// padNumber zero pads the integer part of the number to the width,
+// keeping the decimals only if there are any (12.5 -> 012.5, 12 -> 012).
+func padNumber(width int, number any) (string, error) {
+	var s string
+	switch n := number.(type) {
+	case float32:
+		s = strconv.FormatFloat(float64(n), 'f', -1, 32)
+	case float64:
+		s = strconv.FormatFloat(n, 'f', -1, 64)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		s = fmt.Sprint(n)
+	case string:
+		if _, err := strconv.ParseFloat(n, 64); err != nil {
+			return "", fmt.Errorf("padNumber: %q is not a number", n)
+		}
+		s = n
+	default:
+		return "", fmt.Errorf("padNumber: unsupported type %T", number)
+	}
+
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	integer, decimals, found := strings.Cut(s, ".")
+	if len(integer) < width {
+		integer = strings.Repeat("0", width-len(integer)) + integer
+	}
+	if found {
+		return sign + integer + "." + decimals, nil
+	}
+	return sign + integer, nil
+}
+
+// formatDate formats the date with the Go layout, supported
+// dates are metadata.Date, time.Time and "2006-01-02" strings.
+// Empty dates are formatted as an empty string.
+func formatDate(layout string, date any) (string, error) {
+	var t time.Time
+	switch d := date.(type) {
+	case time.Time:
+		t = d
+	case metadata.Date:
+		if d == (metadata.Date{}) {
+			return "", nil
+		}
+		t = time.Date(d.Year, time.Month(max(d.Month, 1)), max(d.Day, 1), 0, 0, 0, 0, time.Local)
+	case string:
+		if d == "" {
+			return "", nil
+		}
+		var err error
+		t, err = time.ParseInLocation(time.DateOnly, d, time.Local)
+		if err != nil {
+			return "", fmt.Errorf("formatDate: %s", err.Error())
+		}
+	default:
+		return "", fmt.Errorf("formatDate: unsupported type %T", date)
+	}
+	return t.Format(layout), nil
+}
+
+// toJSON encodes the value as JSON, without escaping HTML characters.
+func toJSON(value any) (string, error) {
+	var sb strings.Builder
+	enc := json.NewEncoder(&sb)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return "", fmt.Errorf("toJSON: %s", err.Error())
+	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
+
+// truncateBytes truncates the string to at most n bytes,
+// without cutting a multi-byte character in half.
+func truncateBytes(n int, s string) string {
+	if n < 0 || len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// isEmpty returns true for nil and zero values (empty strings, 0, false, etc.).
+func isEmpty(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
+// defaultValue returns the value, or the default if the value is empty.
+// The value goes last so it can be piped: {{ .Manga.Title | default "Unknown" }}.
+func defaultValue(def, value any) any {
+	if isEmpty(value) {
+		return def
+	}
+	return value
+}
+
+// coalesce returns the first non-empty value.
+func coalesce(values ...any) any {
+	for _, value := range values {
+		if !isEmpty(value) {
+			return value
+		}
+	}
+	return nil
+}
+
+// latinReplacements are the latin letters without a decomposition into base letter and mark.
+var latinReplacements = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "AE", 'œ': "oe", 'Œ': "OE",
+	'ø': "o", 'Ø': "O", 'đ': "d", 'Đ': "D", 'ł': "l", 'Ł': "L",
+	'þ': "th", 'Þ': "TH", 'ð': "d", 'Ð': "D", 'ı': "i",
+}
+
+// kana romanization (Hepburn), katakana is mapped to hiragana first.
+var kana = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ゔ': "vu",
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o", 'ゎ': "wa",
+}
+
+// smallY are the small ya/yu/yo that form digraphs (きゃ -> kya).
+var smallY = map[rune]string{'ゃ': "a", 'ゅ': "u", 'ょ': "o"}
+
+func toHiragana(r rune) rune {
+	if r >= 'ァ' && r <= 'ヶ' {
+		return r - ('ァ' - 'ぁ')
+	}
+	return r
+}
+
+// transliterate romanizes kana and removes the diacritics of latin
+// letters (é -> e). Any other character (such as kanji) is kept as-is.
+func transliterate(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+
+	var (
+		// last romanized kana, to build digraphs and long vowels
+		last    string
+		sokuon  bool
+		lastPos = -1
+	)
+	// NFKC composes the kana with their (han)dakuten and
+	// converts the half/full width forms to the regular ones
+	for _, r := range norm.NFKC.String(s) {
+		h := toHiragana(r)
+
+		if vowel, ok := smallY[h]; ok && strings.HasSuffix(last, "i") && sb.Len() == lastPos {
+			// replace the trailing "i" of the previous kana
+			out := sb.String()
+			sb.Reset()
+			sb.WriteString(out[:len(out)-1])
+			prefix := strings.TrimSuffix(last, "i")
+			if strings.HasSuffix(prefix, "sh") || strings.HasSuffix(prefix, "ch") || strings.HasSuffix(prefix, "j") {
+				last = prefix + vowel
+			} else {
+				last = prefix + "y" + vowel
+			}
+			sb.WriteString(strings.TrimPrefix(last, prefix))
+			lastPos = sb.Len()
+			continue
+		}
+		if vowel, ok := smallY[h]; ok {
+			sb.WriteString("y" + vowel)
+			continue
+		}
+
+		switch {
+		case h == 'っ':
+			sokuon = true
+			continue
+		case r == 'ー':
+			if last != "" && sb.Len() == lastPos {
+				sb.WriteByte(last[len(last)-1])
+				lastPos = sb.Len()
+			}
+			continue
+		}
+
+		if romaji, ok := kana[h]; ok {
+			if sokuon {
+				if strings.HasPrefix(romaji, "ch") {
+					sb.WriteByte('t')
+				} else if !strings.ContainsRune("aeiou", rune(romaji[0])) {
+					sb.WriteByte(romaji[0])
+				}
+				sokuon = false
+			}
+			sb.WriteString(romaji)
+			last = romaji
+			lastPos = sb.Len()
+			continue
+		}
+		sokuon = false
+
+		if replacement, ok := latinReplacements[r]; ok {
+			sb.WriteString(replacement)
+			continue
+		}
+		// remove the diacritics, é -> e + ◌́ -> e
+		for _, d := range norm.NFD.String(string(r)) {
+			if !unicode.Is(unicode.Mn, d) {
+				sb.WriteRune(d)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// slugify transliterates the string and converts it to lowercase
+// words separated by hyphens, only keeping letters and digits.
+func slugify(s string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(transliterate(s)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			hyphen = false
+			sb.WriteRune(r)
+			continue
+		}
+		hyphen = true
+	}
+	return sb.String()
+}
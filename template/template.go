@@ -24,6 +24,13 @@ type volumeTemplateData struct {
 	Manga    mangadata.MangaInfo
 }
 
+type mergeTemplateData struct {
+	Provider libmangal.ProviderInfo
+	Manga    mangadata.MangaInfo
+	From     float32
+	To       float32
+}
+
 type chapterTemplateData struct {
 	Provider libmangal.ProviderInfo
 	Chapter  mangadata.ChapterInfo
@@ -31,6 +38,54 @@ type chapterTemplateData struct {
 	Manga    mangadata.MangaInfo
 }
 
+// MangaData is the data the manga name template is executed with.
+func MangaData(provider libmangal.ProviderInfo, manga mangadata.Manga) any {
+	return MangaInfoData(provider, manga.Info(), manga.Metadata())
+}
+
+// MangaInfoData is the same as MangaData, from the manga info and metadata.
+func MangaInfoData(provider libmangal.ProviderInfo, manga mangadata.MangaInfo, meta metadata.Metadata) any {
+	return mangaTemplateData{
+		Provider: provider,
+		Manga:    manga,
+		Metadata: meta,
+	}
+}
+
+// VolumeData is the data the volume name template is executed with.
+func VolumeData(provider libmangal.ProviderInfo, volume mangadata.Volume) any {
+	return VolumeInfoData(provider, volume.Info(), volume.Manga().Info())
+}
+
+// VolumeInfoData is the same as VolumeData, from the volume and manga info.
+func VolumeInfoData(provider libmangal.ProviderInfo, volume mangadata.VolumeInfo, manga mangadata.MangaInfo) any {
+	return volumeTemplateData{
+		Provider: provider,
+		Volume:   volume,
+		Manga:    manga,
+	}
+}
+
+// ChapterData is the data the chapter name template is executed with.
+func ChapterData(provider libmangal.ProviderInfo, chapter mangadata.Chapter) any {
+	return ChapterInfoData(provider, chapter.Info(), chapter.Volume().Info(), chapter.Volume().Manga().Info())
+}
+
+// ChapterInfoData is the same as ChapterData, from the chapter, volume and manga info.
+func ChapterInfoData(
+	provider libmangal.ProviderInfo,
+	chapter mangadata.ChapterInfo,
+	volume mangadata.VolumeInfo,
+	manga mangadata.MangaInfo,
+) any {
+	return chapterTemplateData{
+		Provider: provider,
+		Chapter:  chapter,
+		Volume:   volume,
+		Manga:    manga,
+	}
+}
+
 func Provider(provider libmangal.ProviderInfo) string {
 	var sb strings.Builder
 
@@ -46,11 +101,15 @@ func Provider(provider libmangal.ProviderInfo) string {
 }
 
 func Manga(provider libmangal.ProviderInfo, manga mangadata.Manga) string {
+	return MangaInfo(provider, manga.Info(), manga.Metadata())
+}
+
+// MangaInfo is the same as Manga, from the manga info and metadata.
+func MangaInfo(provider libmangal.ProviderInfo, manga mangadata.MangaInfo, meta metadata.Metadata) string {
 	var sb strings.Builder
 
 	plt := config.Download.Manga.NameTemplateFallback.Get()
 	// Prioritize the NameTemplate (includes AnilistManga data)
-	meta := manga.Metadata()
 	if metadata.Validate(meta) == nil {
 		plt = config.Download.Manga.NameTemplate.Get()
 	}
@@ -58,11 +117,7 @@ func Manga(provider libmangal.ProviderInfo, manga mangadata.Manga) string {
 	err := template.Must(template.New("manga").
 		Funcs(funcs.FuncMap).
 		Parse(plt)).
-		Execute(&sb, mangaTemplateData{
-			Provider: provider,
-			Manga:    manga.Info(),
-			Metadata: meta,
-		})
+		Execute(&sb, MangaInfoData(provider, manga, meta))
 	if err != nil {
 		util.Errorf("error during execution of the manga name template: %s\n", err)
 	}
@@ -71,16 +126,17 @@ func Manga(provider libmangal.ProviderInfo, manga mangadata.Manga) string {
 }
 
 func Volume(provider libmangal.ProviderInfo, volume mangadata.Volume) string {
+	return VolumeInfo(provider, volume.Info(), volume.Manga().Info())
+}
+
+// VolumeInfo is the same as Volume, from the volume and manga info.
+func VolumeInfo(provider libmangal.ProviderInfo, volume mangadata.VolumeInfo, manga mangadata.MangaInfo) string {
 	var sb strings.Builder
 
 	err := template.Must(template.New("volume").
 		Funcs(funcs.FuncMap).
 		Parse(config.Download.Volume.NameTemplate.Get())).
-		Execute(&sb, volumeTemplateData{
-			Provider: provider,
-			Volume:   volume.Info(),
-			Manga:    volume.Manga().Info(),
-		})
+		Execute(&sb, VolumeInfoData(provider, volume, manga))
 	if err != nil {
 		util.Errorf("error during execution of the volume name template: %s\n", err)
 	}
@@ -89,19 +145,44 @@ func Volume(provider libmangal.ProviderInfo, volume mangadata.Volume) string {
 }
 
 func Chapter(provider libmangal.ProviderInfo, chapter mangadata.Chapter) string {
+	return ChapterInfo(provider, chapter.Info(), chapter.Volume().Info(), chapter.Volume().Manga().Info())
+}
+
+// ChapterInfo is the same as Chapter, from the chapter, volume and manga info.
+func ChapterInfo(
+	provider libmangal.ProviderInfo,
+	chapter mangadata.ChapterInfo,
+	volume mangadata.VolumeInfo,
+	manga mangadata.MangaInfo,
+) string {
 	var sb strings.Builder
 
 	err := template.Must(template.New("chapter").
 		Funcs(funcs.FuncMap).
 		Parse(config.Download.Chapter.NameTemplate.Get())).
-		Execute(&sb, chapterTemplateData{
+		Execute(&sb, ChapterInfoData(provider, chapter, volume, manga))
+	if err != nil {
+		util.Errorf("Error during execution of the chapter name template: %s\n", err)
+	}
+
+	return sb.String()
+}
+
+// Merge names a merged range of chapters, from and to being the first and last chapter numbers.
+func Merge(provider libmangal.ProviderInfo, manga mangadata.Manga, from, to float32) string {
+	var sb strings.Builder
+
+	err := template.Must(template.New("merge").
+		Funcs(funcs.FuncMap).
+		Parse(config.Download.Merge.NameTemplate.Get())).
+		Execute(&sb, mergeTemplateData{
 			Provider: provider,
-			Chapter:  chapter.Info(),
-			Volume:   chapter.Volume().Info(),
-			Manga:    chapter.Volume().Manga().Info(),
+			Manga:    manga.Info(),
+			From:     from,
+			To:       to,
 		})
 	if err != nil {
-		util.Errorf("Error during execution of the chapter name template: %s\n", err)
+		util.Errorf("error during execution of the merge name template: %s\n", err)
 	}
 
 	return sb.String()
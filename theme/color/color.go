@@ -2,10 +2,17 @@ package color
 
 import "github.com/charmbracelet/lipgloss"
 
+// DefaultSecondary is the secondary color of the default theme,
+// adaptive to the terminal background.
+var DefaultSecondary = lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}
+
+// The colors of the active theme, the defaults are the dark theme.
+//
+// They are set by theme.Apply, so they need to be read
+// when rendering instead of being copied on initialization.
 var (
 	// Available as styles, too
 	Accent     = lipgloss.Color("#EB5E28")
-	Secondary  = lipgloss.AdaptiveColor{Light: "#A49FA5", Dark: "#777777"}
 	Background = lipgloss.Color("#252422")
 	Success    = lipgloss.Color("#7EC699")
 	Warning    = lipgloss.Color("#EBCA89")
@@ -13,6 +20,9 @@ var (
 	Loading    = lipgloss.Color("#A49FA5")
 	Viewport   = lipgloss.Color("#008080")
 
+	// Available as a style, too
+	Secondary lipgloss.TerminalColor = DefaultSecondary
+
 	// Available only as colors
 	Bright = lipgloss.Color("#FEFEFE")
 
@@ -7,7 +7,9 @@ import (
 )
 
 type icon struct {
-	color   lipgloss.TerminalColor
+	// returns the theme color, as it can change after initialization;
+	// nil for no color
+	color   func() lipgloss.TerminalColor
 	symbols symbols
 }
 
@@ -18,7 +20,10 @@ func (i icon) String() string {
 }
 
 func (i icon) Colored() string {
-	return style.Bold.Base.Foreground(i.color).Render(i.symbols[currentType])
+	if i.color == nil {
+		return style.Bold.Base.Render(i.symbols[currentType])
+	}
+	return style.Bold.Base.Foreground(i.color()).Render(i.symbols[currentType])
 }
 
 func (i icon) Raw() string {
@@ -27,7 +32,7 @@ func (i icon) Raw() string {
 
 var (
 	Confirm = icon{
-		color: color.Accent,
+		color: func() lipgloss.TerminalColor { return color.Accent },
 		symbols: symbols{
 			TypeASCII: "?",
 			TypeNerd:  "\uEB32",
@@ -35,7 +40,7 @@ var (
 	}
 
 	Progress = icon{
-		color: color.Accent,
+		color: func() lipgloss.TerminalColor { return color.Accent },
 		symbols: symbols{
 			TypeASCII: "@",
 			TypeNerd:  "\U000F0997",
@@ -43,7 +48,7 @@ var (
 	}
 
 	Timer = icon{
-		color: color.Accent,
+		color: func() lipgloss.TerminalColor { return color.Accent },
 		symbols: symbols{
 			TypeASCII: "¿",
 			TypeNerd:  "\U000F13AB",
@@ -51,7 +56,7 @@ var (
 	}
 
 	Mark = icon{
-		color: color.Accent,
+		color: func() lipgloss.TerminalColor { return color.Accent },
 		symbols: symbols{
 			TypeASCII: "*",
 			TypeNerd:  "\U000F0F22",
@@ -59,7 +64,7 @@ var (
 	}
 
 	Download = icon{
-		color: color.Accent,
+		color: func() lipgloss.TerminalColor { return color.Accent },
 		symbols: symbols{
 			TypeASCII: "#",
 			TypeNerd:  "\uF019",
@@ -67,7 +72,7 @@ var (
 	}
 
 	Check = icon{
-		color: color.Success,
+		color: func() lipgloss.TerminalColor { return color.Success },
 		symbols: symbols{
 			TypeASCII: "~",
 			TypeNerd:  "\uF05D",
@@ -75,7 +80,7 @@ var (
 	}
 
 	Cross = icon{
-		color: color.Error,
+		color: func() lipgloss.TerminalColor { return color.Error },
 		symbols: symbols{
 			TypeASCII: "x",
 			TypeNerd:  "\uF05C",
@@ -83,7 +88,7 @@ var (
 	}
 
 	Search = icon{
-		color: color.Accent,
+		color: func() lipgloss.TerminalColor { return color.Accent },
 		symbols: symbols{
 			TypeASCII: ">",
 			TypeNerd:  "\uF002",
@@ -91,7 +96,7 @@ var (
 	}
 
 	Recent = icon{
-		color: color.Secondary,
+		color: func() lipgloss.TerminalColor { return color.Secondary },
 		symbols: symbols{
 			TypeASCII: "~",
 			TypeNerd:  "\uF017",
@@ -99,15 +104,23 @@ var (
 	}
 
 	Read = icon{
-		color: color.Secondary,
+		color: func() lipgloss.TerminalColor { return color.Secondary },
 		symbols: symbols{
 			TypeASCII: "r",
 			TypeNerd:  "\U000F0447",
 		},
 	}
 
+	New = icon{
+		color: func() lipgloss.TerminalColor { return color.Success },
+		symbols: symbols{
+			TypeASCII: "+",
+			TypeNerd:  "\uF005",
+		},
+	}
+
 	Available = icon{
-		color: color.Secondary,
+		color: func() lipgloss.TerminalColor { return color.Secondary },
 		symbols: symbols{
 			TypeASCII: "a",
 			TypeNerd:  "\uEB28",
@@ -115,7 +128,7 @@ var (
 	}
 
 	Filter = icon{
-		color: color.Warning,
+		color: func() lipgloss.TerminalColor { return color.Warning },
 		symbols: symbols{
 			TypeASCII: "f",
 			TypeNerd:  "\uF0B0",
@@ -123,7 +136,7 @@ var (
 	}
 
 	Item = icon{
-		color: color.Accent,
+		color: func() lipgloss.TerminalColor { return color.Accent },
 		symbols: symbols{
 			TypeASCII: ">",
 			TypeNerd:  "\uF101",
@@ -131,7 +144,7 @@ var (
 	}
 
 	SubItem = icon{
-		color: color.Accent,
+		color: func() lipgloss.TerminalColor { return color.Accent },
 		symbols: symbols{
 			TypeASCII: "-",
 			TypeNerd:  "\uF105",
@@ -139,7 +152,7 @@ var (
 	}
 
 	LeftHardDivider = icon{
-		color: color.Accent,
+		color: func() lipgloss.TerminalColor { return color.Accent },
 		symbols: symbols{
 			TypeASCII: ">",
 			TypeNerd:  "\uE0B0",
@@ -147,7 +160,7 @@ var (
 	}
 
 	LeftSoftDivider = icon{
-		color: color.Accent,
+		color: func() lipgloss.TerminalColor { return color.Accent },
 		symbols: symbols{
 			TypeASCII: ">",
 			TypeNerd:  "\uE0B1",
@@ -155,7 +168,7 @@ var (
 	}
 
 	RightHardDivider = icon{
-		color: color.Accent,
+		color: func() lipgloss.TerminalColor { return color.Accent },
 		symbols: symbols{
 			TypeASCII: "<",
 			TypeNerd:  "\uE0B2",
@@ -163,7 +176,7 @@ var (
 	}
 
 	RightSoftDivider = icon{
-		color: color.Accent,
+		color: func() lipgloss.TerminalColor { return color.Accent },
 		symbols: symbols{
 			TypeASCII: "<",
 			TypeNerd:  "\uE0B3",
@@ -171,7 +184,6 @@ var (
 	}
 
 	Ellipsis = icon{
-		color: lipgloss.NoColor{},
 		symbols: symbols{
 			TypeASCII: "_",
 			TypeNerd:  "…", // not really nerd font
@@ -179,7 +191,7 @@ var (
 	}
 
 	Separator = icon{
-		color: color.Warning,
+		color: func() lipgloss.TerminalColor { return color.Warning },
 		symbols: symbols{
 			TypeASCII: "-",
 			TypeNerd:  "•", // not really nerd font
@@ -187,7 +199,7 @@ var (
 	}
 
 	BreadcrumbSep = icon{
-		color: color.Warning,
+		color: func() lipgloss.TerminalColor { return color.Warning },
 		symbols: symbols{
 			TypeASCII: "/",
 			TypeNerd:  "/", // not really nerd font
@@ -23,6 +23,13 @@ var (
 	Italic styles = newStyles(lipgloss.NewStyle().Italic(true))
 )
 
+// Refresh rebuilds the styles from the current colors, after changing the theme.
+func Refresh() {
+	Normal = newStyles(lipgloss.NewStyle())
+	Bold = newStyles(lipgloss.NewStyle().Bold(true))
+	Italic = newStyles(lipgloss.NewStyle().Italic(true))
+}
+
 func newStyles(base lipgloss.Style) styles {
 	return styles{
 		Base:       base,
@@ -0,0 +1,301 @@
+// Package theme loads the color schemes (built-in or user defined
+// TOML files) and applies them to the color and style packages.
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/luevano/mangal/theme/color"
+	"github.com/luevano/mangal/theme/style"
+	"github.com/luevano/mangal/util/afs"
+	"github.com/muesli/termenv"
+	"github.com/pelletier/go-toml"
+)
+
+const (
+	Dark         = "dark"
+	Light        = "light"
+	HighContrast = "high-contrast"
+	NoColor      = "no-color"
+)
+
+// EnvNoColor disables the colors when set (to anything), see https://no-color.org.
+const EnvNoColor = "NO_COLOR"
+
+// Theme is a color scheme, the colors are hex ("#RRGGBB") or ANSI (0-255) codes.
+//
+// A user defined theme is a <name>.toml file in the themes directory, as in:
+//
+//	extends = "light"
+//	accent = "#D65D0E"
+//	viewport = "#458588"
+//
+// The colors not set are the ones of the extended theme (dark by default).
+type Theme struct {
+	Name string `toml:"-"`
+	// Extends is the theme the colors not set are taken from.
+	Extends string `toml:"extends"`
+	// NoColor disables all the colors.
+	NoColor bool `toml:"no_color"`
+
+	Accent     string `toml:"accent"`
+	Secondary  string `toml:"secondary"`
+	Background string `toml:"background"`
+	Success    string `toml:"success"`
+	Warning    string `toml:"warning"`
+	Error      string `toml:"error"`
+	Loading    string `toml:"loading"`
+	Viewport   string `toml:"viewport"`
+	// Bright is the text over the colored backgrounds.
+	Bright string `toml:"bright"`
+
+	// Metadata provider colors
+	Provider     string `toml:"provider"`
+	Anilist      string `toml:"anilist"`
+	MyAnimeList  string `toml:"myanimelist"`
+	Kitsu        string `toml:"kitsu"`
+	MangaUpdates string `toml:"mangaupdates"`
+	AnimePlanet  string `toml:"animeplanet"`
+}
+
+var builtins = map[string]Theme{
+	Dark: {
+		Accent:       "#EB5E28",
+		Secondary:    "#777777",
+		Background:   "#252422",
+		Success:      "#7EC699",
+		Warning:      "#EBCA89",
+		Error:        "#E05252",
+		Loading:      "#A49FA5",
+		Viewport:     "#008080",
+		Bright:       "#FEFEFE",
+		Provider:     "#F26F63",
+		Anilist:      "#02A9FF",
+		MyAnimeList:  "#2E51A2",
+		Kitsu:        "#F75239",
+		MangaUpdates: "#F28A2E",
+		AnimePlanet:  "#A72A2D",
+	},
+	Light: {
+		Extends:    Dark,
+		Accent:     "#C8461B",
+		Secondary:  "#6E6A6F",
+		Background: "#FFFCF2",
+		Success:    "#2E8B57",
+		Warning:    "#A87B00",
+		Error:      "#C62828",
+		Loading:    "#6E6A6F",
+		Viewport:   "#006D6D",
+	},
+	HighContrast: {
+		Extends:    Dark,
+		Accent:     "#FF8700",
+		Secondary:  "#D0D0D0",
+		Background: "#000000",
+		Success:    "#00FF87",
+		Warning:    "#FFFF00",
+		Error:      "#FF5F5F",
+		Loading:    "#FFFFFF",
+		Viewport:   "#00FFFF",
+		Bright:     "#FFFFFF",
+	},
+	NoColor: {
+		Extends: Dark,
+		NoColor: true,
+	},
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// Names returns the sorted names of the built-in themes
+// along the ones in the themes directory.
+func Names(dir string) []string {
+	names := slices.Collect(maps.Keys(builtins))
+	entries, err := afs.Afero.ReadDir(dir)
+	if err == nil {
+		for _, entry := range entries {
+			name, ok := strings.CutSuffix(entry.Name(), ".toml")
+			if ok && !entry.IsDir() && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Load the theme by name, a <name>.toml file in the
+// themes directory takes precedence over the built-ins.
+func Load(dir, name string) (Theme, error) {
+	return load(dir, name, nil)
+}
+
+func load(dir, name string, extended []string) (Theme, error) {
+	if slices.Contains(extended, name) {
+		return Theme{}, fmt.Errorf("theme %q extends itself: %s", name, strings.Join(append(extended, name), " -> "))
+	}
+
+	theme, fromFile, err := read(dir, name)
+	if err != nil {
+		return Theme{}, err
+	}
+	theme.Name = name
+	switch {
+	case theme.Extends != "":
+		base, err := load(dir, theme.Extends, append(extended, name))
+		if err != nil {
+			return Theme{}, err
+		}
+		theme.inherit(base)
+	case fromFile:
+		// a file named as a built-in only overrides some of its colors
+		base, ok := builtin(name)
+		if !ok {
+			base, _ = builtin(Dark)
+		}
+		theme.inherit(base)
+	}
+	return theme, theme.validate()
+}
+
+// builtin returns the built-in theme by name, with the colors it extends.
+func builtin(name string) (Theme, bool) {
+	theme, ok := builtins[name]
+	if !ok {
+		return Theme{}, false
+	}
+	if theme.Extends != "" {
+		base, _ := builtin(theme.Extends)
+		theme.inherit(base)
+	}
+	theme.Name = name
+	return theme, true
+}
+
+// read the theme file, or the built-in theme if there is no file.
+func read(dir, name string) (Theme, bool, error) {
+	var theme Theme
+	path := filepath.Join(dir, name+".toml")
+	data, err := afs.Afero.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		builtin, ok := builtins[name]
+		if !ok {
+			return Theme{}, false, fmt.Errorf("unknown theme %q, available: %s", name, strings.Join(Names(dir), ", "))
+		}
+		return builtin, false, nil
+	}
+	if err != nil {
+		return Theme{}, false, err
+	}
+	if err := toml.Unmarshal(data, &theme); err != nil {
+		return Theme{}, false, fmt.Errorf("error reading theme %q: %s", path, err.Error())
+	}
+	return theme, true, nil
+}
+
+// inherit sets the colors not set from the base theme.
+func (t *Theme) inherit(base Theme) {
+	t.NoColor = t.NoColor || base.NoColor
+	for _, c := range []struct{ color, base *string }{
+		{&t.Accent, &base.Accent},
+		{&t.Secondary, &base.Secondary},
+		{&t.Background, &base.Background},
+		{&t.Success, &base.Success},
+		{&t.Warning, &base.Warning},
+		{&t.Error, &base.Error},
+		{&t.Loading, &base.Loading},
+		{&t.Viewport, &base.Viewport},
+		{&t.Bright, &base.Bright},
+		{&t.Provider, &base.Provider},
+		{&t.Anilist, &base.Anilist},
+		{&t.MyAnimeList, &base.MyAnimeList},
+		{&t.Kitsu, &base.Kitsu},
+		{&t.MangaUpdates, &base.MangaUpdates},
+		{&t.AnimePlanet, &base.AnimePlanet},
+	} {
+		if *c.color == "" {
+			*c.color = *c.base
+		}
+	}
+}
+
+func (t Theme) validate() error {
+	for key, c := range map[string]string{
+		"accent":       t.Accent,
+		"secondary":    t.Secondary,
+		"background":   t.Background,
+		"success":      t.Success,
+		"warning":      t.Warning,
+		"error":        t.Error,
+		"loading":      t.Loading,
+		"viewport":     t.Viewport,
+		"bright":       t.Bright,
+		"provider":     t.Provider,
+		"anilist":      t.Anilist,
+		"myanimelist":  t.MyAnimeList,
+		"kitsu":        t.Kitsu,
+		"mangaupdates": t.MangaUpdates,
+		"animeplanet":  t.AnimePlanet,
+	} {
+		if hexColor.MatchString(c) {
+			continue
+		}
+		if n, err := strconv.Atoi(c); err == nil && n >= 0 && n <= 255 {
+			continue
+		}
+		return fmt.Errorf("theme %q: invalid %s color %q, needs to be hex (#RRGGBB) or ANSI (0-255)", t.Name, key, c)
+	}
+	return nil
+}
+
+// Apply sets the theme as the active one.
+func Apply(t Theme) {
+	color.Accent = lipgloss.Color(t.Accent)
+	color.Secondary = lipgloss.Color(t.Secondary)
+	if t.Name == Dark && t.Secondary == builtins[Dark].Secondary {
+		// keep the default adaptive to the terminal background
+		color.Secondary = color.DefaultSecondary
+	}
+	color.Background = lipgloss.Color(t.Background)
+	color.Success = lipgloss.Color(t.Success)
+	color.Warning = lipgloss.Color(t.Warning)
+	color.Error = lipgloss.Color(t.Error)
+	color.Loading = lipgloss.Color(t.Loading)
+	color.Viewport = lipgloss.Color(t.Viewport)
+	color.Bright = lipgloss.Color(t.Bright)
+	color.Provider = lipgloss.Color(t.Provider)
+	color.Anilist = lipgloss.Color(t.Anilist)
+	color.MyAnimeList = lipgloss.Color(t.MyAnimeList)
+	color.Kitsu = lipgloss.Color(t.Kitsu)
+	color.MangaUpdates = lipgloss.Color(t.MangaUpdates)
+	color.AnimePlanet = lipgloss.Color(t.AnimePlanet)
+
+	if t.NoColor {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+	style.Refresh()
+}
+
+// Set loads and applies the theme by name, the no-color
+// theme is always used if the NO_COLOR env is set.
+func Set(dir, name string) error {
+	if os.Getenv(EnvNoColor) != "" {
+		name = NoColor
+	}
+	theme, err := Load(dir, name)
+	if err != nil {
+		return err
+	}
+	Apply(theme)
+	return nil
+}
@@ -0,0 +1,78 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/luevano/mangal/theme/color"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name+".toml"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("gruvbox", "extends = \"light\"\naccent = \"#D65D0E\"\n")
+	write("dark", "viewport = \"4\"\n")
+	write("loop", "extends = \"loop\"\n")
+	write("bad", "error = \"red\"\n")
+
+	theme, err := Load(dir, "gruvbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	light := builtins[Light]
+	if theme.Accent != "#D65D0E" || theme.Background != light.Background {
+		t.Errorf("expected the accent set and the rest from light, got %+v", theme)
+	}
+	// light doesn't set bright, taken from the (overridden) dark
+	if theme.Bright != builtins[Dark].Bright || theme.Viewport != light.Viewport {
+		t.Errorf("expected the colors not in light from dark, got %+v", theme)
+	}
+
+	theme, err = Load(dir, Dark)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if theme.Viewport != "4" || theme.Accent != builtins[Dark].Accent {
+		t.Errorf("expected the dark file to only override the viewport color, got %+v", theme)
+	}
+
+	for _, name := range []string{"loop", "bad", "unknown"} {
+		if _, err := Load(dir, name); err == nil {
+			t.Errorf("expected theme %q to fail", name)
+		}
+	}
+}
+
+func TestApplySecondary(t *testing.T) {
+	dir := t.TempDir()
+	defer Apply(builtins[Dark])
+
+	for _, tc := range []struct {
+		name, file string
+		want       lipgloss.TerminalColor
+	}{
+		{Dark, "", color.DefaultSecondary},
+		{Light, "", lipgloss.Color(builtins[Light].Secondary)},
+		{Dark, "secondary = \"#123456\"\n", lipgloss.Color("#123456")},
+	} {
+		if tc.file != "" {
+			if err := os.WriteFile(filepath.Join(dir, tc.name+".toml"), []byte(tc.file), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		theme, err := Load(dir, tc.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		Apply(theme)
+		if color.Secondary != tc.want {
+			t.Errorf("theme %q secondary = %v, want %v", tc.name, color.Secondary, tc.want)
+		}
+	}
+}
@@ -12,13 +12,19 @@ var (
 	_ help.KeyMap = (*NoKeyMap)(nil)
 )
 
+func init() {
+	util.RegisterKeyMap(util.GlobalKeyMap, func() { newKeyMap() })
+}
+
 func newKeyMap() *keyMap {
+	keys := util.NewKeys(util.GlobalKeyMap)
 	return &keyMap{
-		quit: util.Bind("quit", "ctrl+c"),
-		back: util.Bind("back", "esc"),
-		home: util.Bind("home", "H"),
-		help: util.Bind("help", "?"),
-		log:  util.Bind("log", "ctrl+l"),
+		quit:   keys.Bind("quit", "quit", "ctrl+c"),
+		back:   keys.Bind("back", "back", "esc"),
+		home:   keys.Bind("home", "home", "H"),
+		help:   keys.Bind("help", "help", "?"),
+		log:    keys.Bind("log", "log", "ctrl+l"),
+		basket: keys.Bind("basket", "basket", "B"),
 	}
 }
 
@@ -28,7 +34,8 @@ type keyMap struct {
 	back,
 	home,
 	help,
-	log key.Binding
+	log,
+	basket key.Binding
 }
 
 // ShortHelp implements help.KeyMap.
@@ -47,6 +54,7 @@ func (k keyMap) FullHelp() [][]key.Binding {
 			k.home,
 			k.quit,
 			k.log,
+			k.basket,
 		},
 	}
 }
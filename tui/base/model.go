@@ -21,6 +21,10 @@ type model struct {
 	viewport *viewport.Model
 	history  *history
 
+	// newBasket creates the basket State, basket is the last one opened
+	newBasket func() State
+	basket    State
+
 	ctx       context.Context
 	ctxCancel context.CancelFunc
 
@@ -10,7 +10,9 @@ import (
 	"github.com/luevano/mangal/tui/model/viewport"
 )
 
-func New(state State) *model {
+// New model starting at the state, basket creates the State
+// showing the chapters basket (opened from any State).
+func New(state State, basket func() State) *model {
 	ctx, ctxCancel := context.WithCancel(context.Background())
 
 	_styles := defaultStyles()
@@ -21,6 +23,7 @@ func New(state State) *model {
 
 	model := &model{
 		state:                       state,
+		newBasket:                   basket,
 		viewport:                    viewport.New(),
 		history:                     &history{},
 		ctx:                         ctx,
@@ -32,6 +32,9 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, m.toggleHelp()
 		case key.Matches(msg, m.keyMap.log):
 			return m, Viewport("Logs", log.Aggregate.String(), color.Viewport)
+		case key.Matches(msg, m.keyMap.basket) && m.state.Backable() && m.state != m.basket:
+			m.basket = m.newBasket()
+			return m, m.pushState(m.basket)
 		}
 	// receiving any of these msgs override the behavior of the keybinds;
 	// even if the keybinds are disabled, these messages will work.
@@ -204,4 +207,5 @@ func (m *model) updateKeybinds() {
 	m.keyMap.back.SetEnabled(enable)
 	m.keyMap.home.SetEnabled(enable)
 	m.keyMap.log.SetEnabled(enable)
+	m.keyMap.basket.SetEnabled(enable)
 }
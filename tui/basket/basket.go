@@ -0,0 +1,109 @@
+// Package basket keeps the chapters selected in the TUI across states
+// (volumes, mangas and providers), to download all of them in one run.
+package basket
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/luevano/libmangal"
+	"github.com/luevano/libmangal/mangadata"
+)
+
+// Entry is a chapter in the basket, along the client of its provider.
+type Entry struct {
+	Client  *libmangal.Client
+	Chapter mangadata.Chapter
+}
+
+// key identifies the chapter, as the same chapter can be
+// a different value after being fetched again.
+func (e Entry) key() string {
+	info := e.Chapter.Info()
+	if info.URL != "" {
+		return e.Client.Info().ID + "\x00" + info.URL
+	}
+	volume := e.Chapter.Volume()
+	return e.Client.Info().ID + "\x00" +
+		volume.Manga().Info().ID + "\x00" +
+		strconv.FormatFloat(float64(volume.Info().Number), 'f', -1, 32) + "\x00" +
+		strconv.FormatFloat(float64(info.Number), 'f', -1, 32)
+}
+
+var (
+	mu      sync.Mutex
+	entries []Entry
+)
+
+func index(e Entry) int {
+	key := e.key()
+	return slices.IndexFunc(entries, func(other Entry) bool {
+		return other.key() == key
+	})
+}
+
+// Has returns true if the chapter is in the basket.
+func Has(client *libmangal.Client, chapter mangadata.Chapter) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return index(Entry{Client: client, Chapter: chapter}) != -1
+}
+
+// Add the chapter to the end of the basket, if not already in it.
+func Add(client *libmangal.Client, chapter mangadata.Chapter) {
+	mu.Lock()
+	defer mu.Unlock()
+	e := Entry{Client: client, Chapter: chapter}
+	if index(e) == -1 {
+		entries = append(entries, e)
+	}
+}
+
+// Remove the chapter from the basket.
+func Remove(client *libmangal.Client, chapter mangadata.Chapter) {
+	mu.Lock()
+	defer mu.Unlock()
+	if i := index(Entry{Client: client, Chapter: chapter}); i != -1 {
+		entries = slices.Delete(entries, i, i+1)
+	}
+}
+
+// Toggle adds the chapter if not in the basket, else removes it.
+// Returns true if it was added.
+func Toggle(client *libmangal.Client, chapter mangadata.Chapter) bool {
+	if Has(client, chapter) {
+		Remove(client, chapter)
+		return false
+	}
+	Add(client, chapter)
+	return true
+}
+
+// Entries returns a copy of the entries, in order.
+func Entries() []Entry {
+	mu.Lock()
+	defer mu.Unlock()
+	return slices.Clone(entries)
+}
+
+// Set replaces the entries, used to reorder them.
+func Set(newEntries []Entry) {
+	mu.Lock()
+	defer mu.Unlock()
+	entries = slices.Clone(newEntries)
+}
+
+// Len returns the amount of chapters in the basket.
+func Len() int {
+	mu.Lock()
+	defer mu.Unlock()
+	return len(entries)
+}
+
+// Clear empties the basket.
+func Clear() {
+	mu.Lock()
+	defer mu.Unlock()
+	entries = nil
+}
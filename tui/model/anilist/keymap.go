@@ -8,22 +8,30 @@ import (
 
 var _ help.KeyMap = (*keyMap)(nil)
 
+// keyMapName is the name of the keymap in the config (tui.keys.anilist_auth).
+const keyMapName = "anilist_auth"
+
+func init() {
+	util.RegisterKeyMap(keyMapName, func() { newKeyMap() })
+}
+
 func newKeyMap() keyMap {
+	keys := util.NewKeys(keyMapName)
 	return keyMap{
-		login:   util.Bind("login", "i"),
-		logout:  util.Bind("logout", "o"),
-		open:    util.Bind("open auth url", "ctrl+o"),
-		new:     util.Bind("new", "n"),
-		delete:  util.Bind("forget", "ctrl+d"),
-		up:      util.BindNamedKey("↑/k", "up", "k", "up"),
-		down:    util.BindNamedKey("↓/j", "down", "j", "down"),
-		selekt:  util.Bind("edit field", "enter"),
-		confirm: util.Bind("confirm", "enter"),
-		cancel:  util.Bind("cancel", "esc"),
-		clear:   util.Bind("clear field", "c"),
-		back:    util.Bind("select login", "esc"),
-		help:    util.Bind("help", "?"),
-		quit:    util.Bind("quit", "q", "ctrl+c"),
+		login:   keys.Bind("login", "login", "i"),
+		logout:  keys.Bind("logout", "logout", "o"),
+		open:    keys.Bind("open_auth_url", "open auth url", "ctrl+o"),
+		new:     keys.Bind("new", "new", "n"),
+		delete:  keys.Bind("forget", "forget", "ctrl+d"),
+		up:      keys.BindNamedKey("up", "↑/k", "up", "k", "up"),
+		down:    keys.BindNamedKey("down", "↓/j", "down", "j", "down"),
+		selekt:  keys.Bind("select", "edit field", "enter"),
+		confirm: keys.Bind("confirm", "confirm", "enter"),
+		cancel:  keys.Bind("cancel", "cancel", "esc"),
+		clear:   keys.Bind("clear", "clear field", "c"),
+		back:    keys.Bind("back", "select login", "esc"),
+		help:    keys.Bind("help", "help", "?"),
+		quit:    keys.Bind("quit", "quit", "q", "ctrl+c"),
 	}
 }
 
@@ -8,10 +8,18 @@ import (
 
 var _ help.KeyMap = (*keyMap)(nil)
 
+// keyMapName is the name of the keymap in the config (tui.keys.confirm).
+const keyMapName = "confirm"
+
+func init() {
+	util.RegisterKeyMap(keyMapName, func() { newKeyMap() }, util.GlobalKeyMap)
+}
+
 func newKeyMap() keyMap {
+	keys := util.NewKeys(keyMapName)
 	return keyMap{
-		yes: util.Bind("yes", "y", "enter"),
-		no:  util.Bind("no", "n", "esc"),
+		yes: keys.Bind("yes", "yes", "y", "enter"),
+		no:  keys.Bind("no", "no", "n", "esc"),
 	}
 }
 
@@ -31,3 +31,10 @@ func (m *Model) setFormatForCmd(what forWhat, format libmangal.Format) tea.Cmd {
 		return config.Write()
 	}
 }
+
+// applyDeviceCmd applies the device for the session only, the config is not written.
+func (m *Model) applyDeviceCmd(device config.Device) tea.Cmd {
+	return func() tea.Msg {
+		return device.Apply()
+	}
+}
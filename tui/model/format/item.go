@@ -6,20 +6,21 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/luevano/libmangal"
 	"github.com/luevano/mangal/config"
-	"github.com/luevano/mangal/theme/style"
 )
 
 var (
 	_ list.Item        = (*item)(nil)
 	_ list.DefaultItem = (*item)(nil)
+	_ list.Item        = (*deviceItem)(nil)
+	_ list.DefaultItem = (*deviceItem)(nil)
 )
 
-// the sep needs to be rendered after the app is
-// completely loaded, else the incorrect icon will be used
+// these need to be rendered after the app is completely
+// loaded, else the incorrect icon and theme will be used
 var (
-	sep  string
-	down = style.Bold.Warning.Render("down")
-	read = style.Bold.Warning.Render("read")
+	sep,
+	down,
+	read string
 )
 
 // item implements list.item.
@@ -68,3 +69,29 @@ func (i *item) isSelectedForDownloading() bool {
 func (i *item) isSelectedForReading() bool {
 	return i.format == config.Read.Format.Get()
 }
+
+// deviceItem implements list.item.
+type deviceItem struct {
+	device config.Device
+}
+
+// FilterValue implements list.Item.
+func (i *deviceItem) FilterValue() string {
+	return i.device.Name
+}
+
+// Title implements list.DefaultItem.
+func (i *deviceItem) Title() string {
+	if !i.device.Applied() {
+		return i.FilterValue()
+	}
+	return i.FilterValue() + sep + down
+}
+
+// Description implements list.DefaultItem.
+func (i *deviceItem) Description() string {
+	if i.device.Description == "" {
+		return i.device.Format
+	}
+	return i.device.Description
+}
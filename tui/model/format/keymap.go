@@ -3,17 +3,26 @@ package format
 import (
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/luevano/mangal/tui/model/list"
 	"github.com/luevano/mangal/tui/util"
 )
 
 var _ help.KeyMap = (*keyMap)(nil)
 
+// keyMapName is the name of the keymap in the config (tui.keys.format).
+const keyMapName = "format"
+
+func init() {
+	util.RegisterKeyMap(keyMapName, func() { newKeyMap() }, util.GlobalKeyMap, list.KeyMapName)
+}
+
 func newKeyMap() keyMap {
+	keys := util.NewKeys(keyMapName)
 	return keyMap{
-		setRead:     util.Bind("read", "r"),
-		setDownload: util.Bind("down", "d"),
-		setBoth:     util.Bind("both", "enter"),
-		back:        util.Bind("back", "esc"),
+		setRead:     keys.Bind("read", "read", "r"),
+		setDownload: keys.Bind("download", "down", "d"),
+		setBoth:     keys.Bind("both", "both", "enter"),
+		back:        keys.Bind("back", "back", "esc"),
 	}
 }
 
@@ -41,6 +41,17 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 			goto end
 		}
 
+		// devices only apply to downloads
+		if d, ok := m.list.SelectedItem().(*deviceItem); ok {
+			switch {
+			case key.Matches(msg, m.keyMap.setDownload), key.Matches(msg, m.keyMap.setBoth):
+				return m.applyDeviceCmd(d.device)
+			case key.Matches(msg, m.keyMap.back):
+				return backCmd
+			}
+			goto end
+		}
+
 		// guaranteed to always have items
 		i, _ := m.list.SelectedItem().(*item)
 		switch {
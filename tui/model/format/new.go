@@ -4,6 +4,7 @@ import (
 	_list "github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/luevano/libmangal"
+	"github.com/luevano/mangal/config"
 	"github.com/luevano/mangal/theme/icon"
 	"github.com/luevano/mangal/theme/style"
 	"github.com/luevano/mangal/tui/base"
@@ -12,12 +13,22 @@ import (
 )
 
 func New(accentColor lipgloss.Color) *Model {
+	var items []_list.DefaultItem
+	for _, format := range libmangal.FormatValues() {
+		items = append(items, &item{format: format})
+	}
+	// devices are validated on config load
+	devices, _ := config.Devices()
+	for _, name := range config.DeviceNames() {
+		items = append(items, &deviceItem{device: devices[name]})
+	}
+
 	list := list.New(
 		2, 0,
-		"manga", "mangas",
-		libmangal.FormatValues(),
-		func(format libmangal.Format) _list.DefaultItem {
-			return &item{format: format}
+		"format", "formats",
+		items,
+		func(item _list.DefaultItem) _list.DefaultItem {
+			return item
 		},
 	)
 	list.SetAccentColor(accentColor)
@@ -26,10 +37,12 @@ func New(accentColor lipgloss.Color) *Model {
 		Background(accentColor).
 		Padding(0, 1).
 		Margin(0, 1, 1, 1).
-		Render("Formats")
+		Render("Formats & devices")
 	// needs to be rendered here, as rendering aat item.go
-	// (on its definition) will load the incorrect icon type
+	// (on its definition) will load the incorrect icon type and theme
 	sep = style.Bold.Warning.Padding(0, 1).Render(icon.Separator.Raw())
+	down = style.Bold.Warning.Render("down")
+	read = style.Bold.Warning.Render("read")
 
 	h := help.New()
 	h.ShowAll = true
@@ -38,9 +51,9 @@ func New(accentColor lipgloss.Color) *Model {
 		list:  list,
 		title: t,
 		help:  h.View(_keyMap),
-		size: base.Size{ // basically perfect size, will make tweakable later
-			Width:  24,
-			Height: 12, // only space required for current formats
+		size: base.Size{ // will make tweakable later
+			Width:  28,
+			Height: 12, // only space required for current formats, devices are paginated
 		},
 		keyMap: _keyMap,
 	}
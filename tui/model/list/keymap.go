@@ -9,10 +9,32 @@ import (
 
 var _ help.KeyMap = (*KeyMap)(nil)
 
+// KeyMapName is the name of the keymap in the config (tui.keys.list),
+// for the states using the list.
+const KeyMapName = "list"
+
+func init() {
+	util.RegisterKeyMap(KeyMapName, func() {
+		listKeyMap := list.DefaultKeyMap()
+		newKeyMap(&listKeyMap)
+	}, util.GlobalKeyMap)
+}
+
 func newKeyMap(listKeyMap *list.KeyMap) KeyMap {
+	keys := util.NewKeys(KeyMapName)
+	keys.Rebind("filter", &listKeyMap.Filter)
+	keys.Rebind("clear_filter", &listKeyMap.ClearFilter)
+	keys.Rebind("cancel_while_filtering", &listKeyMap.CancelWhileFiltering)
+	keys.Rebind("accept_while_filtering", &listKeyMap.AcceptWhileFiltering)
+	keys.Rebind("cursor_up", &listKeyMap.CursorUp)
+	keys.Rebind("cursor_down", &listKeyMap.CursorDown)
+	keys.Rebind("next_page", &listKeyMap.NextPage)
+	keys.Rebind("prev_page", &listKeyMap.PrevPage)
+	keys.Rebind("go_to_start", &listKeyMap.GoToStart)
+	keys.Rebind("go_to_end", &listKeyMap.GoToEnd)
 	return KeyMap{
 		List:    listKeyMap,
-		Reverse: util.Bind("reverse", "R"),
+		Reverse: keys.Bind("reverse", "reverse", "R"),
 	}
 }
 
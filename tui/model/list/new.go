@@ -69,8 +69,9 @@ func New[T any](
 		Model:    l,
 		delegate: &delegate,
 		size:     size,
-		KeyMap:   newKeyMap(&l.KeyMap),
 	}
+	// the list keys need to be the ones of the model, to be rebound
+	s.KeyMap = newKeyMap(&s.Model.KeyMap)
 	s.updateKeybinds()
 	return s
 }
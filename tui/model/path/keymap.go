@@ -8,10 +8,18 @@ import (
 
 var _ help.KeyMap = (*KeyMap)(nil)
 
+// keyMapName is the name of the keymap in the config (tui.keys.path).
+const keyMapName = "path"
+
+func init() {
+	util.RegisterKeyMap(keyMapName, func() { newKeyMap() })
+}
+
 func newKeyMap() KeyMap {
+	keys := util.NewKeys(keyMapName)
 	return KeyMap{
-		Copy: util.Bind("copy", "c", "enter"),
-		quit: util.Bind("quit", "q", "ctrl+c"),
+		Copy: keys.Bind("copy", "copy", "c", "enter"),
+		quit: keys.Bind("quit", "quit", "q", "ctrl+c"),
 	}
 }
 
@@ -5,10 +5,18 @@ import (
 	"github.com/luevano/mangal/tui/util"
 )
 
+// keyMapName is the name of the keymap in the config (tui.keys.search).
+const keyMapName = "search"
+
+func init() {
+	util.RegisterKeyMap(keyMapName, func() { newKeyMap() }, util.GlobalKeyMap)
+}
+
 func newKeyMap() keyMap {
+	keys := util.NewKeys(keyMapName)
 	return keyMap{
-		confirm: util.Bind("confirm", "enter"),
-		cancel:  util.Bind("cancel", "esc"),
+		confirm: keys.Bind("confirm", "confirm", "enter"),
+		cancel:  keys.Bind("cancel", "cancel", "esc"),
 	}
 }
 
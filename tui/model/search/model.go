@@ -23,6 +23,9 @@ type Model struct {
 	state State
 	query string
 
+	// AllowEmpty accepts an empty query, used to clear filters.
+	AllowEmpty bool
+
 	maxWidth int
 	// the Height field in size is used
 	// for the max suggestions to display
@@ -94,7 +97,7 @@ func (m *Model) Update(msg tea.Msg) tea.Cmd {
 		case key.Matches(msg, m.keyMap.confirm):
 			// Remove all surrounding whitespace
 			q := strings.TrimSpace(m.input.Value())
-			if q == "" {
+			if q == "" && !m.AllowEmpty {
 				return base.Notify("Can't search whitespace only")
 			}
 
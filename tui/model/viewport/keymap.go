@@ -9,13 +9,31 @@ import (
 
 var _ help.KeyMap = (*KeyMap)(nil)
 
+// KeyMapName is the name of the keymap in the config (tui.keys.viewport),
+// for the states using the viewport.
+const KeyMapName = "viewport"
+
+func init() {
+	util.RegisterKeyMap(KeyMapName, func() {
+		vieportKeyMap := viewport.DefaultKeyMap()
+		newKeyMap(&vieportKeyMap)
+	}, util.GlobalKeyMap)
+}
+
 func newKeyMap(vieportKeyMap *viewport.KeyMap) KeyMap {
+	keys := util.NewKeys(KeyMapName)
+	keys.Rebind("up", &vieportKeyMap.Up)
+	keys.Rebind("down", &vieportKeyMap.Down)
+	keys.Rebind("half_page_up", &vieportKeyMap.HalfPageUp)
+	keys.Rebind("half_page_down", &vieportKeyMap.HalfPageDown)
+	keys.Rebind("page_up", &vieportKeyMap.PageUp)
+	keys.Rebind("page_down", &vieportKeyMap.PageDown)
 	return KeyMap{
 		Viewport: vieportKeyMap,
-		Copy:     util.Bind("copy content", "c"),
-		GoTop:    util.BindNamedKey("g/home", "go to start", "g", "home"),
-		GoBottom: util.BindNamedKey("G/end", "go to end", "G", "end"),
-		Back:     util.Bind("back", "esc"),
+		Copy:     keys.Bind("copy", "copy content", "c"),
+		GoTop:    keys.BindNamedKey("go_top", "g/home", "go to start", "g", "home"),
+		GoBottom: keys.BindNamedKey("go_bottom", "G/end", "go to end", "G", "end"),
+		Back:     keys.Bind("back", "back", "esc"),
 	}
 }
 
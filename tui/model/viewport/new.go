@@ -13,8 +13,9 @@ func New() *Model {
 		borderHorizontalSize: b.GetLeftSize() + b.GetRightSize(),
 		borderVerticalSize:   b.GetTopSize() + b.GetBottomSize(),
 		style:                lipgloss.NewStyle().BorderStyle(b),
-		KeyMap:               newKeyMap(&v.KeyMap),
 	}
+	// the viewport keys need to be the ones of the model, to be rebound
+	s.KeyMap = newKeyMap(&s.Model.KeyMap)
 	s.updateKeybinds()
 	return s
 }
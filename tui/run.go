@@ -4,11 +4,16 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/luevano/mangal/tui/base"
 	"github.com/luevano/mangal/tui/program"
+	"github.com/luevano/mangal/tui/state/basket"
 	"github.com/luevano/mangal/tui/state/home"
+	"github.com/luevano/mangal/tui/util"
 )
 
 func Run() error {
-	model := base.New(home.New())
+	if err := util.ValidateKeys(); err != nil {
+		return err
+	}
+	model := base.New(home.New(), basket.New)
 	program.SetTUI(tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion()))
 	_, err := program.TUI().Run()
 	return err
@@ -3,18 +3,27 @@ package anilist
 import (
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/luevano/mangal/tui/model/list"
 	"github.com/luevano/mangal/tui/util"
 )
 
 var _ help.KeyMap = (*keyMap)(nil)
 
+// keyMapName is the name of the keymap in the config (tui.keys.anilist).
+const keyMapName = "anilist"
+
+func init() {
+	util.RegisterKeyMap(keyMapName, func() { newKeyMap() }, util.GlobalKeyMap, list.KeyMapName)
+}
+
 func newKeyMap() keyMap {
+	keys := util.NewKeys(keyMapName)
 	return keyMap{
-		confirm:       util.Bind("confirm", "enter"),
-		search:        util.Bind("search", "s"),
-		metadata:      util.Bind("metadata", "m"),
-		cancelSearch:  util.Bind("cancel search", "esc"),
-		confirmSearch: util.Bind("confirm search", "enter"),
+		confirm:       keys.Bind("confirm", "confirm", "enter"),
+		search:        keys.Bind("search", "search", "s"),
+		metadata:      keys.Bind("metadata", "metadata", "m"),
+		cancelSearch:  keys.Bind("cancel_search", "cancel search", "esc"),
+		confirmSearch: keys.Bind("confirm_search", "confirm search", "enter"),
 	}
 }
 
@@ -0,0 +1,36 @@
+package basket
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/tui/basket"
+)
+
+var (
+	_ list.Item        = (*item)(nil)
+	_ list.DefaultItem = (*item)(nil)
+)
+
+// item implements list.item.
+type item struct {
+	entry basket.Entry
+}
+
+// FilterValue implements list.Item.
+func (i *item) FilterValue() string {
+	chapter := i.entry.Chapter
+	return fmt.Sprintf(config.TUI.Chapter.NumberFormat.Get(), chapter.Info().Number) + " " + chapter.Info().Title
+}
+
+// Title implements list.DefaultItem.
+func (i *item) Title() string {
+	return i.FilterValue()
+}
+
+// Description implements list.DefaultItem.
+func (i *item) Description() string {
+	volume := i.entry.Chapter.Volume()
+	return fmt.Sprintf("%s / Volume %s (%s)", volume.Manga(), volume, i.entry.Client.Info().Name)
+}
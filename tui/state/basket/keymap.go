@@ -0,0 +1,57 @@
+package basket
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/luevano/mangal/tui/model/list"
+	"github.com/luevano/mangal/tui/util"
+)
+
+var _ help.KeyMap = (*keyMap)(nil)
+
+// keyMapName is the name of the keymap in the config (tui.keys.basket).
+const keyMapName = "basket"
+
+func init() {
+	util.RegisterKeyMap(keyMapName, func() { newKeyMap() }, util.GlobalKeyMap, list.KeyMapName)
+}
+
+func newKeyMap() keyMap {
+	keys := util.NewKeys(keyMapName)
+	return keyMap{
+		download: keys.Bind("download", "download all", "d"),
+		remove:   keys.Bind("remove", "remove", "x"),
+		clear:    keys.Bind("clear", "clear", "X"),
+		moveUp:   keys.Bind("move_up", "move up", "K"),
+		moveDown: keys.Bind("move_down", "move down", "J"),
+		sort:     keys.Bind("sort", "sort", "s"),
+	}
+}
+
+// keyMap implements help.keyMap.
+type keyMap struct {
+	download,
+	remove,
+	clear,
+	moveUp,
+	moveDown,
+	sort key.Binding
+}
+
+// ShortHelp implements help.keyMap.
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		k.download,
+		k.remove,
+		k.moveUp,
+		k.moveDown,
+	}
+}
+
+// FullHelp implements help.keyMap.
+func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		k.ShortHelp(),
+		{k.sort, k.clear},
+	}
+}
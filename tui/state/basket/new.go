@@ -0,0 +1,27 @@
+package basket
+
+import (
+	_list "github.com/charmbracelet/bubbles/list"
+	"github.com/luevano/mangal/tui/base"
+	"github.com/luevano/mangal/tui/basket"
+	"github.com/luevano/mangal/tui/model/list"
+)
+
+// New is the view of the chapters in the basket.
+func New() base.State {
+	listWrapper := list.New(
+		2, 1,
+		"chapter", "chapters",
+		basket.Entries(),
+		func(entry basket.Entry) _list.DefaultItem {
+			return &item{entry}
+		},
+	)
+
+	s := &state{
+		list:   listWrapper,
+		keyMap: newKeyMap(),
+	}
+	s.updateKeybinds()
+	return s
+}
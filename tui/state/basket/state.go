@@ -0,0 +1,200 @@
+package basket
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	_list "github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/tui/base"
+	"github.com/luevano/mangal/tui/basket"
+	"github.com/luevano/mangal/tui/model/list"
+	"github.com/luevano/mangal/tui/state/download"
+)
+
+var _ base.State = (*state)(nil)
+
+// state implements base.state.
+type state struct {
+	list *list.Model
+
+	keyMap keyMap
+}
+
+// Intermediate implements base.State.
+func (s *state) Intermediate() bool {
+	return false
+}
+
+// Backable implements base.State.
+func (s *state) Backable() bool {
+	return s.list.Unfiltered()
+}
+
+// KeyMap implements base.State.
+func (s *state) KeyMap() help.KeyMap {
+	return base.CombinedKeyMap(s.keyMap, s.list.KeyMap)
+}
+
+// Title implements base.State.
+func (s *state) Title() base.Title {
+	return base.Title{Text: "Basket"}
+}
+
+// Subtitle implements base.State.
+func (s *state) Subtitle() string {
+	return s.list.Subtitle()
+}
+
+// Status implements base.State.
+func (s *state) Status() string {
+	return s.list.Status()
+}
+
+// Resize implements base.State.
+func (s *state) Resize(size base.Size) tea.Cmd {
+	return s.list.Resize(size)
+}
+
+// Init implements base.State.
+func (s *state) Init(ctx context.Context) tea.Cmd {
+	return s.list.Init()
+}
+
+// Update implements base.State.
+func (s *state) Update(ctx context.Context, msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if s.list.Filtering() {
+			goto end
+		}
+
+		i, ok := s.list.SelectedItem().(*item)
+		if !ok {
+			goto end
+		}
+
+		switch {
+		case key.Matches(msg, s.keyMap.download):
+			return s.downloadCmd()
+		case key.Matches(msg, s.keyMap.remove):
+			basket.Remove(i.entry.Client, i.entry.Chapter)
+			return s.setItems(min(s.list.Index(), basket.Len()-1))
+		case key.Matches(msg, s.keyMap.clear):
+			basket.Clear()
+			return tea.Sequence(
+				s.setItems(0),
+				base.Notify("Basket cleared"),
+			)
+		case key.Matches(msg, s.keyMap.moveUp):
+			return s.moveCmd(-1)
+		case key.Matches(msg, s.keyMap.moveDown):
+			return s.moveCmd(1)
+		case key.Matches(msg, s.keyMap.sort):
+			s.sort()
+			return tea.Sequence(
+				s.setItems(0),
+				base.Notify("Sorted by manga, volume and number"),
+			)
+		}
+	case base.RestoredMsg:
+		// the downloaded chapters are removed from the basket
+		return s.setItems(0)
+	}
+end:
+	return s.list.Update(msg)
+}
+
+// View implements base.State.
+func (s *state) View() string {
+	return s.list.View()
+}
+
+// setItems updates the list items from the basket, selecting the index.
+func (s *state) setItems(index int) tea.Cmd {
+	entries := basket.Entries()
+	items := make([]_list.Item, len(entries))
+	for i, entry := range entries {
+		items[i] = &item{entry}
+	}
+	cmd := s.list.SetItems(items)
+	s.list.Select(max(index, 0))
+	s.updateKeybinds()
+	return cmd
+}
+
+// syncOrder sets the basket in the order of the list, as it can be reversed.
+func (s *state) syncOrder() {
+	items := s.list.Items()
+	entries := make([]basket.Entry, len(items))
+	for i, listItem := range items {
+		entries[i] = listItem.(*item).entry
+	}
+	basket.Set(entries)
+}
+
+func (s *state) moveCmd(delta int) tea.Cmd {
+	if s.list.FilterApplied() {
+		return base.Notify("Can't reorder while filtering")
+	}
+
+	s.syncOrder()
+	entries := basket.Entries()
+	from := s.list.Index()
+	to := from + delta
+	if to < 0 || to >= len(entries) {
+		return nil
+	}
+	entries[from], entries[to] = entries[to], entries[from]
+	basket.Set(entries)
+	return s.setItems(to)
+}
+
+// sort the basket by manga, volume and chapter number.
+func (s *state) sort() {
+	entries := basket.Entries()
+	slices.SortStableFunc(entries, func(a, b basket.Entry) int {
+		volA, volB := a.Chapter.Volume(), b.Chapter.Volume()
+		if c := cmp.Compare(volA.Manga().String(), volB.Manga().String()); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(volA.Info().Number, volB.Info().Number); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Chapter.Info().Number, b.Chapter.Info().Number)
+	})
+	basket.Set(entries)
+}
+
+func (s *state) downloadCmd() tea.Cmd {
+	if !s.list.FilterApplied() {
+		s.syncOrder()
+	}
+	entries := basket.Entries()
+	chapters := make([]download.Chapter, len(entries))
+	for i, entry := range entries {
+		chapters[i] = download.Chapter{Client: entry.Client, Chapter: entry.Chapter}
+	}
+
+	options := config.DownloadOptions()
+	// metadata was already searched when selecting the mangas
+	options.SearchMetadata = false
+	return func() tea.Msg {
+		return download.NewChapters(chapters, options)
+	}
+}
+
+// updateKeybinds enables/disables keybinds whose actions require an item.
+func (s *state) updateKeybinds() {
+	enable := len(s.list.Items()) != 0
+	s.keyMap.download.SetEnabled(enable)
+	s.keyMap.remove.SetEnabled(enable)
+	s.keyMap.clear.SetEnabled(enable)
+	s.keyMap.moveUp.SetEnabled(enable)
+	s.keyMap.moveDown.SetEnabled(enable)
+	s.keyMap.sort.SetEnabled(enable)
+}
@@ -4,21 +4,53 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"sort"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/luevano/libmangal"
 	"github.com/luevano/libmangal/mangadata"
+	lmmeta "github.com/luevano/libmangal/metadata"
+	"github.com/luevano/mangal/client/anilist"
 	"github.com/luevano/mangal/config"
 	"github.com/luevano/mangal/log"
 	"github.com/luevano/mangal/path"
 	"github.com/luevano/mangal/tui/base"
+	"github.com/luevano/mangal/tui/basket"
 	"github.com/luevano/mangal/tui/state/download"
+	"github.com/luevano/mangal/util/cache"
 	stringutil "github.com/luevano/mangal/util/string"
+	"github.com/samber/lo"
 	"github.com/skratchdot/open-golang/open"
-	"github.com/zyedidia/generic/set"
 )
 
+// anilistProgressCmd gets the Anilist progress of the manga if bound to
+// an Anilist manga (the metadata) and the user is authenticated.
+func (s *state) anilistProgressCmd(ctx context.Context) tea.Cmd {
+	meta := s.manga.Metadata()
+	if meta == nil {
+		*s.anilistProgress = 0
+		return nil
+	}
+	id, ok := lo.Find(append([]lmmeta.ID{meta.ID()}, meta.ExtraIDs()...), func(id lmmeta.ID) bool {
+		return id.Source == lmmeta.IDSourceAnilist && id.Value() > 0
+	})
+	if !ok {
+		*s.anilistProgress = 0
+		return nil
+	}
+
+	return func() tea.Msg {
+		// not found when not authenticated or not in the list
+		progress, _, err := anilist.Progress(ctx, id.Value())
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				log.Log("Couldn't get the Anilist progress of %q: %s", s.manga, err.Error())
+			}
+			return nil
+		}
+		return anilistProgressMsg(progress)
+	}
+}
+
 func showConfirmCmd(title, message string, state confirmState) tea.Cmd {
 	return func() tea.Msg {
 		return showConfirmMsg{
@@ -47,7 +79,7 @@ func (s *state) onConfirmCmd(ctx context.Context) tea.Cmd {
 	case cSDownloadHovered:
 		return s.downloadChapterCmd(ctx, i, options, false)
 	case cSDownloadSelected:
-		return s.downloadChaptersCmd(s.selected, options)
+		return s.downloadBasketCmd(options)
 	case cSDownloadForRead:
 		options.Format = config.Read.Format.Get()
 		// if shouldn't download on read, save to tmp dir with all dirs created
@@ -89,20 +121,17 @@ func (s *state) downloadCmd(item *item) tea.Cmd {
 		return s.blockedActionByCmd("download")
 	}
 
-	size := s.selected.Size()
-	// when no toggled chapters then just download the one hovered
-	if size <= 1 {
-		i := item
-		if size == 1 {
-			i = s.selected.Keys()[0]
-		}
+	// when no toggled chapters then just download the one hovered,
+	// else all the chapters in the basket (can be of other volumes or mangas)
+	size := basket.Len()
+	if size == 0 {
 		msg := "Download chapter " +
-			stringutil.FormatFloa32(i.chapter.Info().Number) +
-			` ("` + i.chapter.Info().Title + `")?`
+			stringutil.FormatFloa32(item.chapter.Info().Number) +
+			` ("` + item.chapter.Info().Title + `")?`
 		return showConfirmCmd("Download", msg, cSDownloadHovered)
 	}
 
-	msg := "Download " + stringutil.Quantify(size, "chapter", "chapters") + "?"
+	msg := "Download " + stringutil.Quantify(size, "chapter", "chapters") + " in the basket?"
 	return showConfirmCmd("Download", msg, cSDownloadSelected)
 }
 
@@ -135,20 +164,20 @@ func (s *state) downloadChapterCmd(ctx context.Context, item *item, options libm
 	)
 }
 
-func (s *state) downloadChaptersCmd(items set.Set[*item], options libmangal.DownloadOptions) tea.Cmd {
+// downloadBasketCmd downloads all the chapters in the basket, in its order,
+// which are removed from it once downloaded.
+func (s *state) downloadBasketCmd(options libmangal.DownloadOptions) tea.Cmd {
 	return func() tea.Msg {
 		s.actionRunningNow("download")
 		defer s.actionRunningNow("")
 
-		var chapters []mangadata.Chapter
-		for _, item := range items.Keys() {
-			chapters = append(chapters, item.chapter)
+		entries := basket.Entries()
+		chapters := make([]download.Chapter, len(entries))
+		for i, entry := range entries {
+			chapters[i] = download.Chapter{Client: entry.Client, Chapter: entry.Chapter}
 		}
-		sort.SliceStable(chapters, func(i, j int) bool {
-			return chapters[i].Info().Number < chapters[j].Info().Number
-		})
 
-		return download.New(s.client, chapters, options)
+		return download.NewChapters(chapters, options)
 	}
 }
 
@@ -158,14 +187,15 @@ func (s *state) readCmd(ctx context.Context, item *item) tea.Cmd {
 	}
 
 	// when no toggled chapters then just download the one selected
-	if s.selected.Size() > 1 {
+	selected := s.selectedItems()
+	if len(selected) > 1 {
 		return base.Notify("Can't open for reading more than 1 chapter")
 	}
 
 	// use the toggled item, else the hovered one
 	i := item
-	if s.selected.Size() == 1 {
-		i = s.selected.Keys()[0]
+	if len(selected) == 1 {
+		i = selected[0]
 	}
 
 	if i.readAvailablePath != "" {
@@ -193,6 +223,13 @@ func (s *state) readChapterCmd(ctx context.Context, path string, item *item, opt
 				return err
 			}
 
+			if options.SaveHistory {
+				s.readChapters.Add(chapter.Info().Number)
+				if err := cache.SetReadHistory(s.mangaKey, *s.readChapters); err != nil {
+					return err
+				}
+				s.pruneNewChapters()
+			}
 			return nil
 		},
 		base.Loaded,
@@ -0,0 +1,322 @@
+package chapters
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/luevano/libmangal/metadata"
+)
+
+type chapterStatus uint8
+
+const (
+	statusAny chapterStatus = iota
+	statusDownloaded
+	statusNotDownloaded
+	statusRead
+	statusUnread
+	statusNew
+)
+
+var chapterStatuses = map[string]chapterStatus{
+	"downloaded":     statusDownloaded,
+	"not-downloaded": statusNotDownloaded,
+	"read":           statusRead,
+	"unread":         statusUnread,
+	"new":            statusNew,
+}
+
+// filter restricts the chapters shown, parsed from an expression of
+// space separated key:value pairs (quote the values with spaces), as in:
+//
+//	group:"some scans" num:10..20 date:2023-01..2023-06 status:unread prefer:other
+type filter struct {
+	expr string
+
+	// lowercased, matches any of them
+	groups []string
+
+	numFrom, numTo *float32
+
+	// zero value means unbounded
+	dateFrom, dateTo metadata.Date
+
+	status chapterStatus
+
+	// lowercased, scanlation group kept on duplicate chapter numbers
+	prefer string
+}
+
+// parseFilter parses the filter expression, an empty one shows all chapters.
+func parseFilter(expr string) (filter, error) {
+	f := filter{expr: expr}
+	for _, token := range splitFilter(expr) {
+		k, v, ok := strings.Cut(token, ":")
+		if !ok || v == "" {
+			return filter{}, fmt.Errorf("invalid filter %q, needs to be key:value", token)
+		}
+
+		var err error
+		switch strings.ToLower(k) {
+		case "group":
+			f.groups = append(f.groups, strings.ToLower(v))
+		case "num":
+			f.numFrom, f.numTo, err = parseNumberRange(v)
+		case "date":
+			f.dateFrom, f.dateTo, err = parseDateRange(v)
+		case "status":
+			status, ok := chapterStatuses[strings.ToLower(v)]
+			if !ok {
+				err = fmt.Errorf("unknown status %q, available: downloaded, not-downloaded, read, unread, new", v)
+			}
+			f.status = status
+		case "prefer":
+			f.prefer = strings.ToLower(v)
+		case "lang", "language":
+			err = errors.New(`chapters don't have language information, set the "providers.filter.language" config instead`)
+		default:
+			err = fmt.Errorf("unknown filter %q, available: group, num, date, status, prefer", k)
+		}
+		if err != nil {
+			return filter{}, err
+		}
+	}
+	return f, nil
+}
+
+// splitFilter splits the expression by spaces, except for the quoted values.
+func splitFilter(expr string) []string {
+	var tokens []string
+	var token strings.Builder
+	quoted := false
+	for _, r := range expr {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ' ' && !quoted:
+			if token.Len() > 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+		default:
+			token.WriteRune(r)
+		}
+	}
+	if token.Len() > 0 {
+		tokens = append(tokens, token.String())
+	}
+	return tokens
+}
+
+// parseNumberRange parses "from..to", "from..", "..to" or a single number.
+func parseNumberRange(s string) (from, to *float32, err error) {
+	parse := func(n string) (*float32, error) {
+		if n == "" {
+			return nil, nil
+		}
+		f, err := strconv.ParseFloat(n, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chapter number %q", n)
+		}
+		f32 := float32(f)
+		return &f32, nil
+	}
+
+	a, b, isRange := strings.Cut(s, "..")
+	if !isRange {
+		b = a
+	}
+	if from, err = parse(a); err != nil {
+		return nil, nil, err
+	}
+	if to, err = parse(b); err != nil {
+		return nil, nil, err
+	}
+	return from, to, nil
+}
+
+// parseDateRange parses "from..to", "from..", "..to" or a single date, where the
+// dates are YYYY, YYYY-MM or YYYY-MM-DD; the range includes all of the periods.
+func parseDateRange(s string) (from, to metadata.Date, err error) {
+	a, b, isRange := strings.Cut(s, "..")
+	if !isRange {
+		b = a
+	}
+	if a != "" {
+		if from, _, err = parseDate(a); err != nil {
+			return from, to, err
+		}
+	}
+	if b != "" {
+		if _, to, err = parseDate(b); err != nil {
+			return from, to, err
+		}
+	}
+	return from, to, nil
+}
+
+// parseDate returns the first and last day of the date period.
+func parseDate(s string) (first, last metadata.Date, err error) {
+	parts := strings.Split(s, "-")
+	if len(parts) > 3 {
+		return first, last, fmt.Errorf("invalid date %q, needs to be YYYY, YYYY-MM or YYYY-MM-DD", s)
+	}
+	values := []int{0, 1, 1}
+	for i, part := range parts {
+		if values[i], err = strconv.Atoi(part); err != nil {
+			return first, last, fmt.Errorf("invalid date %q, needs to be YYYY, YYYY-MM or YYYY-MM-DD", s)
+		}
+	}
+	first = metadata.Date{Year: values[0], Month: values[1], Day: values[2]}
+	last = first
+	switch len(parts) {
+	case 1:
+		last.Month, last.Day = 12, 31
+	case 2:
+		last.Day = 31
+	}
+	return first, last, nil
+}
+
+// compareDates compares the dates chronologically.
+func compareDates(a, b metadata.Date) int {
+	if c := cmp.Compare(a.Year, b.Year); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Month, b.Month); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Day, b.Day)
+}
+
+// empty returns true if the filter shows all chapters.
+func (f filter) empty() bool {
+	return f.expr == ""
+}
+
+// matches returns true if the item passes the filter (except the prefer rule).
+func (f filter) matches(i *item) bool {
+	info := i.chapter.Info()
+
+	if len(f.groups) > 0 {
+		group := strings.ToLower(info.ScanlationGroup)
+		if !slices.ContainsFunc(f.groups, func(g string) bool {
+			return strings.Contains(group, g)
+		}) {
+			return false
+		}
+	}
+
+	if f.numFrom != nil && info.Number < *f.numFrom {
+		return false
+	}
+	if f.numTo != nil && info.Number > *f.numTo {
+		return false
+	}
+
+	if f.dateFrom != (metadata.Date{}) || f.dateTo != (metadata.Date{}) {
+		if info.Date == (metadata.Date{}) {
+			return false
+		}
+		if f.dateFrom != (metadata.Date{}) && compareDates(info.Date, f.dateFrom) < 0 {
+			return false
+		}
+		if f.dateTo != (metadata.Date{}) && compareDates(info.Date, f.dateTo) > 0 {
+			return false
+		}
+	}
+
+	switch f.status {
+	case statusDownloaded:
+		return i.downloaded()
+	case statusNotDownloaded:
+		return !i.downloaded()
+	case statusRead:
+		return i.read()
+	case statusUnread:
+		return !i.read()
+	case statusNew:
+		return i.isNew()
+	}
+	return true
+}
+
+// preferGroup hides the chapters that share the number with a chapter of
+// the preferred scanlation group (lowercased), which are kept.
+func preferGroup(items []*item, group string) []*item {
+	if group == "" {
+		return items
+	}
+
+	isPreferred := func(i *item) bool {
+		return strings.Contains(strings.ToLower(i.chapter.Info().ScanlationGroup), group)
+	}
+	preferred := make(map[float32]bool)
+	for _, i := range items {
+		if isPreferred(i) {
+			preferred[i.chapter.Info().Number] = true
+		}
+	}
+	return slices.DeleteFunc(items, func(i *item) bool {
+		return preferred[i.chapter.Info().Number] && !isPreferred(i)
+	})
+}
+
+type sortMode uint8
+
+const (
+	sortNone sortMode = iota
+	sortNumberAsc
+	sortNumberDesc
+	sortDateAsc
+	sortDateDesc
+)
+
+// String returns the name of the sort mode.
+func (m sortMode) String() string {
+	switch m {
+	case sortNumberAsc:
+		return "number ascending"
+	case sortNumberDesc:
+		return "number descending"
+	case sortDateAsc:
+		return "date ascending"
+	case sortDateDesc:
+		return "date descending"
+	default:
+		return "provider order"
+	}
+}
+
+// next returns the following sort mode, cycling back to the provider order.
+func (m sortMode) next() sortMode {
+	return (m + 1) % (sortDateDesc + 1)
+}
+
+// sort the items in place, keeps the provider order on ties.
+func (m sortMode) sort(items []*item) {
+	var compare func(a, b *item) int
+	switch m {
+	case sortNumberAsc, sortNumberDesc:
+		compare = func(a, b *item) int {
+			return cmp.Compare(a.chapter.Info().Number, b.chapter.Info().Number)
+		}
+	case sortDateAsc, sortDateDesc:
+		compare = func(a, b *item) int {
+			return compareDates(a.chapter.Info().Date, b.chapter.Info().Date)
+		}
+	default:
+		return
+	}
+	if m == sortNumberDesc || m == sortDateDesc {
+		asc := compare
+		compare = func(a, b *item) int {
+			return asc(b, a)
+		}
+	}
+	slices.SortStableFunc(items, compare)
+}
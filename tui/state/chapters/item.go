@@ -11,7 +11,9 @@ import (
 	"github.com/luevano/mangal/path"
 	"github.com/luevano/mangal/theme/icon"
 	"github.com/luevano/mangal/theme/style"
+	"github.com/luevano/mangal/tui/basket"
 	"github.com/luevano/mangal/util/afs"
+	"github.com/luevano/mangal/util/cache"
 	"github.com/zyedidia/generic/set"
 )
 
@@ -33,8 +35,6 @@ type item struct {
 	renderedChapterNumber     string
 	renderedDownloadedFormats string
 
-	// selected means that the item is toggled on
-	selected bool
 	// path to the downloaded chapter in preferred read format,
 	// prefers download directory path over temp path if existent,
 	// if the read format is not available anywhere it is empty
@@ -44,6 +44,12 @@ type item struct {
 	fullTempPath     string
 	fullDownloadPath string
 
+	// read and new chapters of the manga and its
+	// Anilist chapter progress, shared by all items
+	readChapters    *cache.ChapterNumbers
+	newChapters     *cache.ChapterNumbers
+	anilistProgress *int
+
 	showVolumeNumber  *bool
 	showChapterNumber *bool
 	showGroup         *bool
@@ -77,11 +83,20 @@ func (i *item) Title() string {
 
 	title.WriteString(i.FilterValue())
 
-	if i.selected {
+	if i.selected() {
 		title.WriteString(i.renderedSep)
 		title.WriteString(icon.Mark.Colored())
 	}
 
+	switch {
+	case i.read():
+		title.WriteString(i.renderedSep)
+		title.WriteString(icon.Read.Colored())
+	case i.isNew():
+		title.WriteString(i.renderedSep)
+		title.WriteString(icon.New.Colored())
+	}
+
 	if i.readAvailablePath != "" {
 		title.WriteString(i.renderedSep)
 		title.WriteString(icon.Available.Colored())
@@ -119,8 +134,32 @@ func (i *item) Description() string {
 	return description.String()
 }
 
+// selected means that the item is toggled on, in the basket
+func (i *item) selected() bool {
+	return basket.Has(i.client, i.chapter)
+}
+
 func (i *item) toggle() {
-	i.selected = !i.selected
+	basket.Toggle(i.client, i.chapter)
+}
+
+// downloaded means that the chapter is in the download directory, in any format
+func (i *item) downloaded() bool {
+	return i.downloadedFormats.Size() > 0
+}
+
+// read means that the chapter is in the local read history
+// or within the Anilist progress
+func (i *item) read() bool {
+	number := i.chapter.Info().Number
+	progress := *i.anilistProgress
+	return i.readChapters.Has(number) || (progress > 0 && number <= float32(progress))
+}
+
+// isNew means that the chapter was notified as new for the followed
+// series (notify watch) and it hasn't been read nor downloaded yet
+func (i *item) isNew() bool {
+	return i.newChapters.Has(i.chapter.Info().Number) && !i.read() && !i.downloaded()
 }
 
 // path computes the full filepath to the (possibly) downloaded chapter
@@ -181,9 +220,11 @@ func (i *item) updateDownloadedFormats() {
 }
 
 // renderDownloadedFormats will create the string displayed
-// next to the chapter name that shows the downloaded formats
+// next to the chapter name that shows the downloaded formats,
+// the ones other than the download format are dimmed
 func (i *item) renderDownloadedFormats() {
 	i.renderedDownloadedFormats = ""
+	downloadFormat := config.Download.Format.Get()
 
 	if i.downloadedFormats.Size() > 0 {
 		var formats strings.Builder
@@ -197,7 +238,11 @@ func (i *item) renderDownloadedFormats() {
 			}
 
 			formats.WriteString(" ")
-			formats.WriteString(i.styles.format.Render(format.String()))
+			if format == downloadFormat {
+				formats.WriteString(i.styles.format.Render(format.String()))
+			} else {
+				formats.WriteString(i.styles.otherFormat.Render(format.String()))
+			}
 		}
 		i.renderedDownloadedFormats = formats.String()
 	}
@@ -3,27 +3,38 @@ package chapters
 import (
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/luevano/mangal/tui/model/list"
 	"github.com/luevano/mangal/tui/util"
 )
 
 var _ help.KeyMap = (*keyMap)(nil)
 
+// keyMapName is the name of the keymap in the config (tui.keys.chapters).
+const keyMapName = "chapters"
+
+func init() {
+	util.RegisterKeyMap(keyMapName, func() { newKeyMap() }, util.GlobalKeyMap, list.KeyMapName)
+}
+
 func newKeyMap() keyMap {
+	keys := util.NewKeys(keyMapName)
 	return keyMap{
-		toggle:              util.Bind("toggle", " "),
-		read:                util.Bind("read", "r"),
-		download:            util.Bind("download", "d"),
-		info:                util.Bind("info", "i"),
-		anilist:             util.Bind("anilist", "A"),
-		metadata:            util.Bind("metadata", "m"),
-		changeFormat:        util.Bind("change format", "f"),
-		openURL:             util.Bind("open url", "o"),
-		selectAll:           util.Bind("select all", "a"),
-		unselectAll:         util.Bind("unselect all", "backspace"),
-		toggleVolumeNumber:  util.Bind("toggle vol num", "v"),
-		toggleChapterNumber: util.Bind("toggle number", "c"),
-		toggleGroup:         util.Bind("toggle group", "ctrl+g"),
-		toggleDate:          util.Bind("toggle date", "ctrl+d"),
+		toggle:              keys.Bind("toggle", "toggle", " "),
+		read:                keys.Bind("read", "read", "r"),
+		download:            keys.Bind("download", "download", "d"),
+		info:                keys.Bind("info", "info", "i"),
+		anilist:             keys.Bind("anilist", "anilist", "A"),
+		metadata:            keys.Bind("metadata", "metadata", "m"),
+		changeFormat:        keys.Bind("change_format", "change format", "f"),
+		openURL:             keys.Bind("open_url", "open url", "o"),
+		selectAll:           keys.Bind("select_all", "select all", "a"),
+		unselectAll:         keys.Bind("unselect_all", "unselect all", "backspace"),
+		toggleVolumeNumber:  keys.Bind("toggle_volume_number", "toggle vol num", "v"),
+		toggleChapterNumber: keys.Bind("toggle_chapter_number", "toggle number", "c"),
+		toggleGroup:         keys.Bind("toggle_group", "toggle group", "ctrl+g"),
+		toggleDate:          keys.Bind("toggle_date", "toggle date", "ctrl+d"),
+		filter:              keys.Bind("filter", "filter", "F"),
+		sort:                keys.Bind("sort", "sort", "S"),
 	}
 }
 
@@ -42,7 +53,9 @@ type keyMap struct {
 	toggleVolumeNumber,
 	toggleChapterNumber,
 	toggleGroup,
-	toggleDate key.Binding
+	toggleDate,
+	filter,
+	sort key.Binding
 }
 
 // ShortHelp implements help.keyMap.
@@ -60,7 +73,7 @@ func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		k.ShortHelp(),
 		{k.anilist, k.metadata, k.changeFormat, k.openURL},
-		{k.selectAll, k.unselectAll},
+		{k.selectAll, k.unselectAll, k.filter, k.sort},
 		{k.toggleVolumeNumber, k.toggleChapterNumber, k.toggleGroup, k.toggleDate},
 	}
 }
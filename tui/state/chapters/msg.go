@@ -1,5 +1,8 @@
 package chapters
 
+// anilistProgressMsg is the chapter progress of the manga in the Anilist list.
+type anilistProgressMsg int
+
 type showConfirmMsg struct {
 	title,
 	message string
@@ -7,13 +7,15 @@ import (
 	"github.com/luevano/libmangal"
 	"github.com/luevano/libmangal/mangadata"
 	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/log"
 	"github.com/luevano/mangal/theme/color"
 	"github.com/luevano/mangal/theme/icon"
 	"github.com/luevano/mangal/tui/model/confirm"
 	"github.com/luevano/mangal/tui/model/format"
 	"github.com/luevano/mangal/tui/model/list"
 	"github.com/luevano/mangal/tui/model/metadata"
-	"github.com/zyedidia/generic/set"
+	"github.com/luevano/mangal/tui/model/search"
+	"github.com/luevano/mangal/util/cache"
 )
 
 // volume can be nil, which represents a list of chapters for a manga with only one chapter
@@ -23,6 +25,17 @@ func New(client *libmangal.Client, manga mangadata.Manga, volume mangadata.Volum
 	showGroup := config.TUI.Chapter.ShowGroup.Get()
 	showDate := config.TUI.Chapter.ShowDate.Get()
 
+	mangaKey := cache.MangaKey(client.Info().ID, manga.Info().ID)
+	readChapters := cache.ChapterNumbers{}
+	if _, err := cache.GetReadHistory(mangaKey, &readChapters); err != nil {
+		log.Log("Couldn't get the read history of %q: %s", manga, err.Error())
+	}
+	newChapters := cache.ChapterNumbers{}
+	anilistProgress := 0
+	if _, err := cache.GetNewChapters(mangaKey, &newChapters); err != nil {
+		log.Log("Couldn't get the new chapters of %q: %s", manga, err.Error())
+	}
+
 	_styles := defaultStyles()
 	renderedSep := _styles.sep.Render(icon.Separator.Raw())
 	listWrapper := list.New(
@@ -39,6 +52,9 @@ func New(client *libmangal.Client, manga mangadata.Manga, volume mangadata.Volum
 				renderedSep:           renderedSep,
 				renderedVolumeNumber:  volNum,
 				renderedChapterNumber: chapNum,
+				readChapters:          &readChapters,
+				newChapters:           &newChapters,
+				anilistProgress:       &anilistProgress,
 				showVolumeNumber:      &showVolumeNumber,
 				showChapterNumber:     &showChapterNumber,
 				showGroup:             &showGroup,
@@ -53,8 +69,17 @@ func New(client *libmangal.Client, manga mangadata.Manga, volume mangadata.Volum
 		},
 	)
 
+	items := make([]*item, len(listWrapper.Items()))
+	for i, listItem := range listWrapper.Items() {
+		items[i] = listItem.(*item)
+	}
+
+	filterInput := search.New("Filter, e.g. group:name num:10..20 date:2023 status:unread prefer:name", "", 80, 0)
+	filterInput.AllowEmpty = true
+
 	s := &state{
 		list:              listWrapper,
+		filterInput:       filterInput,
 		meta:              metadata.New(manga.Metadata()),
 		confirm:           confirm.New(30, color.Success),
 		formats:           format.New(color.Viewport),
@@ -62,7 +87,11 @@ func New(client *libmangal.Client, manga mangadata.Manga, volume mangadata.Volum
 		volume:            volume,
 		manga:             manga,
 		client:            client,
-		selected:          set.NewMapset[*item](),
+		items:             items,
+		readChapters:      &readChapters,
+		newChapters:       &newChapters,
+		anilistProgress:   &anilistProgress,
+		mangaKey:          mangaKey,
 		renderedSep:       renderedSep,
 		confirmState:      cSDownloadNone,
 		showVolumeNumber:  &showVolumeNumber,
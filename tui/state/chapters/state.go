@@ -13,13 +13,15 @@ import (
 	"github.com/luevano/libmangal/mangadata"
 	lmmeta "github.com/luevano/libmangal/metadata"
 	"github.com/luevano/mangal/tui/base"
+	"github.com/luevano/mangal/tui/basket"
 	"github.com/luevano/mangal/tui/model/confirm"
 	"github.com/luevano/mangal/tui/model/format"
 	"github.com/luevano/mangal/tui/model/list"
 	"github.com/luevano/mangal/tui/model/metadata"
+	"github.com/luevano/mangal/tui/model/search"
 	"github.com/luevano/mangal/tui/state/anilist"
 	"github.com/luevano/mangal/tui/util"
-	"github.com/zyedidia/generic/set"
+	"github.com/luevano/mangal/util/cache"
 )
 
 var _ base.State = (*state)(nil)
@@ -40,12 +42,24 @@ type state struct {
 	confirm *confirm.Model
 	formats *format.Model
 
+	filterInput *search.Model
+
 	chapters []mangadata.Chapter
 	volume   mangadata.Volume // can be nil
 	manga    mangadata.Manga
 	client   *libmangal.Client
 
-	selected set.Set[*item]
+	// all the items, the list only has the ones passing the filter
+	items    []*item
+	filter   filter
+	sortMode sortMode
+
+	readChapters *cache.ChapterNumbers
+	newChapters  *cache.ChapterNumbers
+	mangaKey     string
+	// anilistProgress is the chapter progress of the manga in the
+	// Anilist list of the authenticated user, 0 if unknown
+	anilistProgress *int
 
 	previousFrame,
 	renderedSep,
@@ -81,7 +95,7 @@ func (s *state) Intermediate() bool {
 
 // Backable implements base.State.
 func (s *state) Backable() bool {
-	return s.list.Unfiltered() && !s.inFormats && !s.inConfirm
+	return s.list.Unfiltered() && !s.inFormats && !s.inConfirm && !s.filterInput.Searching()
 }
 
 // KeyMap implements base.State.
@@ -103,12 +117,20 @@ func (s *state) Subtitle() string {
 	subtitle.Grow(100)
 
 	subtitle.WriteString(s.list.Subtitle())
-	if s.selected.Size() > 0 {
-		selected := s.renderedSep +
-			s.styles.subtitle.Render(fmt.Sprintf("%d selected", s.selected.Size()))
-		subtitle.WriteString(selected)
+	if size := basket.Len(); size > 0 {
+		selected := fmt.Sprintf("%d selected", len(s.selectedItems()))
+		if other := size - len(s.selectedItems()); other > 0 {
+			selected += fmt.Sprintf(" (%d more in basket)", other)
+		}
+		subtitle.WriteString(s.renderedSep + s.styles.subtitle.Render(selected))
 	}
 	subtitle.WriteString(s.renderedSubtitleFormats)
+	if !s.filter.empty() {
+		subtitle.WriteString(s.renderedSep + s.styles.subtitle.Render("filter "+s.filter.expr))
+	}
+	if s.sortMode != sortNone {
+		subtitle.WriteString(s.renderedSep + s.styles.subtitle.Render("sorted by "+s.sortMode.String()))
+	}
 
 	return subtitle.String()
 }
@@ -121,16 +143,22 @@ func (s *state) Status() string {
 // Resize implements base.State.
 func (s *state) Resize(size base.Size) tea.Cmd {
 	s.size = size
+	s.filterInput.Resize(size)
 	return s.list.Resize(size)
 }
 
 // Init implements base.State.
 func (s *state) Init(ctx context.Context) tea.Cmd {
 	s.updateRenderedSubtitleFormats()
-	return tea.Sequence(
-		s.list.Init(),
-		s.confirm.Init(),
-		s.formats.Init(),
+	s.pruneNewChapters()
+	return tea.Batch(
+		tea.Sequence(
+			s.list.Init(),
+			s.applyFilterCmd(),
+			s.confirm.Init(),
+			s.formats.Init(),
+		),
+		s.anilistProgressCmd(ctx),
 	)
 }
 
@@ -139,7 +167,7 @@ func (s *state) Update(ctx context.Context, msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		// skip keybind handling, let the models handle these events
-		if s.list.Filtering() || s.inFormats || s.inConfirm {
+		if s.list.Filtering() || s.inFormats || s.inConfirm || s.filterInput.Searching() {
 			goto end
 		}
 
@@ -148,11 +176,6 @@ func (s *state) Update(ctx context.Context, msg tea.Msg) tea.Cmd {
 		switch {
 		case key.Matches(msg, s.keyMap.toggle):
 			i.toggle()
-			if i.selected {
-				s.selected.Put(i)
-			} else {
-				s.selected.Remove(i)
-			}
 			return nil
 		case key.Matches(msg, s.keyMap.read):
 			return s.readCmd(ctx, i)
@@ -184,16 +207,14 @@ func (s *state) Update(ctx context.Context, msg tea.Msg) tea.Cmd {
 					continue
 				}
 
-				if !it.selected {
+				if !it.selected() {
 					it.toggle()
-					s.selected.Put(it)
 				}
 			}
 			return nil
 		case key.Matches(msg, s.keyMap.unselectAll):
-			for _, item := range s.selected.Keys() {
+			for _, item := range s.selectedItems() {
 				item.toggle()
-				s.selected.Remove(item)
 			}
 			return nil
 		case key.Matches(msg, s.keyMap.toggleVolumeNumber):
@@ -206,7 +227,28 @@ func (s *state) Update(ctx context.Context, msg tea.Msg) tea.Cmd {
 		case key.Matches(msg, s.keyMap.toggleDate):
 			*s.showDate = !(*s.showDate)
 			s.updateListDelegate()
+		case key.Matches(msg, s.keyMap.filter):
+			s.list.ResetFilter()
+			return s.filterInput.Focus()
+		case key.Matches(msg, s.keyMap.sort):
+			s.sortMode = s.sortMode.next()
+			return tea.Sequence(
+				s.applyFilterCmd(),
+				base.Notify("Sorted by "+s.sortMode.String()),
+			)
+		}
+	case search.SearchMsg:
+		f, err := parseFilter(string(msg))
+		if err != nil {
+			return tea.Sequence(
+				s.filterInput.Focus(),
+				func() tea.Msg {
+					return err
+				},
+			)
 		}
+		s.filter = f
+		return s.applyFilterCmd()
 	case showConfirmMsg:
 		s.previousFrame = s.View()
 		s.inConfirm = true
@@ -227,9 +269,22 @@ func (s *state) Update(ctx context.Context, msg tea.Msg) tea.Cmd {
 		// usually the downloaded chapters change or the metadata when restoring the chapter list
 		s.updateAllItems()
 		s.updateRenderedSubtitleFormats()
+		// the downloaded and read status can change
+		s.pruneNewChapters()
+		return tea.Batch(
+			s.applyFilterCmd(),
+			// the manga could have been bound to another Anilist manga
+			s.anilistProgressCmd(ctx),
+		)
+	case anilistProgressMsg:
+		*s.anilistProgress = int(msg)
+		s.pruneNewChapters()
+		return s.applyFilterCmd()
 	}
 end:
 	switch {
+	case s.filterInput.Searching():
+		return s.filterInput.Update(msg)
 	case s.inConfirm:
 		return s.confirm.Update(msg)
 	case s.inFormats:
@@ -250,6 +305,8 @@ func (s *state) View() string {
 		fV := s.styles.formatView.Render(s.formats.View())
 		w, h := lipgloss.Size(fV)
 		return util.PlaceOverlay((s.size.Width-w)/2, (s.size.Height-h)/2, fV, s.previousFrame)
+	case s.filterInput.Searching():
+		return util.PlaceOverlay(0, 0, s.styles.filterView.Render(s.filterInput.View()), s.list.View())
 	default:
 		return s.list.View()
 	}
@@ -10,20 +10,26 @@ type styles struct {
 	sep,
 	subtitle,
 	format,
+	otherFormat,
 	confirmView,
-	formatView lipgloss.Style
+	formatView,
+	filterView lipgloss.Style
 }
 
 func defaultStyles() styles {
 	return styles{
-		sep:      style.Bold.Warning.Padding(0, 1),
-		subtitle: style.Normal.Secondary, // matches base without padding
-		format:   style.Bold.Warning,
+		sep:         style.Bold.Warning.Padding(0, 1),
+		subtitle:    style.Normal.Secondary, // matches base without padding
+		format:      style.Bold.Warning,
+		otherFormat: style.Normal.Secondary,
 		confirmView: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
 			BorderForeground(color.Success),
 		formatView: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
 			BorderForeground(color.Viewport),
+		filterView: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(color.Accent),
 	}
 }
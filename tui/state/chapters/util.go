@@ -1,13 +1,31 @@
 package chapters
 
 import (
+	"slices"
+	"strings"
+
+	_list "github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/log"
+	"github.com/luevano/mangal/util/cache"
 )
 
 func (s *state) actionRunningNow(action string) {
 	s.actionRunning = action
 }
 
+// selectedItems returns the items of the list in the basket.
+func (s *state) selectedItems() []*item {
+	var selected []*item
+	for _, listItem := range s.list.Items() {
+		if i, ok := listItem.(*item); ok && i.selected() {
+			selected = append(selected, i)
+		}
+	}
+	return selected
+}
+
 func (s *state) updateItem(item *item) {
 	item.updatePaths()
 	item.updateDownloadedFormats()
@@ -15,12 +33,51 @@ func (s *state) updateItem(item *item) {
 }
 
 func (s *state) updateAllItems() {
-	for _, i := range s.list.Items() {
-		i := i.(*item)
+	for _, i := range s.items {
 		s.updateItem(i)
 	}
 }
 
+// pruneNewChapters removes the read or downloaded chapters from the new ones.
+func (s *state) pruneNewChapters() {
+	removed := s.newChapters.Remove(func(number float32) bool {
+		return s.readChapters.Has(number) || slices.ContainsFunc(s.items, func(i *item) bool {
+			return i.chapter.Info().Number == number && (i.read() || i.downloaded())
+		})
+	})
+	if !removed {
+		return
+	}
+	if err := cache.SetNewChapters(s.mangaKey, *s.newChapters); err != nil {
+		log.Log("Couldn't update the new chapters of %q: %s", s.manga, err.Error())
+	}
+}
+
+// applyFilterCmd sets the list items to the ones passing the filter, sorted.
+func (s *state) applyFilterCmd() tea.Cmd {
+	var items []*item
+	for _, i := range s.items {
+		if s.filter.matches(i) {
+			items = append(items, i)
+		}
+	}
+
+	prefer := s.filter.prefer
+	if prefer == "" {
+		prefer = strings.ToLower(config.TUI.Chapter.PreferGroup.Get())
+	}
+	items = preferGroup(items, prefer)
+	s.sortMode.sort(items)
+
+	listItems := make([]_list.Item, len(items))
+	for i, item := range items {
+		listItems[i] = item
+	}
+	cmd := s.list.SetItems(listItems)
+	s.updateKeybinds()
+	return cmd
+}
+
 func (s *state) updateListDelegate() {
 	if *s.showDate || *s.showGroup {
 		s.list.SetItemHeight(3)
@@ -41,6 +98,10 @@ func (s *state) updateRenderedSubtitleFormats() {
 // (either to perform an action, or change something visually).
 func (s *state) updateKeybinds() {
 	enable := len(s.list.Items()) != 0
+	// filter the chapters only when there are any
+	s.keyMap.filter.SetEnabled(len(s.items) != 0)
+	s.keyMap.sort.SetEnabled(len(s.items) != 0)
+
 	// require item
 	s.keyMap.toggle.SetEnabled(enable)
 	s.keyMap.read.SetEnabled(enable)
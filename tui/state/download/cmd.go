@@ -10,16 +10,45 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/luevano/libmangal/metadata"
+	"github.com/luevano/mangal/metrics"
+	"github.com/luevano/mangal/script/hook"
+	"github.com/luevano/mangal/tui/basket"
+	"github.com/luevano/mangal/util/chapter"
 	"github.com/skratchdot/open-golang/open"
 )
 
 func (s *state) startDownloadCmd() tea.Msg {
+	if err := s.loadHooks(); err != nil {
+		s.closeHooks()
+		return err
+	}
+
 	s.downloading = dSDownloading
 	s.currentIdx = 0
 	s.toDownload = s.chapters.ToDownload()
 	return nextChapterMsg{}
 }
 
+// beforeDownloadCmd runs the before_download hooks for the current chapter,
+// which is skipped if vetoed.
+func (s *state) beforeDownloadCmd(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		ch := s.toDownload[s.currentIdx]
+		hooks := s.hooks[s.origin[ch]]
+		download, err := hooks.BeforeDownload(ctx, ch)
+		if err != nil {
+			ch.Err = err
+			hooks.OnError(ctx, ch)
+			return s.nextChapter(ctx)
+		}
+		if !download {
+			hook.Skip(ch)
+			return s.nextChapter(ctx)
+		}
+		return s.downloadChapterCmd(ctx)()
+	}
+}
+
 func (s *state) downloadChapterCmd(ctx context.Context) tea.Cmd {
 	return func() tea.Msg {
 		var (
@@ -28,7 +57,7 @@ func (s *state) downloadChapterCmd(ctx context.Context) tea.Cmd {
 			err      error
 		)
 
-		downChap, err = s.client.DownloadChapter(ctx, ch.Chapter, s.options)
+		downChap, err = s.clients[s.origin[ch]].DownloadChapter(ctx, ch.Chapter, s.options)
 		if err != nil {
 			errMsg := err.Error()
 			// TODO: handle other responses here too if possible
@@ -37,12 +66,15 @@ func (s *state) downloadChapterCmd(ctx context.Context) tea.Cmd {
 			if strings.Contains(errMsg, "429") && strings.Contains(errMsg, "Retry-After") {
 				s.retryCount++
 				if s.retryCount > s.maxRetries {
+					// the batch is aborted
+					s.closeHooks()
 					return fmt.Errorf("exceeded max retries (%d) while downloading chapters", s.maxRetries)
 				}
 
 				raTemp := strings.Split(errMsg, ":")
 				raParsed, err := strconv.Atoi(strings.TrimSpace(raTemp[len(raTemp)-1]))
 				if err != nil {
+					s.closeHooks()
 					return errors.New("error while parsing Retry-Count from error mesage: " + err.Error())
 				}
 
@@ -52,18 +84,92 @@ func (s *state) downloadChapterCmd(ctx context.Context) tea.Cmd {
 		}
 		ch.Down = downChap
 		ch.Err = err
+		if ch.Failed() && s.fallback.Enabled() {
+			return fallbackChapterMsg{}
+		}
+		return s.afterDownload(ctx, ch)
+	}
+}
 
-		if s.currentIdx+1 >= len(s.toDownload) {
-			return downloadCompletedMsg{}
+// fallbackChapterCmd downloads the current (failed) chapter with the fallback providers.
+func (s *state) fallbackChapterCmd(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		ch := s.toDownload[s.currentIdx]
+		s.fallback.Download(ctx, ch, s.options)
+		return s.afterDownload(ctx, ch)
+	}
+}
+
+// afterDownload runs the chapter hooks and advances to the next chapter.
+func (s *state) afterDownload(ctx context.Context, ch *chapter.Chapter) tea.Msg {
+	hooks := s.hooks[s.origin[ch]]
+	if !ch.Failed() {
+		ch.Err = hooks.AfterChapterDownloaded(ctx, ch)
+	}
+	if ch.Failed() {
+		hooks.OnError(ctx, ch)
+	}
+
+	return s.nextChapter(ctx)
+}
+
+// nextChapter counts the finished chapter, removing it from the basket if
+// downloaded, and advances to the next one to download, running the
+// after_download_batch hooks if it was the last one.
+func (s *state) nextChapter(ctx context.Context) tea.Msg {
+	ch := s.toDownload[s.currentIdx]
+	metrics.Chapters(chapter.Chapters{ch})
+	// the failed chapters are kept in the basket, to download them again
+	if ch.Succeed() {
+		basket.Remove(s.clients[s.origin[ch]], ch.Chapter)
+	}
+	if s.currentIdx+1 >= len(s.toDownload) {
+		// each provider hooks get their chapters only
+		batches := make(map[string]chapter.Chapters, len(s.hooks))
+		for _, ch := range s.toDownload {
+			batches[s.origin[ch]] = append(batches[s.origin[ch]], ch)
+		}
+		for id, batch := range batches {
+			s.hooks[id].AfterDownloadBatch(ctx, batch)
 		}
-		s.currentIdx++
-		return nextChapterMsg{}
+		s.closeHooks()
+		return downloadCompletedMsg{}
 	}
+	s.currentIdx++
+	return nextChapterMsg{}
 }
 
-// openCmd acts on the key press and thus relies on the keybinds being updated.
+// loadHooks loads the hooks of the clients that don't have them loaded.
+func (s *state) loadHooks() error {
+	for id, client := range s.clients {
+		if _, ok := s.hooks[id]; ok {
+			continue
+		}
+		hooks, err := hook.Load(client)
+		if err != nil {
+			return err
+		}
+		s.hooks[id] = hooks
+	}
+	return nil
+}
+
+// closeHooks closes the loaded hooks, once the batch is done.
+func (s *state) closeHooks() {
+	for id, hooks := range s.hooks {
+		hooks.Close()
+		delete(s.hooks, id)
+	}
+}
+
+// openCmd acts on the key press and thus relies on the keybinds being updated,
+// opens the directory of the first downloaded chapter.
 func (s *state) openCmd() tea.Msg {
-	return open.Start(s.chapters[0].Down.Directory)
+	succeed := s.chapters.Succeed()
+	if len(succeed) == 0 {
+		return nil
+	}
+	return open.Start(succeed[0].Down.Directory)
 }
 
 // retryCmd acts on the key press and thus relies on the keybinds being updated,
@@ -74,6 +180,9 @@ func (s *state) retryCmd() tea.Cmd {
 	s.toDownload = s.chapters.Failed()
 	return tea.Sequence(
 		func() tea.Msg {
+			if err := s.loadHooks(); err != nil {
+				return err
+			}
 			return nextChapterMsg{}
 		},
 		s.Resize(s.size),
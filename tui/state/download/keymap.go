@@ -9,11 +9,19 @@ import (
 
 var _ help.KeyMap = (*keyMap)(nil)
 
+// keyMapName is the name of the keymap in the config (tui.keys.download).
+const keyMapName = "download"
+
+func init() {
+	util.RegisterKeyMap(keyMapName, func() { newKeyMap(nil) }, util.GlobalKeyMap, viewport.KeyMapName)
+}
+
 func newKeyMap(viewport *viewport.KeyMap) keyMap {
+	keys := util.NewKeys(keyMapName)
 	return keyMap{
 		viewport: viewport,
-		open:     util.Bind("open directory", "o"),
-		retry:    util.Bind("retry", "r"),
+		open:     keys.Bind("open", "open directory", "o"),
+		retry:    keys.Bind("retry", "retry", "r"),
 	}
 }
 
@@ -8,4 +8,8 @@ type retryChapterMsg struct {
 	After time.Duration
 }
 
+// fallbackChapterMsg is sent when the current chapter failed
+// and it should be downloaded with the fallback providers.
+type fallbackChapterMsg struct{}
+
 type downloadCompletedMsg struct{}
@@ -8,6 +8,11 @@ import (
 	"github.com/charmbracelet/bubbles/timer"
 	"github.com/luevano/libmangal"
 	"github.com/luevano/libmangal/mangadata"
+	mangalclient "github.com/luevano/mangal/client"
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/log"
+	"github.com/luevano/mangal/script/hook"
+	"github.com/luevano/mangal/theme/color"
 	"github.com/luevano/mangal/theme/icon"
 	"github.com/luevano/mangal/theme/style"
 	"github.com/luevano/mangal/tui/base"
@@ -15,28 +20,60 @@ import (
 	"github.com/luevano/mangal/util/chapter"
 )
 
+// Chapter to download, along the client of its provider.
+type Chapter struct {
+	Client  *libmangal.Client
+	Chapter mangadata.Chapter
+}
+
 func New(client *libmangal.Client, chaptersToDownload []mangadata.Chapter, options libmangal.DownloadOptions) *state {
+	c := make([]Chapter, len(chaptersToDownload))
+	for i, ch := range chaptersToDownload {
+		c[i] = Chapter{Client: client, Chapter: ch}
+	}
+	return NewChapters(c, options)
+}
+
+// NewChapters downloads the chapters in order, which can be of different providers.
+func NewChapters(chaptersToDownload []Chapter, options libmangal.DownloadOptions) *state {
 	c := make(chapter.Chapters, len(chaptersToDownload))
+	clients := make(map[string]*libmangal.Client)
+	origin := make(map[*chapter.Chapter]string, len(chaptersToDownload))
 	for i, ch := range chaptersToDownload {
+		id := ch.Client.Info().ID
 		c[i] = &chapter.Chapter{
-			Chapter: ch,
+			Chapter: ch.Chapter,
+			Source:  id,
+		}
+		origin[c[i]] = id
+		if _, ok := clients[id]; !ok {
+			clients[id] = ch.Client
 		}
 	}
 
+	// already validated when loading the config
+	chains, err := config.FallbackChains()
+	if err != nil {
+		log.Log("Couldn't get the fallback chains: %s", err.Error())
+	}
+
 	_styles := defaultStyles()
 	sep := _styles.sep.Render(icon.Separator.Raw())
 
 	_viewport := viewport.New()
 	_keyMap := newKeyMap(&_viewport.KeyMap)
 	return &state{
-		progress: progress.New(),
+		progress: progress.New(progress.WithSolidFill(string(color.Accent))),
 		spinner: spinner.New(
 			spinner.WithSpinner(base.DotSpinner),
 			spinner.WithStyle(style.Normal.Accent),
 		),
 		timer:       timer.New(time.Second),
 		viewport:    _viewport,
-		client:      client,
+		clients:     clients,
+		origin:      origin,
+		hooks:       make(map[string]*hook.Hooks, len(clients)),
+		fallback:    mangalclient.NewFallback(config.FallbackProviders(), chains),
 		chapters:    c,
 		options:     options,
 		downloading: dSUninitialized,
@@ -13,7 +13,9 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/luevano/libmangal"
+	mangalclient "github.com/luevano/mangal/client"
 	"github.com/luevano/mangal/log"
+	"github.com/luevano/mangal/script/hook"
 	"github.com/luevano/mangal/tui/base"
 	"github.com/luevano/mangal/tui/model/viewport"
 	"github.com/luevano/mangal/util/chapter"
@@ -36,9 +38,15 @@ type state struct {
 	spinner  spinner.Model
 	timer    timer.Model
 	viewport *viewport.Model
-	client   *libmangal.Client
+	// clients and hooks by provider ID
+	clients  map[string]*libmangal.Client
+	hooks    map[string]*hook.Hooks
+	fallback *mangalclient.Fallback
 	chapters chapter.Chapters
-	options  libmangal.DownloadOptions
+	// origin is the provider ID of each chapter, as
+	// the source changes when downloaded by a fallback
+	origin  map[*chapter.Chapter]string
+	options libmangal.DownloadOptions
 
 	downloading downloadState
 	currentIdx  int
@@ -125,13 +133,15 @@ func (s *state) Resize(size base.Size) tea.Cmd {
 
 // Init implements base.State.
 func (s *state) Init(ctx context.Context) tea.Cmd {
-	s.client.Logger().SetOnLog(func(format string, a ...any) {
-		s.message = fmt.Sprintf(format, a...)
-		// TODO: add option for "verbose" so it logs pages progress?
-		if !strings.HasPrefix(format, "page") {
-			log.Log(format, a...)
-		}
-	})
+	for _, client := range s.clients {
+		client.Logger().SetOnLog(func(format string, a ...any) {
+			s.message = fmt.Sprintf(format, a...)
+			// TODO: add option for "verbose" so it logs pages progress?
+			if !strings.HasPrefix(format, "page") {
+				log.Log(format, a...)
+			}
+		})
+	}
 
 	s.updateKeybinds()
 	return tea.Sequence(
@@ -172,7 +182,10 @@ func (s *state) Update(ctx context.Context, msg tea.Msg) tea.Cmd {
 	case nextChapterMsg:
 		s.updateKeybinds()
 		s.viewport.SetContent(s.viewDownloaded())
-		return s.downloadChapterCmd(ctx)
+		return s.beforeDownloadCmd(ctx)
+	case fallbackChapterMsg:
+		s.message = fmt.Sprintf("Trying fallback providers for chapter %q", s.toDownload[s.currentIdx].Chapter)
+		return s.fallbackChapterCmd(ctx)
 	case retryChapterMsg:
 		s.retrying = true
 		s.timer.Timeout = msg.After
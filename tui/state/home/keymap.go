@@ -8,9 +8,17 @@ import (
 
 var _ help.KeyMap = (*keyMap)(nil)
 
+// keyMapName is the name of the keymap in the config (tui.keys.home).
+const keyMapName = "home"
+
+func init() {
+	util.RegisterKeyMap(keyMapName, func() { newKeyMap() }, util.GlobalKeyMap)
+}
+
 func newKeyMap() keyMap {
+	keys := util.NewKeys(keyMapName)
 	return keyMap{
-		confirm: util.Bind("continue", "enter"),
+		confirm: keys.Bind("confirm", "continue", "enter"),
 	}
 }
 
@@ -46,10 +46,13 @@ func (s *state) searchMangasCmd(ctx context.Context, query string) tea.Cmd {
 				return nil
 			}
 
+			newItems := make([]*item, len(mangas))
 			items := make([]list.Item, len(mangas))
 			for i, m := range mangas {
-				items[i] = newItem(m, s.extraInfo, s.fullExtraInfo)
+				newItems[i] = newItem(s.client.Info().ID, m, s.extraInfo, s.fullExtraInfo)
+				items[i] = newItems[i]
 			}
+			renderNewChapters(newItems...)
 			s.list.SetItems(items)
 
 			s.searched = true
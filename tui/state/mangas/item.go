@@ -1,9 +1,14 @@
 package mangas
 
 import (
+	"fmt"
+
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/luevano/libmangal/mangadata"
+	"github.com/luevano/mangal/theme/icon"
+	"github.com/luevano/mangal/theme/style"
 	"github.com/luevano/mangal/tui/model/metadata"
+	"github.com/luevano/mangal/util/cache"
 )
 
 var (
@@ -21,6 +26,10 @@ type item struct {
 
 	renderedMeta     string
 	renderedFullMeta string
+
+	// key of the read and new chapters of the manga
+	mangaKey    string
+	renderedNew string
 }
 
 // FilterValue implements list.Item.
@@ -31,13 +40,13 @@ func (i *item) FilterValue() string {
 // Title implements list.DefaultItem.
 func (i *item) Title() string {
 	if !(*i.extraInfo) {
-		return i.FilterValue()
+		return i.FilterValue() + i.renderedNew
 	}
 
 	if *i.fullExtraInfo {
-		return i.FilterValue() + i.renderedFullMeta
+		return i.FilterValue() + i.renderedNew + i.renderedFullMeta
 	}
-	return i.FilterValue() + i.renderedMeta
+	return i.FilterValue() + i.renderedNew + i.renderedMeta
 }
 
 // Description implements list.DefaultItem.
@@ -45,12 +54,13 @@ func (i *item) Description() string {
 	return i.manga.Info().URL
 }
 
-func newItem(manga mangadata.Manga, info, fullInfo *bool) *item {
+func newItem(providerID string, manga mangadata.Manga, info, fullInfo *bool) *item {
 	i := &item{
 		manga:         manga,
 		meta:          metadata.New(manga.Metadata()),
 		extraInfo:     info,
 		fullExtraInfo: fullInfo,
+		mangaKey:      cache.MangaKey(providerID, manga.Info().ID),
 	}
 	i.renderMetadata()
 	return i
@@ -69,3 +79,11 @@ func (i *item) renderMetadata() {
 	i.meta.ShowFull = true
 	i.renderedFullMeta = " " + i.meta.View()
 }
+
+// renderNewChapters pre-renders the amount of unread new chapters.
+func (i *item) renderNewChapters(count int) {
+	i.renderedNew = ""
+	if count > 0 {
+		i.renderedNew = " " + icon.New.Colored() + " " + style.Bold.Success.Render(fmt.Sprintf("%d new", count))
+	}
+}
@@ -3,19 +3,28 @@ package mangas
 import (
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/luevano/mangal/tui/model/list"
 	"github.com/luevano/mangal/tui/util"
 )
 
 var _ help.KeyMap = (*keyMap)(nil)
 
+// keyMapName is the name of the keymap in the config (tui.keys.mangas).
+const keyMapName = "mangas"
+
+func init() {
+	util.RegisterKeyMap(keyMapName, func() { newKeyMap() }, util.GlobalKeyMap, list.KeyMapName)
+}
+
 func newKeyMap() keyMap {
+	keys := util.NewKeys(keyMapName)
 	return keyMap{
-		confirm:        util.Bind("confirm", "enter"),
-		search:         util.Bind("search", "s"),
-		anilist:        util.Bind("anilist", "A"),
-		metadata:       util.Bind("metadata", "m"),
-		info:           util.Bind("info", "i"),
-		toggleFullMeta: util.Bind("toggle full meta", "M"),
+		confirm:        keys.Bind("confirm", "confirm", "enter"),
+		search:         keys.Bind("search", "search", "s"),
+		anilist:        keys.Bind("anilist", "anilist", "A"),
+		metadata:       keys.Bind("metadata", "metadata", "m"),
+		info:           keys.Bind("info", "info", "i"),
+		toggleFullMeta: keys.Bind("toggle_full_meta", "toggle full meta", "M"),
 	}
 }
 
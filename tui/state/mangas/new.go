@@ -16,7 +16,7 @@ func New(client *libmangal.Client) *state {
 		"manga", "mangas",
 		nil,
 		func(manga mangadata.Manga) _list.DefaultItem {
-			return newItem(manga, &info, &fullInfo)
+			return newItem(client.Info().ID, manga, &info, &fullInfo)
 		},
 	)
 
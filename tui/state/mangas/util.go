@@ -1,14 +1,40 @@
 package mangas
 
+import (
+	"github.com/luevano/mangal/log"
+	"github.com/luevano/mangal/util/cache"
+)
+
 func (s *state) updateItem(item *item) {
 	item.updateMetadata()
 	item.renderMetadata()
+	renderNewChapters(item)
 }
 
 func (s *state) updateAllItems() {
-	for _, i := range s.list.Items() {
+	items := make([]*item, len(s.list.Items()))
+	for idx, i := range s.list.Items() {
 		i := i.(*item)
-		s.updateItem(i)
+		i.updateMetadata()
+		i.renderMetadata()
+		items[idx] = i
+	}
+	renderNewChapters(items...)
+}
+
+// renderNewChapters pre-renders the amount of unread new chapters of the items,
+// only available for the followed series (notify watch).
+func renderNewChapters(items ...*item) {
+	keys := make([]string, len(items))
+	for idx, i := range items {
+		keys[idx] = i.mangaKey
+	}
+	counts, err := cache.UnreadNewChapterCounts(keys)
+	if err != nil {
+		log.Log("Couldn't get the new chapters: %s", err.Error())
+	}
+	for _, i := range items {
+		i.renderNewChapters(counts[i.mangaKey])
 	}
 }
 
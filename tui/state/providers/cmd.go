@@ -10,6 +10,7 @@ import (
 	"github.com/luevano/mangal/client"
 	"github.com/luevano/mangal/log"
 	"github.com/luevano/mangal/tui/base"
+	"github.com/luevano/mangal/tui/basket"
 	"github.com/luevano/mangal/tui/state/mangas"
 )
 
@@ -32,10 +33,12 @@ func (s *state) loadProviderCmd(ctx context.Context, item *item) tea.Cmd {
 				item.markLoaded()
 
 				mangalClient.Logger().SetOnLog(func(format string, a ...any) {
-					// TODO: add option for "verbose" so it logs pages progress?
-					if !strings.HasPrefix(format, "page") {
-						log.Log(format, a...)
+					// pages progress is only logged when verbose
+					event := log.L.Info()
+					if strings.HasPrefix(format, "page") {
+						event = log.L.Debug()
 					}
+					event.Str("provider", item.loader.Info().ID).Msgf(format, a...)
 				})
 			}
 			log.Log("Using %s mangal client for provider %q", newex, item.loader.String())
@@ -55,6 +58,8 @@ func (s *state) closeAllProvidersCmd() tea.Msg {
 	for _, item := range s.loaded.Keys() {
 		item.markClosed()
 	}
+	// the chapters in the basket belong to the closed clients
+	basket.Clear()
 
 	return base.Notify("Closed all clients")()
 }
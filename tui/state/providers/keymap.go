@@ -3,16 +3,25 @@ package providers
 import (
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/luevano/mangal/tui/model/list"
 	"github.com/luevano/mangal/tui/util"
 )
 
 var _ help.KeyMap = (*keyMap)(nil)
 
+// keyMapName is the name of the keymap in the config (tui.keys.providers).
+const keyMapName = "providers"
+
+func init() {
+	util.RegisterKeyMap(keyMapName, func() { newKeyMap() }, util.GlobalKeyMap, list.KeyMapName)
+}
+
 func newKeyMap() keyMap {
+	keys := util.NewKeys(keyMapName)
 	return keyMap{
-		confirm:  util.Bind("confirm", "enter"),
-		info:     util.Bind("info", "i"),
-		closeAll: util.Bind("close all", "backspace"),
+		confirm:  keys.Bind("confirm", "confirm", "enter"),
+		info:     keys.Bind("info", "info", "i"),
+		closeAll: keys.Bind("close_all", "close all", "backspace"),
 	}
 }
 
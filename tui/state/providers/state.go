@@ -8,6 +8,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/luevano/mangal/client"
 	"github.com/luevano/mangal/tui/base"
+	"github.com/luevano/mangal/tui/basket"
 	"github.com/luevano/mangal/tui/model/list"
 	"github.com/zyedidia/generic/set"
 )
@@ -63,7 +64,12 @@ func (s *state) Init(ctx context.Context) tea.Cmd {
 	// State.Destroy() (if implemented) method and perform that there?
 	return tea.Sequence(
 		func() tea.Msg {
-			return client.CloseAll()
+			if err := client.CloseAll(); err != nil {
+				return err
+			}
+			// the chapters in the basket belong to the closed clients
+			basket.Clear()
+			return nil
 		},
 		s.list.Init(),
 	)
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/luevano/mangal/client"
+	"github.com/luevano/mangal/config"
 	"github.com/luevano/mangal/tui/base"
 	"github.com/luevano/mangal/tui/state/chapters"
 )
@@ -23,3 +25,27 @@ func (s *state) searchVolumeChapters(ctx context.Context, item *item) tea.Cmd {
 		base.Loaded,
 	)
 }
+
+func (s *state) mergeVolumeCmd(ctx context.Context, item *item) tea.Cmd {
+	return tea.Sequence(
+		base.Loading(fmt.Sprintf("Merging chapters of volume %s", item.volume)),
+		func() tea.Msg {
+			chapterList, err := s.client.VolumeChapters(ctx, item.volume)
+			if err != nil {
+				return err
+			}
+
+			options := config.DownloadOptions()
+			// Guaranteed to had been searched during mangas state
+			options.SearchMetadata = false
+			merged, err := client.MergeVolume(ctx, s.client, item.volume, chapterList, options)
+			if err != nil {
+				return err
+			}
+			return base.NotificationMsg{
+				Message: fmt.Sprintf("Merged %d chapters into %s (%s)", len(merged.Chapters), merged.Filename, merged.Status),
+			}
+		},
+		base.Loaded,
+	)
+}
@@ -3,23 +3,34 @@ package volumes
 import (
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/luevano/mangal/tui/model/list"
 	"github.com/luevano/mangal/tui/util"
 )
 
 var _ help.KeyMap = (*keyMap)(nil)
 
+// keyMapName is the name of the keymap in the config (tui.keys.volumes).
+const keyMapName = "volumes"
+
+func init() {
+	util.RegisterKeyMap(keyMapName, func() { newKeyMap() }, util.GlobalKeyMap, list.KeyMapName)
+}
+
 func newKeyMap() keyMap {
+	keys := util.NewKeys(keyMapName)
 	return keyMap{
-		confirm:  util.Bind("confirm", "enter"),
-		anilist:  util.Bind("anilist", "A"),
-		metadata: util.Bind("metadata", "m"),
-		info:     util.Bind("info", "i"),
+		confirm:  keys.Bind("confirm", "confirm", "enter"),
+		merge:    keys.Bind("merge", "merge", "M"),
+		anilist:  keys.Bind("anilist", "anilist", "A"),
+		metadata: keys.Bind("metadata", "metadata", "m"),
+		info:     keys.Bind("info", "info", "i"),
 	}
 }
 
 // keyMap implements help.keyMap.
 type keyMap struct {
 	confirm,
+	merge,
 	anilist,
 	metadata,
 	info key.Binding
@@ -29,6 +40,7 @@ type keyMap struct {
 func (k keyMap) ShortHelp() []key.Binding {
 	return []key.Binding{
 		k.confirm,
+		k.merge,
 		k.anilist,
 		k.metadata,
 		k.info,
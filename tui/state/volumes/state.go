@@ -86,6 +86,8 @@ func (s *state) Update(ctx context.Context, msg tea.Msg) (cmd tea.Cmd) {
 		switch {
 		case key.Matches(msg, s.keyMap.confirm):
 			return s.searchVolumeChapters(ctx, i)
+		case key.Matches(msg, s.keyMap.merge):
+			return s.mergeVolumeCmd(ctx, i)
 		case key.Matches(msg, s.keyMap.anilist):
 			ani, err := s.client.GetMetadataProvider(lmmeta.IDSourceAnilist)
 			if err != nil {
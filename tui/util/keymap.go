@@ -1,6 +1,11 @@
 package util
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/luevano/mangal/config"
+)
 
 // Bind is a convenience function to create key bindings easily.
 func Bind(help string, primaryKey string, extraKeys ...string) key.Binding {
@@ -23,3 +28,58 @@ func BindNamedKey(keyHelp, help string, keys ...string) key.Binding {
 		key.WithHelp(keyHelp, help),
 	)
 }
+
+// Keys creates the key bindings of a keymap by action name,
+// the user can override the keys of any action in the config
+// (tui.keys.<keymap>.<action>).
+type Keys struct {
+	keyMap    string
+	overrides map[string][]string
+}
+
+// NewKeys returns the Keys of the keymap, with the user overrides.
+func NewKeys(keyMap string) Keys {
+	// already validated when loading the config
+	keyMaps, _ := config.TUIKeys()
+	return Keys{
+		keyMap:    keyMap,
+		overrides: keyMaps[keyMap],
+	}
+}
+
+// Bind is the same as the Bind function, for the action.
+func (k Keys) Bind(action, help string, primaryKey string, extraKeys ...string) key.Binding {
+	return k.BindNamedKey(action, primaryKey, help, append([]string{primaryKey}, extraKeys...)...)
+}
+
+// BindNamedKey is the same as the BindNamedKey function, for the action.
+//
+// If overridden, the help shows the user keys instead of keyHelp.
+func (k Keys) BindNamedKey(action, keyHelp, help string, keys ...string) key.Binding {
+	record(k.keyMap, action, keys)
+	if override, ok := k.overrides[action]; ok {
+		return BindNamedKey(keysHelp(override), help, override...)
+	}
+	return BindNamedKey(keyHelp, help, keys...)
+}
+
+// Rebind applies the override of the action to an existing binding,
+// such as the ones of the bubbles components.
+func (k Keys) Rebind(action string, binding *key.Binding) {
+	record(k.keyMap, action, binding.Keys())
+	if override, ok := k.overrides[action]; ok {
+		binding.SetKeys(override...)
+		binding.SetHelp(keysHelp(override), binding.Help().Desc)
+	}
+}
+
+func keysHelp(keys []string) string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		if k == " " {
+			k = "space"
+		}
+		names[i] = k
+	}
+	return strings.Join(names, "/")
+}
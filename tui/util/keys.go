@@ -0,0 +1,176 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/luevano/mangal/config"
+)
+
+// GlobalKeyMap is the name of the keymap active in all the states.
+const GlobalKeyMap = "global"
+
+type registeredKeyMap struct {
+	build func()
+	with  []string
+}
+
+var (
+	keysMu sync.Mutex
+	// registered keymaps by name
+	keyMaps = make(map[string]registeredKeyMap)
+	// default keys of each action by keymap, recorded when binding
+	defaultKeys = make(map[string]map[string][]string)
+)
+
+// RegisterKeyMap registers the keymap so its actions can be listed and
+// validated. The build function needs to create the keymap with NewKeys(name).
+//
+// The keymap is active along the ones it is used with, their keys can't conflict.
+func RegisterKeyMap(name string, build func(), with ...string) {
+	keysMu.Lock()
+	defer keysMu.Unlock()
+	keyMaps[name] = registeredKeyMap{build: build, with: with}
+}
+
+func record(keyMap, action string, keys []string) {
+	keysMu.Lock()
+	defer keysMu.Unlock()
+	if defaultKeys[keyMap] == nil {
+		defaultKeys[keyMap] = make(map[string][]string)
+	}
+	defaultKeys[keyMap][action] = slices.Clone(keys)
+}
+
+// KeyAction is a bindable action of a keymap.
+type KeyAction struct {
+	KeyMap  string
+	Name    string
+	Default []string
+	// Keys are the user keys if overridden, else the default.
+	Keys []string
+}
+
+// Overridden returns true if the user set the keys of the action.
+func (a KeyAction) Overridden() bool {
+	return !slices.Equal(a.Default, a.Keys)
+}
+
+func (a KeyAction) String() string {
+	return a.KeyMap + "." + a.Name
+}
+
+// KeyActions returns the actions of all the registered keymaps,
+// sorted by keymap and action name.
+func KeyActions() ([]KeyAction, error) {
+	overrides, err := config.TUIKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	keysMu.Lock()
+	builds := make([]func(), 0, len(keyMaps))
+	for _, name := range slices.Sorted(maps.Keys(keyMaps)) {
+		builds = append(builds, keyMaps[name].build)
+	}
+	keysMu.Unlock()
+	for _, build := range builds {
+		build()
+	}
+
+	keysMu.Lock()
+	defer keysMu.Unlock()
+	var actions []KeyAction
+	for _, name := range slices.Sorted(maps.Keys(keyMaps)) {
+		for _, action := range slices.Sorted(maps.Keys(defaultKeys[name])) {
+			a := KeyAction{
+				KeyMap:  name,
+				Name:    action,
+				Default: defaultKeys[name][action],
+				Keys:    defaultKeys[name][action],
+			}
+			if keys, ok := overrides[name][action]; ok {
+				a.Keys = keys
+			}
+			actions = append(actions, a)
+		}
+	}
+	return actions, nil
+}
+
+// ValidateKeys validates the user keybinding overrides.
+//
+// The overridden keymaps and actions need to exist, and the
+// new keys can't conflict with the other actions active along them,
+// unless they already shared keys by default (used in different modes).
+func ValidateKeys() error {
+	actions, err := KeyActions()
+	if err != nil {
+		return err
+	}
+	overrides, _ := config.TUIKeys()
+
+	byKeyMap := make(map[string][]KeyAction)
+	for _, a := range actions {
+		byKeyMap[a.KeyMap] = append(byKeyMap[a.KeyMap], a)
+	}
+
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(overrides)) {
+		available, ok := byKeyMap[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown TUI keymap %q, available: %s", name, strings.Join(slices.Sorted(maps.Keys(byKeyMap)), ", ")))
+			continue
+		}
+		for _, action := range slices.Sorted(maps.Keys(overrides[name])) {
+			if !slices.ContainsFunc(available, func(a KeyAction) bool { return a.Name == action }) {
+				names := make([]string, len(available))
+				for i, a := range available {
+					names[i] = a.Name
+				}
+				errs = append(errs, fmt.Errorf("unknown action %q of TUI keymap %q, available: %s", action, name, strings.Join(names, ", ")))
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range slices.Sorted(maps.Keys(byKeyMap)) {
+		keysMu.Lock()
+		active := slices.Clone(byKeyMap[name])
+		for _, with := range keyMaps[name].with {
+			active = append(active, byKeyMap[with]...)
+		}
+		keysMu.Unlock()
+
+		for i, a := range active {
+			for _, b := range active[i+1:] {
+				if !a.Overridden() && !b.Overridden() {
+					continue
+				}
+				shared := sharedKey(a.Keys, b.Keys)
+				if shared == "" || sharedKey(a.Default, b.Default) != "" {
+					continue
+				}
+				conflict := fmt.Sprintf("TUI key %q of %s conflicts with %s", keysHelp([]string{shared}), a, b)
+				if !seen[conflict] {
+					seen[conflict] = true
+					errs = append(errs, errors.New(conflict))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func sharedKey(a, b []string) string {
+	for _, k := range a {
+		if slices.Contains(b, k) {
+			return k
+		}
+	}
+	return ""
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/metrics"
 	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
@@ -96,6 +97,7 @@ func (s Store) Get(k string, v interface{}) (found bool, err error) {
 		}
 		return nil
 	})
+	metrics.CacheLookup(s.bucketName, err == nil && data != nil)
 	if err != nil {
 		return false, nil
 	}
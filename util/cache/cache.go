@@ -88,7 +88,9 @@ func CacheStore(dbName, bucketName string) (gokv.Store, error) {
 		// tokens expire, delete the individual
 		// auth data and prompt for re-authenticat
 		ttl = 0
-	case BucketNameSearchHistory:
+	case BucketNameSearchHistory,
+		BucketNameReadHistory,
+		BucketNameNewChapters:
 		ttl = 0 // no expiry
 	}
 
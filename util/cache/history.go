@@ -1,6 +1,9 @@
 package cache
 
-import "sort"
+import (
+	"slices"
+	"sort"
+)
 
 // Record is a single history record.
 type Record struct {
@@ -112,3 +115,25 @@ func (u *UserHistory) Delete(username string) {
 	}
 	*u = newHistory
 }
+
+// ChapterNumbers are the numbers of some chapters of a manga (read, new, etc.).
+type ChapterNumbers []float32
+
+// Has returns true if the chapter number is included.
+func (c ChapterNumbers) Has(number float32) bool {
+	return slices.Contains(c, number)
+}
+
+// Add the chapter number, if not already included.
+func (c *ChapterNumbers) Add(number float32) {
+	if !c.Has(number) {
+		*c = append(*c, number)
+	}
+}
+
+// Remove the chapter numbers that match, returns true if any was removed.
+func (c *ChapterNumbers) Remove(match func(number float32) bool) bool {
+	size := len(*c)
+	*c = slices.DeleteFunc(*c, match)
+	return len(*c) != size
+}
@@ -8,6 +8,8 @@ const CacheDBNameMangal = "mangal"
 
 const (
 	BucketNameSearchHistory = "search-history"
+	BucketNameReadHistory   = "read-history"
+	BucketNameNewChapters   = "new-chapters"
 )
 
 const (
@@ -96,3 +98,105 @@ func GetAnilistSearchHistory(records *Records) (bool, error) {
 	}
 	return false, nil
 }
+
+// MangaKey is the key of the chapter numbers (read, new) of the manga.
+func MangaKey(providerID, mangaID string) string {
+	return providerID + "/" + mangaID
+}
+
+// SetReadHistory will store the read chapters of the manga to the cache.
+func SetReadHistory(key string, read ChapterNumbers) error {
+	err := store_.open(BucketNameReadHistory)
+	if err != nil {
+		return err
+	}
+	defer store_.close()
+
+	return store_.store.Set(key, read)
+}
+
+// GetReadHistory will populate the given read chapters of the manga from the cache.
+func GetReadHistory(key string, read *ChapterNumbers) (bool, error) {
+	err := store_.open(BucketNameReadHistory)
+	if err != nil {
+		return false, err
+	}
+	defer store_.close()
+
+	return store_.store.Get(key, read)
+}
+
+// SetNewChapters will store the new chapters of the manga to the cache.
+func SetNewChapters(key string, chapters ChapterNumbers) error {
+	err := store_.open(BucketNameNewChapters)
+	if err != nil {
+		return err
+	}
+	defer store_.close()
+
+	return store_.store.Set(key, chapters)
+}
+
+// GetNewChapters will populate the given new chapters of the manga from the cache.
+func GetNewChapters(key string, chapters *ChapterNumbers) (bool, error) {
+	err := store_.open(BucketNameNewChapters)
+	if err != nil {
+		return false, err
+	}
+	defer store_.close()
+
+	return store_.store.Get(key, chapters)
+}
+
+// AddNewChapters adds the chapter numbers to the new chapters of the manga.
+func AddNewChapters(key string, numbers ChapterNumbers) error {
+	chapters := ChapterNumbers{}
+	if _, err := GetNewChapters(key, &chapters); err != nil {
+		return err
+	}
+	for _, number := range numbers {
+		chapters.Add(number)
+	}
+	return SetNewChapters(key, chapters)
+}
+
+// UnreadNewChapterCounts returns the amount of new chapters not in the read
+// history of each manga, opening each bucket once for all the mangas.
+func UnreadNewChapterCounts(keys []string) (map[string]int, error) {
+	err := store_.open(BucketNameNewChapters)
+	if err != nil {
+		return nil, err
+	}
+	newChapters := make(map[string]ChapterNumbers)
+	for _, key := range keys {
+		chapters := ChapterNumbers{}
+		if _, err := store_.store.Get(key, &chapters); err != nil {
+			store_.close()
+			return nil, err
+		}
+		if len(chapters) > 0 {
+			newChapters[key] = chapters
+		}
+	}
+	if err := store_.close(); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(newChapters))
+	if len(newChapters) == 0 {
+		return counts, nil
+	}
+	if err := store_.open(BucketNameReadHistory); err != nil {
+		return nil, err
+	}
+	defer store_.close()
+	for key, chapters := range newChapters {
+		read := ChapterNumbers{}
+		if _, err := store_.store.Get(key, &read); err != nil {
+			return nil, err
+		}
+		chapters.Remove(read.Has)
+		counts[key] = len(chapters)
+	}
+	return counts, nil
+}
@@ -13,6 +13,12 @@ type Chapter struct {
 	Chapter mangadata.Chapter
 	Down    *metadata.DownloadedChapter
 	Err     error
+
+	// Source is the ID of the provider the chapter was downloaded with.
+	Source string
+	// Fallback is true if Source is a fallback provider
+	// and not the one the chapter originally belongs to.
+	Fallback bool
 }
 
 // ToDownload returns true if it is yet to be downloaded
@@ -54,6 +60,9 @@ func (c Chapter) Render(normal, toDownload, succeed, failed, warning lipgloss.St
 		status = toDownload.Render(status)
 	}
 	str = s.Render(str+" - ") + status + s.Render(" - f: "+c.Down.Filename)
+	if c.Fallback {
+		str += warning.Render(" - p: " + c.Source)
+	}
 	return str
 }
 
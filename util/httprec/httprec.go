@@ -0,0 +1,188 @@
+// Package httprec records HTTP request/response pairs into a cassette
+// directory and replays them back, to run mangal fully offline.
+package httprec
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"sync"
+
+	"github.com/luevano/mangal/config"
+	"github.com/luevano/mangal/util/afs"
+)
+
+type Mode uint8
+
+const (
+	ModeOff Mode = iota
+	ModeRecord
+	ModeReplay
+)
+
+var (
+	mode Mode
+	dir  string
+)
+
+// Set the global mode and cassette directory used by Transport.
+func Set(m Mode, cassetteDir string) {
+	mode = m
+	dir = cassetteDir
+}
+
+// Transport wraps next with the globally set mode,
+// next is returned as is when off. A nil next means http.DefaultTransport.
+func Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	switch mode {
+	case ModeRecord:
+		return &recorder{dir: dir, next: next}
+	case ModeReplay:
+		return &replayer{dir: dir}
+	default:
+		return next
+	}
+}
+
+// Wrap sets the Transport of the client.
+func Wrap(client *http.Client) *http.Client {
+	client.Transport = Transport(client.Transport)
+	return client
+}
+
+// ErrNotRecorded is returned on replay when the request is not in the cassette.
+var ErrNotRecorded = errors.New("request not recorded in the cassette")
+
+// interaction is the request/response pair stored in a cassette file.
+type interaction struct {
+	Request struct {
+		Method string `json:"method"`
+		URL    string `json:"url"`
+	} `json:"request"`
+	Response struct {
+		StatusCode int         `json:"status_code"`
+		Header     http.Header `json:"header"`
+		Body       []byte      `json:"body"`
+	} `json:"response"`
+}
+
+// key identifies the request by its method, url and body.
+func key(req *http.Request) (string, error) {
+	h := sha256.New()
+	h.Write([]byte(req.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(req.URL.String()))
+	h.Write([]byte{0})
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return "", err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		h.Write(body)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func cassettePath(dir, key string) string {
+	return filepath.Join(dir, key+".json")
+}
+
+var _ http.RoundTripper = (*recorder)(nil)
+
+type recorder struct {
+	dir  string
+	next http.RoundTripper
+	m    sync.Mutex
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	k, err := key(req)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	var i interaction
+	i.Request.Method = req.Method
+	i.Request.URL = req.URL.String()
+	i.Response.StatusCode = res.StatusCode
+	i.Response.Header = res.Header
+	i.Response.Body = body
+
+	data, err := json.MarshalIndent(i, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	if err := afs.Afero.MkdirAll(r.dir, config.Download.ModeDir.Get()); err != nil {
+		return nil, err
+	}
+	if err := afs.Afero.WriteFile(cassettePath(r.dir, k), data, config.Download.ModeFile.Get()); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+var _ http.RoundTripper = (*replayer)(nil)
+
+type replayer struct {
+	dir string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	k, err := key(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := afs.Afero.ReadFile(cassettePath(r.dir, k))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, req.Method, req.URL)
+		}
+		return nil, err
+	}
+
+	var i interaction
+	if err := json.Unmarshal(data, &i); err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Response.StatusCode, http.StatusText(i.Response.StatusCode)),
+		StatusCode:    i.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        i.Response.Header,
+		Body:          io.NopCloser(bytes.NewReader(i.Response.Body)),
+		ContentLength: int64(len(i.Response.Body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,52 @@
+package httprec
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "yes")
+		io.WriteString(w, "hello "+r.URL.Query().Get("q"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	get := func(url string) (string, error) {
+		client := Wrap(&http.Client{})
+		res, err := client.Get(url)
+		if err != nil {
+			return "", err
+		}
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		if res.Header.Get("X-Test") != "yes" {
+			t.Errorf("header X-Test not kept")
+		}
+		return string(body), err
+	}
+
+	Set(ModeRecord, dir)
+	if _, err := get(server.URL + "?q=world"); err != nil {
+		t.Fatalf("record: %s", err)
+	}
+	server.Close()
+
+	Set(ModeReplay, dir)
+	defer Set(ModeOff, "")
+	body, err := get(server.URL + "?q=world")
+	if err != nil {
+		t.Fatalf("replay: %s", err)
+	}
+	if body != "hello world" {
+		t.Errorf("replay body = %q, want %q", body, "hello world")
+	}
+
+	if _, err := get(server.URL + "?q=unknown"); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("replay unknown request error = %v, want %v", err, ErrNotRecorded)
+	}
+}
@@ -0,0 +1,59 @@
+package image
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+)
+
+// avifenc is the libavif encoder, used as there is no Go AV1 encoder.
+const avifenc = "avifenc"
+
+// encodeAVIF encodes the image as AVIF with avifenc, needs to be in the PATH.
+func encodeAVIF(w io.Writer, img image.Image, quality int) error {
+	path, err := exec.LookPath(avifenc)
+	if err != nil {
+		return fmt.Errorf("AVIF encoding needs %s (libavif) in the PATH: %w", avifenc, err)
+	}
+
+	dir, err := os.MkdirTemp("", "mangal-avif-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	in, out := filepath.Join(dir, "page.png"), filepath.Join(dir, "page.avif")
+	file, err := os.Create(in)
+	if err != nil {
+		return err
+	}
+	// only read back by avifenc
+	encoder := png.Encoder{CompressionLevel: png.NoCompression}
+	err = encoder.Encode(file, img)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if quality <= 0 || quality > 100 {
+		quality = 75
+	}
+	output, err := exec.Command(path, "-q", strconv.Itoa(quality), in, out).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %w: %s", avifenc, err, output)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
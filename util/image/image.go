@@ -0,0 +1,268 @@
+package image
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // register gif decoder
+	"image/jpeg"
+	"image/png"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // register webp decoder
+)
+
+// Format is the output format of the transformed images.
+type Format string
+
+const (
+	// FormatOriginal keeps the original format, if possible.
+	FormatOriginal Format = ""
+	FormatJPEG     Format = "jpeg"
+	FormatPNG      Format = "png"
+	// FormatWebP is lossless, near-lossless below quality 81.
+	FormatWebP Format = "webp"
+	// FormatAVIF needs avifenc (libavif) in the PATH.
+	FormatAVIF Format = "avif"
+)
+
+// ParseFormat parses the format name.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case FormatOriginal, FormatJPEG, FormatPNG, FormatWebP, FormatAVIF:
+		return Format(s), nil
+	case "jpg":
+		return FormatJPEG, nil
+	default:
+		return "", fmt.Errorf("unknown image format %q, available: %q, %q, %q and %q", s, FormatJPEG, FormatPNG, FormatWebP, FormatAVIF)
+	}
+}
+
+// Extension returns the file extension of the format, starting with a dot.
+func (f Format) Extension() string {
+	switch f {
+	case FormatPNG:
+		return ".png"
+	case FormatWebP:
+		return ".webp"
+	case FormatAVIF:
+		return ".avif"
+	default:
+		return ".jpg"
+	}
+}
+
+// Image is an encoded image.
+type Image struct {
+	Data   []byte
+	Format Format
+}
+
+// Options for the image transformation steps,
+// the zero value doesn't transform the images.
+type Options struct {
+	// Format to convert to.
+	Format Format
+	// Quality of the JPEG, WebP and AVIF encoding, 1-100.
+	Quality int
+	// Grayscale conversion, useful for e-ink devices.
+	Grayscale bool
+	// MaxWidth to resize to, keeping the aspect ratio. 0 disables it.
+	MaxWidth int
+	// MaxHeight to resize to, keeping the aspect ratio. 0 disables it.
+	MaxHeight int
+	// AutoCrop white borders.
+	AutoCrop bool
+	// SplitSpreads splits double page spreads (wider than tall) in two
+	// pages, the right half first (manga reading order).
+	SplitSpreads bool
+	// LeftToRight puts the left half of split spreads first (comics reading order).
+	LeftToRight bool
+}
+
+// Enabled returns true if any step is enabled.
+func (o Options) Enabled() bool {
+	return o.Format != FormatOriginal ||
+		o.Grayscale ||
+		o.MaxWidth > 0 ||
+		o.MaxHeight > 0 ||
+		o.AutoCrop ||
+		o.SplitSpreads
+}
+
+// Transform decodes the image, applies the steps in order (auto-crop, split spreads,
+// resize, grayscale and convert) and encodes it back. Returns two images
+// in reading order for a split spread, otherwise one.
+func Transform(b []byte, options Options) ([]Image, error) {
+	img, format, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %w", err)
+	}
+
+	if options.AutoCrop {
+		img = autoCrop(img)
+	}
+	images := []image.Image{img}
+	if options.SplitSpreads {
+		images = splitSpread(img, options.LeftToRight)
+	}
+
+	out := options.Format
+	if out == FormatOriginal {
+		switch format {
+		case "png", "gif":
+			out = FormatPNG
+		case "webp":
+			out = FormatWebP
+		default:
+			out = FormatJPEG
+		}
+	}
+
+	result := make([]Image, len(images))
+	for i, img := range images {
+		if options.MaxWidth > 0 || options.MaxHeight > 0 {
+			img = resize(img, options.MaxWidth, options.MaxHeight)
+		}
+		if options.Grayscale {
+			img = grayscale(img)
+		}
+		data, err := encode(img, out, options.Quality)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = Image{Data: data, Format: out}
+	}
+	return result, nil
+}
+
+func encode(img image.Image, format Format, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case FormatPNG:
+		err = png.Encode(&buf, img)
+	case FormatWebP:
+		err = encodeWebP(&buf, img, quality)
+	case FormatAVIF:
+		err = encodeAVIF(&buf, img, quality)
+	default:
+		if quality <= 0 {
+			quality = jpeg.DefaultQuality
+		}
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: min(quality, 100)})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error encoding image as %s: %w", format, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// whiteThreshold is the minimum luminance (16 bit) considered white.
+const whiteThreshold = 0xF000
+
+func isWhite(c color.Color) bool {
+	return color.Gray16Model.Convert(c).(color.Gray16).Y >= whiteThreshold
+}
+
+// autoCrop removes the white borders.
+func autoCrop(img image.Image) image.Image {
+	b := img.Bounds()
+	rowWhite := func(y int) bool {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if !isWhite(img.At(x, y)) {
+				return false
+			}
+		}
+		return true
+	}
+	colWhite := func(x, minY, maxY int) bool {
+		for y := minY; y < maxY; y++ {
+			if !isWhite(img.At(x, y)) {
+				return false
+			}
+		}
+		return true
+	}
+
+	minY, maxY := b.Min.Y, b.Max.Y
+	for minY < maxY && rowWhite(minY) {
+		minY++
+	}
+	for maxY > minY && rowWhite(maxY-1) {
+		maxY--
+	}
+	minX, maxX := b.Min.X, b.Max.X
+	for minX < maxX && colWhite(minX, minY, maxY) {
+		minX++
+	}
+	for maxX > minX && colWhite(maxX-1, minY, maxY) {
+		maxX--
+	}
+
+	crop := image.Rect(minX, minY, maxX, maxY)
+	// all white or nothing to crop
+	if crop.Empty() || crop == b {
+		return img
+	}
+	return subImage(img, crop)
+}
+
+// splitSpread splits the image in its right and left halves if it's a spread,
+// or left and right if leftToRight.
+func splitSpread(img image.Image, leftToRight bool) []image.Image {
+	b := img.Bounds()
+	if b.Dx() <= b.Dy() {
+		return []image.Image{img}
+	}
+	half := b.Dx() / 2
+	right := image.Rect(b.Min.X+half, b.Min.Y, b.Max.X, b.Max.Y)
+	left := image.Rect(b.Min.X, b.Min.Y, b.Min.X+half, b.Max.Y)
+	first, second := right, left
+	if leftToRight {
+		first, second = left, right
+	}
+	return []image.Image{subImage(img, first), subImage(img, second)}
+}
+
+// resize scales down the image to fit in maxWidth x maxHeight, keeping
+// the aspect ratio. Images are never scaled up.
+func resize(img image.Image, maxWidth, maxHeight int) image.Image {
+	b := img.Bounds()
+	scale := 1.0
+	if maxWidth > 0 && b.Dx() > maxWidth {
+		scale = min(scale, float64(maxWidth)/float64(b.Dx()))
+	}
+	if maxHeight > 0 && b.Dy() > maxHeight {
+		scale = min(scale, float64(maxHeight)/float64(b.Dy()))
+	}
+	if scale == 1.0 {
+		return img
+	}
+
+	w := max(1, int(float64(b.Dx())*scale))
+	h := max(1, int(float64(b.Dy())*scale))
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, b, xdraw.Src, nil)
+	return dst
+}
+
+func grayscale(img image.Image) image.Image {
+	b := img.Bounds()
+	dst := image.NewGray(b)
+	draw.Draw(dst, b, img, b.Min, draw.Src)
+	return dst
+}
+
+func subImage(img image.Image, r image.Rectangle) image.Image {
+	if s, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return s.SubImage(r)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, r.Min, draw.Src)
+	return dst
+}
@@ -0,0 +1,145 @@
+package image
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestTransform(t *testing.T) {
+	// 200x100 white spread with a black 100x50 block in the middle
+	src := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	draw.Draw(src, src.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(src, image.Rect(50, 25, 150, 75), image.NewUniform(color.Black), image.Point{}, draw.Src)
+
+	tests := []struct {
+		name    string
+		options Options
+		pages   int
+		want    image.Point
+	}{
+		{"none", Options{Format: FormatPNG}, 1, image.Pt(200, 100)},
+		{"webp", Options{Format: FormatWebP}, 1, image.Pt(200, 100)},
+		{"crop", Options{AutoCrop: true}, 1, image.Pt(100, 50)},
+		{"split", Options{SplitSpreads: true}, 2, image.Pt(100, 100)},
+		{"crop and split", Options{AutoCrop: true, SplitSpreads: true}, 2, image.Pt(50, 50)},
+		{"resize width", Options{MaxWidth: 100}, 1, image.Pt(100, 50)},
+		{"resize both", Options{MaxWidth: 100, MaxHeight: 20}, 1, image.Pt(40, 20)},
+		{"no upscale", Options{MaxWidth: 400}, 1, image.Pt(200, 100)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := Transform(encodePNG(t, src), tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(out) != tt.pages {
+				t.Fatalf("pages = %d, want %d", len(out), tt.pages)
+			}
+			for _, o := range out {
+				img, format, err := image.Decode(bytes.NewReader(o.Data))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := img.Bounds().Size(); got != tt.want {
+					t.Errorf("size = %v, want %v", got, tt.want)
+				}
+				if Format(format) != o.Format {
+					t.Errorf("encoded as %s, want %s", format, o.Format)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitSpreadOrder(t *testing.T) {
+	// 200x100 spread, black left half and white right half
+	src := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	draw.Draw(src, src.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(src, image.Rect(0, 0, 100, 100), image.NewUniform(color.Black), image.Point{}, draw.Src)
+
+	for _, leftToRight := range []bool{false, true} {
+		halves := splitSpread(src, leftToRight)
+		if len(halves) != 2 {
+			t.Fatalf("leftToRight=%t: %d pages, want 2", leftToRight, len(halves))
+		}
+		first := halves[0]
+		if got := isWhite(first.At(first.Bounds().Min.X, first.Bounds().Min.Y)); got == leftToRight {
+			t.Errorf("leftToRight=%t: first page white = %t", leftToRight, got)
+		}
+	}
+}
+
+func TestEncodeWebP(t *testing.T) {
+	// noise, gradients, flat areas and transparency, to use all the codes
+	src := image.NewNRGBA(image.Rect(0, 0, 300, 200))
+	seed := uint32(1)
+	for y := range 200 {
+		for x := range 300 {
+			seed = seed*1664525 + 1013904223
+			c := color.NRGBA{uint8(x), uint8(y), uint8(x + y), 0xff}
+			switch {
+			case y < 50:
+				c = color.NRGBA{uint8(seed >> 24), uint8(seed >> 16), uint8(seed >> 8), uint8(seed)}
+			case x > 200:
+				c = color.NRGBA{0xff, 0xff, 0xff, 0xff}
+			}
+			src.SetNRGBA(x, y, c)
+		}
+	}
+
+	for _, quality := range []int{100, 50, 1} {
+		var buf bytes.Buffer
+		if err := encodeWebP(&buf, src, quality); err != nil {
+			t.Fatal(err)
+		}
+		img, format, err := image.Decode(&buf)
+		if err != nil {
+			t.Fatalf("quality %d: %s", quality, err)
+		}
+		if format != "webp" || img.Bounds() != src.Bounds() {
+			t.Fatalf("quality %d: decoded %s %v", quality, format, img.Bounds())
+		}
+
+		tolerance := 1 << nearLosslessBits(quality) / 2
+		for y := range 200 {
+			for x := range 300 {
+				want := src.NRGBAAt(x, y)
+				got := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+				if got.A != want.A || diff(got.R, want.R) > tolerance || diff(got.G, want.G) > tolerance || diff(got.B, want.B) > tolerance {
+					t.Fatalf("quality %d: pixel (%d, %d) = %v, want %v", quality, x, y, got, want)
+				}
+			}
+		}
+	}
+}
+
+func diff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
+
+func TestParseFormat(t *testing.T) {
+	for s, want := range map[string]Format{"": FormatOriginal, "jpg": FormatJPEG, "png": FormatPNG, "webp": FormatWebP, "avif": FormatAVIF} {
+		if got, err := ParseFormat(s); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v, want %q", s, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("bmp"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
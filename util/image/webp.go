@@ -0,0 +1,593 @@
+package image
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+	"math/bits"
+	"slices"
+)
+
+// WebP lossless (VP8L) encoder, as there is none in the standard
+// library nor golang.org/x/image. It only uses the subtract green
+// transform and backward references, without color cache nor meta
+// prefix codes, which is enough for the mostly flat manga pages.
+//
+// https://developers.google.com/speed/webp/docs/webp_lossless_bitstream_specification
+
+const (
+	vp8lSignature = 0x2f
+	vp8lMaxSize   = 1 << 14
+
+	// alphabet sizes of the 5 prefix codes
+	vp8lLengthCodes   = 24
+	vp8lGreenSize     = 256 + vp8lLengthCodes
+	vp8lDistanceCodes = 40
+	// distance codes up to 120 are for the 2D neighbourhood
+	vp8lDistanceOffset = 120
+
+	// predictor tiles of 1<<vp8lPredictorBits pixels
+	vp8lPredictorBits = 5
+
+	vp8lMaxCodeLength     = 15
+	vp8lMaxCodeCodeLength = 7
+
+	// backward references search
+	vp8lHashBits  = 16
+	vp8lWindow    = 1 << 16
+	vp8lMaxChain  = 32
+	vp8lMinMatch  = 3
+	vp8lMaxLength = 4096
+)
+
+// vp8lCodeLengthOrder is the order the code length code lengths are written in.
+var vp8lCodeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// nearLosslessBits returns the low bits to round the pixels to for the quality,
+// lossless for 81-100.
+func nearLosslessBits(quality int) int {
+	if quality <= 0 || quality > 100 {
+		return 0
+	}
+	return min((100-quality)/20, 4)
+}
+
+// encodeWebP encodes the image as a lossless WebP, with the
+// pixels rounded to fewer bits for a quality below 81.
+func encodeWebP(w io.Writer, img image.Image, quality int) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if width < 1 || height < 1 || width > vp8lMaxSize || height > vp8lMaxSize {
+		return fmt.Errorf("image size %dx%d out of the WebP range (1-%d)", width, height, vp8lMaxSize)
+	}
+
+	nrgba := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(nrgba, nrgba.Bounds(), img, b.Min, draw.Src)
+
+	round := nearLosslessBits(quality)
+	argb := make([]uint32, width*height)
+	alphaUsed := false
+	for y := range height {
+		row := nrgba.Pix[y*nrgba.Stride : y*nrgba.Stride+width*4]
+		for x := range width {
+			r, g, bl, a := uint32(row[x*4]), uint32(row[x*4+1]), uint32(row[x*4+2]), uint32(row[x*4+3])
+			if round > 0 {
+				r, g, bl = roundBits(r, round), roundBits(g, round), roundBits(bl, round)
+			}
+			if a != 0xff {
+				alphaUsed = true
+			}
+			// subtract green transform
+			r = (r - g) & 0xff
+			bl = (bl - g) & 0xff
+			argb[y*width+x] = a<<24 | r<<16 | g<<8 | bl
+		}
+	}
+
+	bw := &bitWriter{}
+	bw.write(vp8lSignature, 8)
+	bw.write(uint64(width-1), 14)
+	bw.write(uint64(height-1), 14)
+	bw.write(boolBit(alphaUsed), 1)
+	bw.write(0, 3) // version
+
+	// subtract green transform (already applied)
+	bw.write(1, 1)
+	bw.write(2, 2)
+	// predictor transform
+	residuals, modes := predict(argb, width, height)
+	bw.write(1, 1)
+	bw.write(0, 2)
+	bw.write(vp8lPredictorBits-2, 3)
+	writeImage(bw, modes, false)
+	// no more transforms
+	bw.write(0, 1)
+
+	writeImage(bw, residuals, true)
+	data := bw.bytes()
+
+	pad := len(data) & 1
+	header := make([]byte, 20)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(4+8+len(data)+pad))
+	copy(header[8:], "WEBPVP8L")
+	binary.LittleEndian.PutUint32(header[16:], uint32(len(data)))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if pad != 0 {
+		_, err := w.Write([]byte{0})
+		return err
+	}
+	return nil
+}
+
+// writeImage writes the entropy coded image, the main one
+// (not of a transform) also has the meta prefix codes bit.
+func writeImage(bw *bitWriter, argb []uint32, main bool) {
+	tokens := backwardReferences(argb)
+
+	var (
+		green    = make([]int, vp8lGreenSize)
+		red      = make([]int, 256)
+		blue     = make([]int, 256)
+		alpha    = make([]int, 256)
+		distance = make([]int, vp8lDistanceCodes)
+	)
+	for _, t := range tokens {
+		if t.length == 0 {
+			green[t.pixel>>8&0xff]++
+			red[t.pixel>>16&0xff]++
+			blue[t.pixel&0xff]++
+			alpha[t.pixel>>24]++
+			continue
+		}
+		lengthCode, _, _ := prefixEncode(t.length)
+		green[256+lengthCode]++
+		distanceCode, _, _ := prefixEncode(t.distance + vp8lDistanceOffset)
+		distance[distanceCode]++
+	}
+
+	bw.write(0, 1) // no color cache
+	if main {
+		bw.write(0, 1) // no meta prefix codes
+	}
+
+	codes := make([]prefixCode, 5)
+	for i, histogram := range [][]int{green, red, blue, alpha, distance} {
+		codes[i] = newPrefixCode(huffmanLengths(histogram, vp8lMaxCodeLength))
+		codes[i].writeLengths(bw)
+	}
+	greenCode, redCode, blueCode, alphaCode, distanceCode := codes[0], codes[1], codes[2], codes[3], codes[4]
+
+	for _, t := range tokens {
+		if t.length == 0 {
+			greenCode.write(bw, int(t.pixel>>8&0xff))
+			redCode.write(bw, int(t.pixel>>16&0xff))
+			blueCode.write(bw, int(t.pixel&0xff))
+			alphaCode.write(bw, int(t.pixel>>24))
+			continue
+		}
+		code, extraBits, extra := prefixEncode(t.length)
+		greenCode.write(bw, 256+code)
+		bw.write(uint64(extra), extraBits)
+		code, extraBits, extra = prefixEncode(t.distance + vp8lDistanceOffset)
+		distanceCode.write(bw, code)
+		bw.write(uint64(extra), extraBits)
+	}
+}
+
+// vp8lPredictors are the predictor modes tried for each tile.
+var vp8lPredictors = []uint32{1, 2, 7, 11, 12}
+
+// predict returns the residuals of the pixels with the best predictor
+// mode of each tile, along the modes sub-image (in the green channel).
+func predict(argb []uint32, width, height int) (residuals, modes []uint32) {
+	tileSize := 1 << vp8lPredictorBits
+	tilesX := (width + tileSize - 1) / tileSize
+	tilesY := (height + tileSize - 1) / tileSize
+	modes = make([]uint32, tilesX*tilesY)
+	residuals = make([]uint32, len(argb))
+
+	for ty := range tilesY {
+		for tx := range tilesX {
+			minX, minY := tx*tileSize, ty*tileSize
+			maxX, maxY := min(minX+tileSize, width), min(minY+tileSize, height)
+
+			best, bestCost := vp8lPredictors[0], -1
+			for _, mode := range vp8lPredictors {
+				cost := 0
+				for y := minY; y < maxY; y++ {
+					for x := minX; x < maxX; x++ {
+						cost += residualCost(pixelResidual(argb, width, x, y, mode))
+					}
+				}
+				if bestCost < 0 || cost < bestCost {
+					best, bestCost = mode, cost
+				}
+			}
+
+			modes[ty*tilesX+tx] = 0xff000000 | best<<8
+			for y := minY; y < maxY; y++ {
+				for x := minX; x < maxX; x++ {
+					residuals[y*width+x] = pixelResidual(argb, width, x, y, best)
+				}
+			}
+		}
+	}
+	return residuals, modes
+}
+
+// pixelResidual returns the difference of the pixel with its prediction,
+// the first row and column always use the left and top pixels.
+func pixelResidual(argb []uint32, width, x, y int, mode uint32) uint32 {
+	i := y*width + x
+	var prediction uint32
+	switch {
+	case x == 0 && y == 0:
+		prediction = 0xff000000
+	case y == 0:
+		prediction = argb[i-1]
+	case x == 0:
+		prediction = argb[i-width]
+	default:
+		l, t, tl := argb[i-1], argb[i-width], argb[i-width-1]
+		switch mode {
+		case 1:
+			prediction = l
+		case 2:
+			prediction = t
+		case 7:
+			prediction = average2(l, t)
+		case 11:
+			prediction = selectPredictor(l, t, tl)
+		case 12:
+			prediction = clampAddSubtractFull(l, t, tl)
+		}
+	}
+	return subPixels(argb[i], prediction)
+}
+
+// residualCost estimates the cost of the residual, as the sum
+// of the distance to 0 of each channel.
+func residualCost(residual uint32) int {
+	cost := 0
+	for shift := 0; shift < 32; shift += 8 {
+		v := int(int8(residual >> shift))
+		if v < 0 {
+			v = -v
+		}
+		cost += v
+	}
+	return cost
+}
+
+func subPixels(a, b uint32) uint32 {
+	var result uint32
+	for shift := 0; shift < 32; shift += 8 {
+		result |= ((a>>shift - b>>shift) & 0xff) << shift
+	}
+	return result
+}
+
+func average2(a, b uint32) uint32 {
+	return (((a ^ b) & 0xfefefefe) >> 1) + (a & b)
+}
+
+func selectPredictor(l, t, tl uint32) uint32 {
+	distanceL, distanceT := 0, 0
+	for shift := 0; shift < 32; shift += 8 {
+		cl, ct, ctl := int(l>>shift&0xff), int(t>>shift&0xff), int(tl>>shift&0xff)
+		p := cl + ct - ctl
+		distanceL += absInt(p - cl)
+		distanceT += absInt(p - ct)
+	}
+	if distanceL < distanceT {
+		return l
+	}
+	return t
+}
+
+func clampAddSubtractFull(a, b, c uint32) uint32 {
+	var result uint32
+	for shift := 0; shift < 32; shift += 8 {
+		v := int(a>>shift&0xff) + int(b>>shift&0xff) - int(c>>shift&0xff)
+		result |= uint32(min(max(v, 0), 0xff)) << shift
+	}
+	return result
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// roundBits rounds the channel value to a multiple of 1<<n.
+func roundBits(v uint32, n int) uint32 {
+	return min((v+1<<(n-1))&^(1<<n-1), 0xff)
+}
+
+func boolBit(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// token is a literal pixel or a backward reference (non-zero length).
+type token struct {
+	pixel    uint32
+	length   int
+	distance int
+}
+
+// backwardReferences finds the repeated pixel sequences, with hash chains.
+func backwardReferences(argb []uint32) []token {
+	n := len(argb)
+	head := make([]int32, 1<<vp8lHashBits)
+	for i := range head {
+		head[i] = -1
+	}
+	prev := make([]int32, n)
+	hash := func(i int) uint32 {
+		return (argb[i]*0x9E3779B1 ^ argb[i+1]*0x85EBCA77) >> (32 - vp8lHashBits)
+	}
+	insert := func(i int) {
+		if i+1 >= n {
+			return
+		}
+		h := hash(i)
+		prev[i] = head[h]
+		head[h] = int32(i)
+	}
+
+	tokens := make([]token, 0, n/4)
+	for i := 0; i < n; {
+		bestLength, bestDistance := 0, 0
+		if i+vp8lMinMatch <= n {
+			limit := min(vp8lMaxLength, n-i)
+			for candidate, chain := head[hash(i)], 0; candidate >= 0 && i-int(candidate) <= vp8lWindow && chain < vp8lMaxChain; candidate, chain = prev[candidate], chain+1 {
+				c := int(candidate)
+				length := 0
+				for length < limit && argb[c+length] == argb[i+length] {
+					length++
+				}
+				if length > bestLength {
+					bestLength, bestDistance = length, i-c
+					if length == limit {
+						break
+					}
+				}
+			}
+		}
+
+		if bestLength < vp8lMinMatch {
+			tokens = append(tokens, token{pixel: argb[i]})
+			insert(i)
+			i++
+			continue
+		}
+		tokens = append(tokens, token{length: bestLength, distance: bestDistance})
+		for j := i; j < i+bestLength; j++ {
+			insert(j)
+		}
+		i += bestLength
+	}
+	return tokens
+}
+
+// prefixEncode returns the prefix code of the length or distance value (>= 1),
+// along its extra bits.
+func prefixEncode(v int) (code int, extraBits uint, extra int) {
+	d := v - 1
+	if d < 4 {
+		return d, 0, 0
+	}
+	h := bits.Len(uint(d)) - 1
+	second := (d >> (h - 1)) & 1
+	extraBits = uint(h - 1)
+	return 2*h + second, extraBits, d & (1<<extraBits - 1)
+}
+
+// bitWriter writes the bits LSB first.
+type bitWriter struct {
+	buf  []byte
+	acc  uint64
+	nAcc uint
+}
+
+func (w *bitWriter) write(v uint64, n uint) {
+	w.acc |= v << w.nAcc
+	w.nAcc += n
+	for w.nAcc >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.nAcc -= 8
+	}
+}
+
+func (w *bitWriter) bytes() []byte {
+	if w.nAcc > 0 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc, w.nAcc = 0, 0
+	}
+	return w.buf
+}
+
+// prefixCode is a canonical Huffman code. A code with a single
+// symbol is written with 0 bits, as the decoders read it.
+type prefixCode struct {
+	lengths []uint8
+	codes   []uint16
+	single  bool
+}
+
+func newPrefixCode(lengths []uint8) prefixCode {
+	var count [vp8lMaxCodeLength + 1]int
+	used := 0
+	for _, l := range lengths {
+		if l > 0 {
+			count[l]++
+			used++
+		}
+	}
+	var next [vp8lMaxCodeLength + 1]int
+	code := 0
+	for l := 1; l <= vp8lMaxCodeLength; l++ {
+		code = (code + count[l-1]) << 1
+		next[l] = code
+	}
+
+	codes := make([]uint16, len(lengths))
+	for symbol, l := range lengths {
+		if l > 0 {
+			// reversed, as the bits are written LSB first
+			codes[symbol] = bits.Reverse16(uint16(next[l])) >> (16 - l)
+			next[l]++
+		}
+	}
+	return prefixCode{lengths: lengths, codes: codes, single: used == 1}
+}
+
+func (c prefixCode) write(w *bitWriter, symbol int) {
+	if c.single {
+		return
+	}
+	w.write(uint64(c.codes[symbol]), uint(c.lengths[symbol]))
+}
+
+// writeLengths writes the code as a normal code length code, run length
+// encoding the zeros.
+func (c prefixCode) writeLengths(w *bitWriter) {
+	type lengthToken struct {
+		symbol, extra int
+		extraBits     uint
+	}
+	var tokens []lengthToken
+	for i := 0; i < len(c.lengths); {
+		if c.lengths[i] != 0 {
+			tokens = append(tokens, lengthToken{symbol: int(c.lengths[i])})
+			i++
+			continue
+		}
+		run := 0
+		for i+run < len(c.lengths) && c.lengths[i+run] == 0 && run < 138 {
+			run++
+		}
+		switch {
+		case run >= 11:
+			tokens = append(tokens, lengthToken{18, run - 11, 7})
+		case run >= 3:
+			tokens = append(tokens, lengthToken{17, run - 3, 3})
+		default:
+			for range run {
+				tokens = append(tokens, lengthToken{symbol: 0})
+			}
+		}
+		i += run
+	}
+
+	histogram := make([]int, len(vp8lCodeLengthOrder))
+	for _, t := range tokens {
+		histogram[t.symbol]++
+	}
+	lengthCode := newPrefixCode(huffmanLengths(histogram, vp8lMaxCodeCodeLength))
+
+	n := len(vp8lCodeLengthOrder)
+	for n > 4 && lengthCode.lengths[vp8lCodeLengthOrder[n-1]] == 0 {
+		n--
+	}
+	w.write(0, 1) // normal code
+	w.write(uint64(n-4), 4)
+	for _, symbol := range vp8lCodeLengthOrder[:n] {
+		w.write(uint64(lengthCode.lengths[symbol]), 3)
+	}
+	w.write(0, 1) // all the code lengths are written
+	for _, t := range tokens {
+		lengthCode.write(w, t.symbol)
+		w.write(uint64(t.extra), t.extraBits)
+	}
+}
+
+// huffmanLengths returns the code lengths of the symbols frequencies, limited
+// to maxLength by flattening the frequencies. At least one symbol has a length.
+func huffmanLengths(frequencies []int, maxLength int) []uint8 {
+	lengths := make([]uint8, len(frequencies))
+	var used []int
+	for symbol, f := range frequencies {
+		if f > 0 {
+			used = append(used, symbol)
+		}
+	}
+	switch len(used) {
+	case 0:
+		lengths[0] = 1
+		return lengths
+	case 1:
+		lengths[used[0]] = 1
+		return lengths
+	}
+
+	freqs := make([]int, len(frequencies))
+	copy(freqs, frequencies)
+	for {
+		if huffmanDepths(freqs, used, lengths) <= maxLength {
+			return lengths
+		}
+		for _, symbol := range used {
+			freqs[symbol] = freqs[symbol]/2 + 1
+		}
+	}
+}
+
+// huffmanDepths sets the depths of the used symbols in the Huffman tree of
+// the frequencies, returns the max depth.
+func huffmanDepths(freqs, used []int, lengths []uint8) int {
+	type node struct {
+		freq, symbol, left, right int
+	}
+	nodes := make([]node, 0, 2*len(used))
+	for _, symbol := range used {
+		nodes = append(nodes, node{freqs[symbol], symbol, -1, -1})
+	}
+	slices.SortStableFunc(nodes, func(a, b node) int {
+		return a.freq - b.freq
+	})
+
+	// two queues: the sorted leaves and the internal nodes, created in order
+	leaf, internal := 0, len(nodes)
+	pop := func() int {
+		if leaf < len(used) && (internal >= len(nodes) || nodes[leaf].freq <= nodes[internal].freq) {
+			leaf++
+			return leaf - 1
+		}
+		internal++
+		return internal - 1
+	}
+	for range len(used) - 1 {
+		a, b := pop(), pop()
+		nodes = append(nodes, node{nodes[a].freq + nodes[b].freq, -1, a, b})
+	}
+
+	maxDepth := 0
+	var walk func(i, depth int)
+	walk = func(i, depth int) {
+		n := nodes[i]
+		if n.left < 0 {
+			lengths[n.symbol] = uint8(min(depth, 255))
+			maxDepth = max(maxDepth, depth)
+			return
+		}
+		walk(n.left, depth+1)
+		walk(n.right, depth+1)
+	}
+	walk(len(nodes)-1, 0)
+	return maxDepth
+}
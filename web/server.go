@@ -16,8 +16,11 @@ import (
 	"github.com/luevano/libmangal/metadata"
 	"github.com/luevano/libmangal/metadata/anilist"
 	"github.com/luevano/mangal/client"
+	"github.com/luevano/mangal/config"
 	"github.com/luevano/mangal/meta"
+	"github.com/luevano/mangal/metrics"
 	"github.com/luevano/mangal/provider/manager"
+	"github.com/luevano/mangal/util/httprec"
 	"github.com/luevano/mangal/web/api"
 	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/bigcache"
@@ -32,6 +35,7 @@ var _ api.StrictServerInterface = (*Server)(nil)
 
 type Server struct {
 	imageCache  gokv.Store
+	imageClient *http.Client
 	loaders     []libmangal.ProviderLoader
 	loadersByID map[string]libmangal.ProviderLoader
 }
@@ -268,7 +272,7 @@ func (s *Server) GetImage(ctx context.Context, request api.GetImageRequestObject
 		req.Header.Set("Referer", *referer)
 	}
 
-	response, err := http.DefaultClient.Do(req)
+	response, err := s.imageClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -361,7 +365,9 @@ func NewServer() (*echo.Echo, error) {
 		return nil, err
 	}
 
-	server := &Server{}
+	server := &Server{
+		imageClient: httprec.Wrap(&http.Client{}),
+	}
 	store, err := bigcache.NewStore(bigcache.Options{
 		HardMaxCacheSize: 0,
 		Eviction:         0,
@@ -386,6 +392,11 @@ func NewServer() (*echo.Echo, error) {
 	e := echo.New()
 	api.RegisterHandlersWithBaseURL(e, handler, "api")
 
+	e.GET("/healthz", echo.WrapHandler(metrics.HealthHandler()))
+	if config.Metrics.Enabled.Get() {
+		e.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+	}
+
 	e.StaticFS("/", sub)
 	e.HideBanner = true
 